@@ -7,6 +7,7 @@ type CategoryResponseDTO struct {
 	Code        string `json:"code"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	TaxRateID   *int   `json:"tax_rate_id,omitempty"`
 	IsActive    bool   `json:"is_active"`
 	CreatedBy   int    `json:"created_by"`
 
@@ -18,6 +19,7 @@ type CreateCategoryRequest struct {
 	Code        string `json:"code" validate:"required,max=20"`
 	Name        string `json:"name" validate:"required,max=100"`
 	Description string `json:"description" validate:"omitempty"`
+	TaxRateID   *int   `json:"tax_rate_id,omitempty"`
 	IsActive    *bool  `json:"is_active,omitempty"`
 
 	// CreatedBy *int  `json:"created_by,omitempty" db:"created_by"`
@@ -27,6 +29,7 @@ type UpdateCategoryRequest struct {
 	Code        string `json:"code" validate:"required,max=20"`
 	Name        string `json:"name" validate:"required,max=100"`
 	Description string `json:"description" validate:"omitempty"`
+	TaxRateID   *int   `json:"tax_rate_id,omitempty"`
 	IsActive    *bool  `json:"is_active,omitempty"`
 
 	// CreatedBy *int `json:"created_by,omitempty" db:"created_by"`
@@ -0,0 +1,31 @@
+package dto
+
+import "alfdwirhmn/inventory/model"
+
+type CreateCustomerRequest struct {
+	Name    string  `json:"name" validate:"required,max=100"`
+	Phone   *string `json:"phone,omitempty" validate:"omitempty,max=20"`
+	Email   *string `json:"email,omitempty" validate:"omitempty,email,max=100"`
+	Address *string `json:"address,omitempty"`
+	Notes   *string `json:"notes,omitempty"`
+
+	PriceListID *int `json:"price_list_id,omitempty"`
+}
+
+type UpdateCustomerRequest struct {
+	Name     string  `json:"name" validate:"required,max=100"`
+	Phone    *string `json:"phone,omitempty" validate:"omitempty,max=20"`
+	Email    *string `json:"email,omitempty" validate:"omitempty,email,max=100"`
+	Address  *string `json:"address,omitempty"`
+	Notes    *string `json:"notes,omitempty"`
+	IsActive bool    `json:"is_active"`
+
+	PriceListID *int `json:"price_list_id,omitempty"`
+}
+
+// riwayat belanja customer + total & sisa tagihan
+type CustomerSalesResponseDTO struct {
+	Customer model.Customer             `json:"customer"`
+	Summary  model.CustomerSalesSummary `json:"summary"`
+	Sales    []SaleResponseDTO          `json:"sales"`
+}
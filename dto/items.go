@@ -1,22 +1,26 @@
 package dto
 
-import "time"
+import (
+	"alfdwirhmn/inventory/model"
+	"time"
+)
 
 type ItemResponseDTO struct {
 	ID int `json:"id"`
 
 	CategoryID int  `json:"category_id"`
 	RackID     *int `json:"rack_id,omitempty"`
+	TaxRateID  *int `json:"tax_rate_id,omitempty"`
 
 	SKU         string  `json:"sku"`
 	Name        string  `json:"name"`
 	Description *string `json:"description,omitempty"`
 
-	Unit         string  `json:"unit"`
-	Price        float64 `json:"price"`
-	Cost         float64 `json:"cost"`
-	Stock        int     `json:"stock"`
-	MinimumStock int     `json:"minimum_stock"`
+	Unit         string      `json:"unit"`
+	Price        model.Money `json:"price"`
+	Cost         model.Money `json:"cost"`
+	Stock        int         `json:"stock"`
+	MinimumStock int         `json:"minimum_stock"`
 
 	Weight     float64 `json:"weight"`
 	Dimensions *string `json:"dimensions,omitempty"`
@@ -25,21 +29,44 @@ type ItemResponseDTO struct {
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	Locations  []ItemLocationResponseDTO   `json:"locations,omitempty"`
+	Warehouses []WarehouseStockResponseDTO `json:"warehouses,omitempty"`
+}
+
+// stok per rak
+type ItemLocationResponseDTO struct {
+	RackID        int    `json:"rack_id"`
+	RackCode      string `json:"rack_code"`
+	RackName      string `json:"rack_name"`
+	WarehouseID   int    `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+	WarehouseName string `json:"warehouse_name"`
+	Quantity      int    `json:"quantity"`
+}
+
+// stok per gudang (total dari rak-rak di gudang tsb)
+type WarehouseStockResponseDTO struct {
+	WarehouseID   int    `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+	WarehouseName string `json:"warehouse_name"`
+	Quantity      int    `json:"quantity"`
 }
 
 type CreateItemRequest struct {
 	CategoryID int  `json:"category_id" validate:"required"`
 	RackID     *int `json:"rack_id,omitempty"`
+	TaxRateID  *int `json:"tax_rate_id,omitempty"` // kosong = ikut kategori
 
 	SKU         string  `json:"sku" validate:"required,max=50"`
 	Name        string  `json:"name" validate:"required,max=200"`
 	Description *string `json:"description,omitempty"`
 
-	Unit         string  `json:"unit" validate:"omitempty,max=20"`
-	Price        float64 `json:"price" validate:"required,gte=0"`
-	Cost         float64 `json:"cost" validate:"omitempty,gte=0"`
-	Stock        int     `json:"stock" validate:"omitempty,gte=0"`
-	MinimumStock int     `json:"minimum_stock" validate:"omitempty,gte=0"`
+	Unit         string      `json:"unit" validate:"omitempty,max=20"`
+	Price        model.Money `json:"price" validate:"required,gte=0"`
+	Cost         model.Money `json:"cost" validate:"omitempty,gte=0"`
+	Stock        int         `json:"stock" validate:"omitempty,gte=0"` // stok awal, masuk ke rack_id
+	MinimumStock int         `json:"minimum_stock" validate:"omitempty,gte=0"`
 
 	Weight     float64 `json:"weight" validate:"omitempty,gte=0"`
 	Dimensions *string `json:"dimensions,omitempty"`
@@ -47,17 +74,70 @@ type CreateItemRequest struct {
 }
 
 type UpdateItemRequest struct {
-	CategoryID   *int     `json:"category_id" validate:"omitempty"`
-	RackID       *int     `json:"rack_id" validate:"omitempty"`
-	SKU          *string  `json:"sku" validate:"omitempty"`
-	Name         *string  `json:"name" validate:"omitempty"`
-	Description  *string  `json:"description" validate:"omitempty"`
-	Unit         *string  `json:"unit" validate:"omitempty"`
-	Price        *float64 `json:"price" validate:"omitempty,gt=0"`
-	Cost         *float64 `json:"cost" validate:"omitempty,gte=0"`
-	Stock        *int     `json:"stock" validate:"omitempty,gte=0"`
-	MinimumStock *int     `json:"minimum_stock" validate:"omitempty,gte=0"`
-	Weight       *float64 `json:"weight" validate:"omitempty,gte=0"`
-	Dimensions   *string  `json:"dimensions" validate:"omitempty"`
-	IsActive     *bool    `json:"is_active" validate:"omitempty"`
+	CategoryID   *int         `json:"category_id" validate:"omitempty"`
+	RackID       *int         `json:"rack_id" validate:"omitempty"`
+	TaxRateID    *int         `json:"tax_rate_id" validate:"omitempty"`
+	SKU          *string      `json:"sku" validate:"omitempty"`
+	Name         *string      `json:"name" validate:"omitempty"`
+	Description  *string      `json:"description" validate:"omitempty"`
+	Unit         *string      `json:"unit" validate:"omitempty"`
+	Price        *model.Money `json:"price" validate:"omitempty,gt=0"`
+	Cost         *model.Money `json:"cost" validate:"omitempty,gte=0"`
+	MinimumStock *int         `json:"minimum_stock" validate:"omitempty,gte=0"`
+	Weight       *float64     `json:"weight" validate:"omitempty,gte=0"`
+	Dimensions   *string      `json:"dimensions" validate:"omitempty"`
+	IsActive     *bool        `json:"is_active" validate:"omitempty"`
+}
+
+// effective_at kosong = berlaku sekarang, di masa depan = dijadwalkan
+type CreatePriceChangeRequest struct {
+	Price       *model.Money `json:"price,omitempty" validate:"omitempty,gt=0"`
+	Cost        *model.Money `json:"cost,omitempty" validate:"omitempty,gte=0"`
+	EffectiveAt *time.Time   `json:"effective_at,omitempty"`
+	Reason      *string      `json:"reason,omitempty" validate:"omitempty,max=500"`
+}
+
+// stok hanya bisa diubah lewat adjustment, quantity bertanda (+ masuk, - keluar)
+type CreateStockAdjustmentRequest struct {
+	RackID   *int    `json:"rack_id,omitempty"` // default rak utama item
+	Quantity int     `json:"quantity" validate:"required"`
+	Reason   string  `json:"reason" validate:"required,oneof=damage count_correction found expired lost theft other"`
+	Notes    *string `json:"notes,omitempty"`
+}
+
+func ToItemLocationsResponseDTO(locations []model.ItemLocation) ([]ItemLocationResponseDTO, []WarehouseStockResponseDTO) {
+	var racks []ItemLocationResponseDTO
+	var warehouses []WarehouseStockResponseDTO
+
+	index := map[int]int{}
+	for _, loc := range locations {
+		racks = append(racks, ItemLocationResponseDTO{
+			RackID:        loc.RackID,
+			RackCode:      loc.RackCode,
+			RackName:      loc.RackName,
+			WarehouseID:   loc.WarehouseID,
+			WarehouseCode: loc.WarehouseCode,
+			WarehouseName: loc.WarehouseName,
+			Quantity:      loc.Quantity,
+		})
+
+		i, ok := index[loc.WarehouseID]
+		if !ok {
+			i = len(warehouses)
+			index[loc.WarehouseID] = i
+			warehouses = append(warehouses, WarehouseStockResponseDTO{
+				WarehouseID:   loc.WarehouseID,
+				WarehouseCode: loc.WarehouseCode,
+				WarehouseName: loc.WarehouseName,
+			})
+		}
+		warehouses[i].Quantity += loc.Quantity
+	}
+
+	return racks, warehouses
+}
+
+type LowStockFilter struct {
+	WarehouseID int
+	CategoryID  int
 }
@@ -0,0 +1,6 @@
+package dto
+
+type NotificationFilter struct {
+	Type       string
+	UnreadOnly bool
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "alfdwirhmn/inventory/model"
+
+type CreatePriceListRequest struct {
+	Code        string  `json:"code" validate:"required,max=20"`
+	Name        string  `json:"name" validate:"required,max=100"`
+	Description *string `json:"description,omitempty"`
+}
+
+type UpdatePriceListRequest struct {
+	Code        string  `json:"code" validate:"required,max=20"`
+	Name        string  `json:"name" validate:"required,max=100"`
+	Description *string `json:"description,omitempty"`
+	IsActive    bool    `json:"is_active"`
+}
+
+// min_quantity kosong = 1 (harga normal daftar ini)
+type UpsertPriceListItemRequest struct {
+	ItemID      int         `json:"item_id" validate:"required"`
+	MinQuantity int         `json:"min_quantity" validate:"omitempty,gt=0"`
+	Price       model.Money `json:"price" validate:"gte=0"`
+}
+
+// harga dasar item + harga di tiap daftar harga aktif
+type ItemPricesResponseDTO struct {
+	ItemID    int               `json:"item_id"`
+	SKU       string            `json:"sku"`
+	Name      string            `json:"name"`
+	BasePrice model.Money       `json:"base_price"`
+	Prices    []model.ItemPrice `json:"prices"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"alfdwirhmn/inventory/model"
+	"time"
+)
+
+// value: persen (10.00 = 10%) untuk percentage, potongan per unit untuk fixed,
+// diabaikan untuk buy_x_get_y. item_id & category_id kosong = semua item
+type CreatePromotionRequest struct {
+	Code        string      `json:"code" validate:"required,max=30"`
+	Name        string      `json:"name" validate:"required,max=100"`
+	PromoType   string      `json:"promo_type" validate:"required,oneof=percentage fixed buy_x_get_y"`
+	Value       model.Money `json:"value" validate:"gte=0"`
+	BuyQuantity *int        `json:"buy_quantity,omitempty" validate:"omitempty,gt=0"`
+	GetQuantity *int        `json:"get_quantity,omitempty" validate:"omitempty,gt=0"`
+	ItemID      *int        `json:"item_id,omitempty"`
+	CategoryID  *int        `json:"category_id,omitempty"`
+	StartsAt    *time.Time  `json:"starts_at,omitempty"` // default sekarang
+	EndsAt      *time.Time  `json:"ends_at,omitempty"`
+	UsageLimit  *int        `json:"usage_limit,omitempty" validate:"omitempty,gt=0"`
+}
+
+type UpdatePromotionRequest struct {
+	CreatePromotionRequest
+	IsActive bool `json:"is_active"`
+}
@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"alfdwirhmn/inventory/model"
+	"time"
+)
+
+// unit_cost kosong = harga beli terakhir dari supplier ini, fallback cost item
+type PurchaseOrderItemRequest struct {
+	ItemID   int          `json:"item_id" validate:"required"`
+	Quantity int          `json:"quantity" validate:"required,gt=0"`
+	UnitCost *model.Money `json:"unit_cost,omitempty"`
+}
+
+type CreatePurchaseOrderRequest struct {
+	SupplierID   int                        `json:"supplier_id" validate:"required"`
+	WarehouseID  int                        `json:"warehouse_id" validate:"required"`
+	OrderDate    *time.Time                 `json:"order_date,omitempty"`
+	ExpectedDate *time.Time                 `json:"expected_date,omitempty"`
+	Notes        *string                    `json:"notes,omitempty"`
+	Items        []PurchaseOrderItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// gudang tujuan tidak bisa diganti karena ikut di nomor PO
+type UpdatePurchaseOrderRequest struct {
+	SupplierID   int                        `json:"supplier_id" validate:"required"`
+	ExpectedDate *time.Time                 `json:"expected_date,omitempty"`
+	Notes        *string                    `json:"notes,omitempty"`
+	Items        []PurchaseOrderItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// unit_cost kosong = harga di PO
+type ReceiveItemRequest struct {
+	ItemID   int          `json:"item_id" validate:"required"`
+	Quantity int          `json:"quantity" validate:"required,gt=0"`
+	UnitCost *model.Money `json:"unit_cost,omitempty"`
+}
+
+// kosongkan items untuk menerima semua sisa qty
+type ReceivePurchaseOrderRequest struct {
+	RackID       int                  `json:"rack_id" validate:"required"`
+	ReceivedDate *time.Time           `json:"received_date,omitempty"`
+	Notes        *string              `json:"notes,omitempty"`
+	Items        []ReceiveItemRequest `json:"items" validate:"omitempty,dive"`
+}
+
+type PurchaseOrderFilter struct {
+	Status      string
+	SupplierID  int
+	WarehouseID int
+}
+
+// saran reorder satu gudang dijadikan PO draft, satu PO per supplier utama.
+// item_ids kosong = semua item yang disarankan
+type CreateReorderPurchaseRequest struct {
+	WarehouseID int     `json:"warehouse_id" validate:"required"`
+	CategoryID  int     `json:"category_id,omitempty"`
+	SupplierID  int     `json:"supplier_id,omitempty"`
+	Days        int     `json:"days,omitempty" validate:"omitempty,min=1,max=365"`
+	ItemIDs     []int   `json:"item_ids,omitempty"`
+	Notes       *string `json:"notes,omitempty"`
+}
+
+// item tanpa supplier utama tidak dibuatkan PO
+type ReorderPurchaseResponseDTO struct {
+	PurchaseOrders []model.PurchaseOrder     `json:"purchase_orders"`
+	Skipped        []model.ReorderSuggestion `json:"skipped"`
+}
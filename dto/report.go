@@ -0,0 +1,125 @@
+package dto
+
+import (
+	"alfdwirhmn/inventory/model"
+	"time"
+)
+
+// nilai persediaan per gudang pada akhir tanggal as_of
+type InventoryValuationResponseDTO struct {
+	AsOf          string                          `json:"as_of"`
+	CostingMethod string                          `json:"costing_method"`
+	TotalQuantity int                             `json:"total_quantity"`
+	TotalValue    model.Money                     `json:"total_value"`
+	Warehouses    []WarehouseValuationResponseDTO `json:"warehouses"`
+}
+
+type WarehouseValuationResponseDTO struct {
+	WarehouseID   int                        `json:"warehouse_id"`
+	WarehouseCode string                     `json:"warehouse_code"`
+	WarehouseName string                     `json:"warehouse_name"`
+	Quantity      int                        `json:"quantity"`
+	Value         model.Money                `json:"value"`
+	Items         []model.InventoryValuation `json:"items"`
+}
+
+type ReorderFilter struct {
+	WarehouseID int
+	CategoryID  int
+	SupplierID  int
+	Days        int // periode penjualan untuk rata-rata harian
+}
+
+type ReorderReportResponseDTO struct {
+	From          string                    `json:"from"`
+	To            string                    `json:"to"`
+	Days          int                       `json:"days"`
+	TotalItems    int                       `json:"total_items"`
+	EstimatedCost model.Money               `json:"estimated_cost"`
+	Items         []model.ReorderSuggestion `json:"items"`
+}
+
+// periode laporan penjualan
+const (
+	SalesPeriodDay   = "day"
+	SalesPeriodWeek  = "week"
+	SalesPeriodMonth = "month"
+)
+
+// sale_date di [From, To + 1 hari), payment_status kosong = semua kecuali cancelled
+type SalesReportFilter struct {
+	From          time.Time
+	To            time.Time
+	Period        string
+	PaymentStatus string
+	WarehouseID   int
+}
+
+type SalesReportResponseDTO struct {
+	From           string                       `json:"from"`
+	To             string                       `json:"to"`
+	Period         string                       `json:"period"`
+	Summary        model.SalesSummary           `json:"summary"`
+	Periods        []model.SalesPeriod          `json:"periods"`
+	PaymentMethods []model.SalesByPaymentMethod `json:"payment_methods"`
+	Cashiers       []model.SalesByCashier       `json:"cashiers"`
+	Categories     []model.SalesByCategory      `json:"categories"`
+	Items          []model.SalesByItem          `json:"items"`
+}
+
+// laporan margin dikelompokkan per item, kategori atau periode
+const (
+	MarginByItem     = "item"
+	MarginByCategory = "category"
+	MarginByPeriod   = "period"
+)
+
+type MarginReportFilter struct {
+	SalesReportFilter
+	GroupBy    string
+	CategoryID int
+	Sort       string // revenue, cost, margin, margin_percent, quantity, key
+	Desc       bool
+}
+
+type MarginReportResponseDTO struct {
+	From    string              `json:"from"`
+	To      string              `json:"to"`
+	GroupBy string              `json:"group_by"`
+	Period  string              `json:"period,omitempty"`
+	Sort    string              `json:"sort"`
+	Order   string              `json:"order"`
+	Total   model.GrossMargin   `json:"total"`
+	Rows    []model.GrossMargin `json:"rows"`
+}
+
+type InventoryAgingFilter struct {
+	WarehouseID int
+	RackID      int
+	CategoryID  int
+}
+
+type AgingBucketsDTO struct {
+	Quantity    int         `json:"quantity"`
+	Value       model.Money `json:"value"`
+	Days0To30   int         `json:"days_0_30"`
+	Days31To90  int         `json:"days_31_90"`
+	Days91To180 int         `json:"days_91_180"`
+	DaysOver180 int         `json:"days_over_180"`
+}
+
+type InventoryAgingResponseDTO struct {
+	AsOf  string                 `json:"as_of"`
+	Total AgingBucketsDTO        `json:"total"`
+	Items []model.InventoryAging `json:"items"`
+}
+
+// item tanpa mutasi / penjualan selama >= days hari
+type DeadStockResponseDTO struct {
+	AsOf          string                 `json:"as_of"`
+	Days          int                    `json:"days"`
+	TotalItems    int                    `json:"total_items"`
+	TotalQuantity int                    `json:"total_quantity"`
+	TotalValue    model.Money            `json:"total_value"`
+	Items         []model.InventoryAging `json:"items"`
+}
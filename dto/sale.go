@@ -1,43 +1,88 @@
 package dto
 
-import "time"
+import (
+	"alfdwirhmn/inventory/model"
+	"time"
+)
 
 type SaleResponseDTO struct {
 	ID            int    `json:"id"`
 	InvoiceNumber string `json:"invoice_number"`
+	WarehouseID   *int   `json:"warehouse_id,omitempty"`
+	CustomerID    *int   `json:"customer_id,omitempty"`
 
 	CustomerName  *string   `json:"customer_name"`
 	CustomerPhone *string   `json:"customer_phone"`
 	CustomerEmail *string   `json:"customer_email"`
 	SaleDate      time.Time `json:"sale_date"`
 
-	TotalAmount float64 `json:"total_amount"`
-	Discount    float64 `json:"discount"`
-	Tax         float64 `json:"tax"`
-	GrandTotal  float64 `json:"grand_total"`
+	TotalAmount model.Money `json:"total_amount"`
+	Discount    model.Money `json:"discount"`
+	Tax         model.Money `json:"tax"`
+	GrandTotal  model.Money `json:"grand_total"`
 
 	PaymentMethod *string `json:"payment_method"`
 	PaymentStatus string  `json:"payment_status"`
 	Notes         *string `json:"notes"`
 
+	CancelledBy  *int       `json:"cancelled_by,omitempty"`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty"`
+	CancelReason *string    `json:"cancel_reason,omitempty"`
+
 	CreatedBy int       `json:"created_by"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+type SaleItemResponseDTO struct {
+	ID        int         `json:"id"`
+	ItemID    int         `json:"item_id"`
+	SKU       string      `json:"sku"`
+	Name      string      `json:"name"`
+	Quantity  int         `json:"quantity"`
+	UnitPrice model.Money `json:"unit_price"`
+	Discount  model.Money `json:"discount"`
+	Subtotal  model.Money `json:"subtotal"`
+
+	PriceListID       *int        `json:"price_list_id,omitempty"`
+	PromotionID       *int        `json:"promotion_id,omitempty"`
+	PromotionDiscount model.Money `json:"promotion_discount"`
+
+	TaxRateID    *int          `json:"tax_rate_id,omitempty"`
+	TaxRate      model.Percent `json:"tax_rate"`
+	TaxInclusive bool          `json:"tax_inclusive"`
+	TaxAmount    model.Money   `json:"tax_amount"`
+	Total        model.Money   `json:"total"`
+}
+
+// sale header + baris item
+type SaleDetailResponseDTO struct {
+	SaleResponseDTO
+	Items []SaleItemResponseDTO `json:"items"`
+}
+
 type CreateSaleItemRequest struct {
-	ItemID   int     `json:"item_id" validate:"required"`
-	Quantity int     `json:"quantity" validate:"required,gt=0"`
-	Discount float64 `json:"discount" validate:"gte=0"`
+	ItemID   int         `json:"item_id" validate:"required"`
+	Quantity int         `json:"quantity" validate:"required,gt=0"`
+	Discount model.Money `json:"discount" validate:"gte=0"`
 }
 
 type CreateSaleRequest struct {
-	InvoiceNumber string                  `json:"invoice_number" validate:"required"`
-	CustomerName  string                  `json:"customer_name"`
-	CustomerPhone string                  `json:"customer_phone"`
-	CustomerEmail string                  `json:"customer_email"`
-	Discount      float64                 `json:"discount" validate:"gte=0"`
-	Tax           float64                 `json:"tax" validate:"gte=0"`
+	// kosongkan supaya digenerate server, isi hanya untuk import sale lama
+	InvoiceNumber string     `json:"invoice_number" validate:"omitempty,max=50"`
+	SaleDate      *time.Time `json:"sale_date,omitempty"`
+	WarehouseID   *int       `json:"warehouse_id,omitempty"`
+
+	// customer terdaftar, nama/phone/email diambil dari master customer bila kosong
+	CustomerID    *int   `json:"customer_id,omitempty"`
+	CustomerName  string `json:"customer_name"`
+	CustomerPhone string `json:"customer_phone"`
+	CustomerEmail string `json:"customer_email"`
+
+	// daftar harga untuk sale ini, kosong = daftar harga customer (kalau ada)
+	PriceListID *int `json:"price_list_id,omitempty"`
+
+	Discount      model.Money             `json:"discount" validate:"gte=0"` // diskon header, dibagi proporsional ke baris sebelum pajak
 	PaymentMethod string                  `json:"payment_method"`
 	Notes         string                  `json:"notes"`
 	Items         []CreateSaleItemRequest `json:"items" validate:"required,min=1"`
@@ -48,16 +93,128 @@ type UpdateSaleRequest struct {
 	CustomerPhone *string `json:"customer_phone"`
 	CustomerEmail *string `json:"customer_email"`
 
-	// TotalAmount *float64 `json:"total_amount" validate:"omitempty,gte=0"`
-	// Discount    *float64 `json:"discount" validate:"omitempty,gte=0"`
-	// Tax         *float64 `json:"tax" validate:"omitempty,gte=0"`
-	// GrandTotal  *float64 `json:"grand_total" validate:"omitempty,gte=0"`
-
 	PaymentMethod *string `json:"payment_method"`
 	PaymentStatus *string `json:"payment_status" validate:"omitempty,oneof=pending paid cancelled"`
 	Notes         *string `json:"notes"`
 }
 
+// cancel lewat endpoint /cancel supaya stok ikut dikembalikan,
+// paid = catat pembayaran sebesar sisa tagihan
 type UpdateSalePaymentRequest struct {
-	PaymentStatus string `json:"payment_status" validate:"required,oneof=pending paid cancelled"`
+	PaymentStatus string `json:"payment_status" validate:"required,oneof=pending paid"`
+}
+
+type CreateSalePaymentRequest struct {
+	Amount        model.Money `json:"amount" validate:"required,gt=0"`
+	PaymentMethod string      `json:"payment_method" validate:"required,max=50"`
+	Reference     *string     `json:"reference,omitempty" validate:"omitempty,max=100"`
+}
+
+type VoidSalePaymentRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// ringkasan pembayaran sale
+type SalePaymentsResponseDTO struct {
+	SaleID        int                 `json:"sale_id"`
+	GrandTotal    model.Money         `json:"grand_total"`
+	TotalPaid     model.Money         `json:"total_paid"`
+	Outstanding   model.Money         `json:"outstanding"`
+	PaymentStatus string              `json:"payment_status"`
+	Payments      []model.SalePayment `json:"payments"`
+}
+
+type CancelSaleRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+type CreateSaleReturnItemRequest struct {
+	SaleItemID int  `json:"sale_item_id" validate:"required"`
+	Quantity   int  `json:"quantity" validate:"required,gt=0"`
+	RackID     *int `json:"rack_id,omitempty"` // default rak utama item
+}
+
+type CreateSaleReturnRequest struct {
+	Reason       *string                       `json:"reason,omitempty"`
+	RefundMethod *string                       `json:"refund_method,omitempty"`
+	Items        []CreateSaleReturnItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// helper konversi dari model sale untuk response dto sale
+func ToSaleResponseDTO(sale *model.Sale) SaleResponseDTO {
+	resp := SaleResponseDTO{
+		ID:            sale.ID,
+		InvoiceNumber: sale.InvoiceNumber,
+		WarehouseID:   sale.WarehouseID,
+		CustomerID:    sale.CustomerID,
+		CustomerName:  sale.CustomerName,
+		CustomerPhone: sale.CustomerPhone,
+		CustomerEmail: sale.CustomerEmail,
+		SaleDate:      sale.SaleDate,
+		TotalAmount:   sale.TotalAmount,
+		Discount:      sale.Discount,
+		Tax:           sale.Tax,
+		GrandTotal:    sale.GrandTotal,
+		PaymentMethod: sale.PaymentMethod,
+		PaymentStatus: sale.PaymentStatus,
+		Notes:         sale.Notes,
+		CancelledBy:   sale.CancelledBy,
+		CancelledAt:   sale.CancelledAt,
+		CancelReason:  sale.CancelReason,
+		CreatedAt:     sale.CreatedAt,
+		UpdatedAt:     sale.UpdatedAt,
+	}
+
+	if sale.CreatedBy != nil {
+		resp.CreatedBy = *sale.CreatedBy
+	}
+
+	return resp
+}
+
+func ToSaleItemsResponseDTO(items []model.SaleItem) []SaleItemResponseDTO {
+	resp := make([]SaleItemResponseDTO, 0, len(items))
+	for _, it := range items {
+		resp = append(resp, SaleItemResponseDTO{
+			ID:        it.ID,
+			ItemID:    it.ItemID,
+			SKU:       it.SKU,
+			Name:      it.Name,
+			Quantity:  it.Quantity,
+			UnitPrice: it.UnitPrice,
+			Discount:  it.Discount,
+			Subtotal:  it.Subtotal,
+
+			PriceListID:       it.PriceListID,
+			PromotionID:       it.PromotionID,
+			PromotionDiscount: it.PromotionDiscount,
+
+			TaxRateID:    it.TaxRateID,
+			TaxRate:      it.TaxRate,
+			TaxInclusive: it.TaxInclusive,
+			TaxAmount:    it.TaxAmount,
+			Total:        it.Total(),
+		})
+	}
+	return resp
+}
+
+func ToSalePaymentsResponseDTO(sale *model.Sale) SalePaymentsResponseDTO {
+	resp := SalePaymentsResponseDTO{
+		SaleID:        sale.ID,
+		GrandTotal:    sale.GrandTotal,
+		PaymentStatus: sale.PaymentStatus,
+		Payments:      make([]model.SalePayment, 0, len(sale.Payments)),
+	}
+
+	for _, p := range sale.Payments {
+		if p.VoidedAt == nil {
+			resp.TotalPaid += p.Amount
+		}
+		resp.Payments = append(resp.Payments, p)
+	}
+
+	resp.Outstanding = sale.GrandTotal - resp.TotalPaid
+
+	return resp
 }
@@ -0,0 +1,30 @@
+package dto
+
+import "alfdwirhmn/inventory/model"
+
+type CreateSupplierRequest struct {
+	Code             string  `json:"code" validate:"required,max=20"`
+	Name             string  `json:"name" validate:"required,max=100"`
+	ContactPerson    *string `json:"contact_person,omitempty" validate:"omitempty,max=100"`
+	Phone            *string `json:"phone,omitempty" validate:"omitempty,max=20"`
+	Email            *string `json:"email,omitempty" validate:"omitempty,email,max=100"`
+	Address          *string `json:"address,omitempty"`
+	City             *string `json:"city,omitempty" validate:"omitempty,max=50"`
+	PaymentTermsDays int     `json:"payment_terms_days" validate:"gte=0"`
+	Notes            *string `json:"notes,omitempty"`
+}
+
+type UpdateSupplierRequest struct {
+	CreateSupplierRequest
+	IsActive bool `json:"is_active"`
+}
+
+// min_order_quantity kosong = 1
+type UpsertItemSupplierRequest struct {
+	ItemID            int          `json:"item_id" validate:"required"`
+	SupplierSKU       *string      `json:"supplier_sku,omitempty" validate:"omitempty,max=50"`
+	LeadTimeDays      int          `json:"lead_time_days" validate:"gte=0"`
+	MinOrderQuantity  int          `json:"min_order_quantity" validate:"omitempty,gt=0"`
+	LastPurchasePrice *model.Money `json:"last_purchase_price,omitempty"`
+	IsPreferred       bool         `json:"is_preferred"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"alfdwirhmn/inventory/model"
+	"time"
+)
+
+// unit_cost kosong = harga di GRN, atau harga beli terakhir dari supplier kalau tanpa GRN
+type SupplierReturnItemRequest struct {
+	ItemID   int          `json:"item_id" validate:"required"`
+	Quantity int          `json:"quantity" validate:"required,gt=0"`
+	UnitCost *model.Money `json:"unit_cost,omitempty"`
+}
+
+// isi goods_receipt_id untuk retur barang dari penerimaan tertentu,
+// supplier diambil dari PO-nya. tanpa GRN supplier_id wajib diisi
+type CreateSupplierReturnRequest struct {
+	SupplierID     int                         `json:"supplier_id" validate:"required_without=GoodsReceiptID"`
+	GoodsReceiptID *int                        `json:"goods_receipt_id,omitempty"`
+	RackID         int                         `json:"rack_id" validate:"required"`
+	ReturnDate     *time.Time                  `json:"return_date,omitempty"`
+	Reason         *string                     `json:"reason,omitempty"`
+	Notes          *string                     `json:"notes,omitempty"`
+	Items          []SupplierReturnItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+type AddSupplierCreditRequest struct {
+	Amount     model.Money `json:"amount" validate:"gt=0"`
+	Reference  *string     `json:"reference,omitempty" validate:"omitempty,max=100"`
+	CreditedAt *time.Time  `json:"credited_at,omitempty"`
+	Notes      *string     `json:"notes,omitempty"`
+}
+
+type SupplierReturnFilter struct {
+	SupplierID   int
+	CreditStatus string
+}
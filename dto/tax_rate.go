@@ -0,0 +1,18 @@
+package dto
+
+import "alfdwirhmn/inventory/model"
+
+type CreateTaxRateRequest struct {
+	Code        string        `json:"code" validate:"required,max=20"`
+	Name        string        `json:"name" validate:"required,max=100"`
+	Rate        model.Percent `json:"rate" validate:"gte=0,lte=10000"` // persen, 11.00 = 11%
+	IsInclusive bool          `json:"is_inclusive"`
+}
+
+type UpdateTaxRateRequest struct {
+	Code        string        `json:"code" validate:"required,max=20"`
+	Name        string        `json:"name" validate:"required,max=100"`
+	Rate        model.Percent `json:"rate" validate:"gte=0,lte=10000"`
+	IsInclusive bool          `json:"is_inclusive"`
+	IsActive    bool          `json:"is_active"`
+}
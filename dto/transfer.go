@@ -0,0 +1,30 @@
+package dto
+
+type CreateTransferItemRequest struct {
+	ItemID   int `json:"item_id" validate:"required"`
+	Quantity int `json:"quantity" validate:"required,gt=0"`
+}
+
+type CreateTransferRequest struct {
+	SourceRackID      int                         `json:"source_rack_id" validate:"required"`
+	DestinationRackID int                         `json:"destination_rack_id" validate:"required,nefield=SourceRackID"`
+	Notes             *string                     `json:"notes,omitempty"`
+	Items             []CreateTransferItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// kosongkan items untuk menerima semua sisa qty
+type ReceiveTransferRequest struct {
+	Items []CreateTransferItemRequest `json:"items" validate:"omitempty,dive"`
+}
+
+// tutup transfer yang belum diterima penuh, sisa qty yang belum diterima dianggap hilang
+type CloseTransferRequest struct {
+	Reason string  `json:"reason" validate:"required,oneof=damage lost theft other"`
+	Notes  *string `json:"notes,omitempty"`
+}
+
+type TransferFilter struct {
+	Status                 string
+	SourceWarehouseID      int
+	DestinationWarehouseID int
+}
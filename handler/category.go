@@ -67,6 +67,7 @@ func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 			Code:        category.Code,
 			Name:        category.Name,
 			Description: category.Description,
+			TaxRateID:   category.TaxRateID,
 			IsActive:    category.IsActive,
 			CreatedBy:   *category.CreatedBy,
 			CreatedAt:   category.CreatedAt,
@@ -127,6 +128,7 @@ func (h *CategoryHandler) DetailById(w http.ResponseWriter, r *http.Request) {
 		Code:        res.Code,
 		Name:        res.Name,
 		Description: res.Description,
+		TaxRateID:   res.TaxRateID,
 		IsActive:    res.IsActive,
 		CreatedBy:   *res.CreatedBy,
 		CreatedAt:   res.CreatedAt,
@@ -176,6 +178,7 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 			Code:        category.Code,
 			Name:        category.Name,
 			Description: category.Description,
+			TaxRateID:   category.TaxRateID,
 			IsActive:    category.IsActive,
 			CreatedBy:   *category.CreatedBy,
 			CreatedAt:   category.CreatedAt,
@@ -17,6 +17,17 @@ type Container struct {
 	Racks     *RacksHandler
 	Items     *ItemsHandler
 	Sale      *SaleHandler
+	Transfer  *TransferHandler
+	Purchase  *PurchaseHandler
+	Customer  *CustomerHandler
+	Supplier  *SupplierHandler
+	TaxRate   *TaxRateHandler
+	Promotion *PromotionHandler
+	PriceList *PriceListHandler
+	Report    *ReportHandler
+
+	SupplierReturn *SupplierReturnHandler
+	Notification   *NotificationHandler
 
 	Repositories *repository.Container
 }
@@ -38,6 +49,17 @@ func NewContainer(
 		Racks:     NewRacksHandler(svc.Racks, validate, log, conf),
 		Items:     NewItemsHandler(svc.Items, validate, log, conf),
 		Sale:      NewSaleHandler(svc.Sale, validate, log, conf),
+		Transfer:  NewTransferHandler(svc.Transfer, validate, log, conf),
+		Purchase:  NewPurchaseHandler(svc.Purchase, validate, log, conf),
+		Customer:  NewCustomerHandler(svc.Customer, validate, log, conf),
+		Supplier:  NewSupplierHandler(svc.Supplier, validate, log, conf),
+		TaxRate:   NewTaxRateHandler(svc.TaxRate, validate, log, conf),
+		Promotion: NewPromotionHandler(svc.Promotion, validate, log, conf),
+		PriceList: NewPriceListHandler(svc.PriceList, validate, log, conf),
+		Report:    NewReportHandler(svc.Report, log, conf),
+
+		SupplierReturn: NewSupplierReturnHandler(svc.SupplierReturn, validate, log, conf),
+		Notification:   NewNotificationHandler(svc.Notification, log, conf),
 
 		Repositories: repo,
 	}
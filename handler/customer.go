@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/middleware"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/service"
+	"alfdwirhmn/inventory/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+type CustomerHandler struct {
+	CustomerService service.CustomerService
+	Validator       *validator.Validate
+	Logger          *zap.Logger
+
+	Config utils.Configuration
+}
+
+func NewCustomerHandler(service service.CustomerService, validator *validator.Validate, logger *zap.Logger, config utils.Configuration) *CustomerHandler {
+	return &CustomerHandler{
+		CustomerService: service,
+		Validator:       validator,
+		Logger:          logger,
+		Config:          config,
+	}
+}
+
+func (h *CustomerHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req dto.CreateCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	customer, err := h.CustomerService.Create(r.Context(), user, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("customer created",
+		zap.Int("customer_id", customer.ID),
+		zap.Int("created_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusCreated, "customer created successfully", customer)
+}
+
+// ?q= cari berdasarkan nama, phone atau email
+func (h *CustomerHandler) Lists(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		utils.JSONError(w, http.StatusBadRequest, "invalid page", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(h.Config.Limit)
+	if err != nil {
+		utils.JSONError(w, http.StatusInternalServerError, "invalid limit config", nil)
+		return
+	}
+
+	customers, pagination, err := h.CustomerService.FindAll(r.Context(), r.URL.Query().Get("q"), page, limit)
+	if err != nil {
+		h.Logger.Error("failed get customer", zap.Error(err))
+		utils.JSONError(w, http.StatusInternalServerError, "failed", nil)
+		return
+	}
+
+	utils.JSONWithPagination(w, http.StatusOK, "succesfully get customer data", customers, *pagination)
+}
+
+func (h *CustomerHandler) DetailById(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid customer id", nil)
+		return
+	}
+
+	customer, err := h.CustomerService.FindByID(r.Context(), user, id)
+	if err != nil {
+		utils.JSONError(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get customer detail", customer)
+}
+
+func (h *CustomerHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid customer id", nil)
+		return
+	}
+
+	var req dto.UpdateCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	customer, err := h.CustomerService.Update(r.Context(), user, id, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("customer updated successfully",
+		zap.Int("customer_id", customer.ID),
+		zap.String("updated_by", user.Role),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, "customer updated successfully", customer)
+}
+
+func (h *CustomerHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid customer id", nil)
+		return
+	}
+
+	if err := h.CustomerService.Delete(r.Context(), user, id); err != nil {
+		utils.JSONError(w, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("customer deleted successfully",
+		zap.Int("customer_id", id),
+		zap.String("deleted_by", user.Role),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, "customer deleted successfully", id)
+}
+
+func (h *CustomerHandler) Sales(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid customer id", nil)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		utils.JSONError(w, http.StatusBadRequest, "invalid page", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(h.Config.Limit)
+	if err != nil {
+		utils.JSONError(w, http.StatusInternalServerError, "invalid limit config", nil)
+		return
+	}
+
+	res, pagination, err := h.CustomerService.Sales(r.Context(), user, id, page, limit)
+	if err != nil {
+		utils.JSONError(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.JSONWithPagination(w, http.StatusOK, "successfully get customer sales", res, *pagination)
+}
@@ -66,6 +66,7 @@ func (h *ItemsHandler) Create(w http.ResponseWriter, r *http.Request) {
 		dto.ItemResponseDTO{
 			ID:           items.ID,
 			CategoryID:   items.CategoryID,
+			TaxRateID:    items.TaxRateID,
 			SKU:          items.SKU,
 			Name:         items.Name,
 			Description:  items.Description,
@@ -130,9 +131,13 @@ func (h *ItemsHandler) DetailById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	locations, warehouses := dto.ToItemLocationsResponseDTO(res.Locations)
+
 	utils.JSONSuccess(w, http.StatusOK, "succesfully get item detail", dto.ItemResponseDTO{
+		ID:           res.ID,
 		CategoryID:   res.CategoryID,
 		RackID:       res.RackID,
+		TaxRateID:    res.TaxRateID,
 		SKU:          res.SKU,
 		Name:         res.Name,
 		Description:  res.Description,
@@ -147,6 +152,8 @@ func (h *ItemsHandler) DetailById(w http.ResponseWriter, r *http.Request) {
 		CreatedBy:    *res.CreatedBy,
 		CreatedAt:    res.CreatedAt,
 		UpdatedAt:    res.UpdatedAt,
+		Locations:    locations,
+		Warehouses:   warehouses,
 	})
 }
 
@@ -196,6 +203,7 @@ func (h *ItemsHandler) Update(w http.ResponseWriter, r *http.Request) {
 			ID:           item.ID,
 			CategoryID:   item.CategoryID,
 			RackID:       item.RackID,
+			TaxRateID:    item.TaxRateID,
 			SKU:          item.SKU,
 			Name:         item.Name,
 			Description:  item.Description,
@@ -240,3 +248,222 @@ func (h *ItemsHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	utils.JSONSuccess(w, http.StatusOK, "item deleted successfully", id)
 }
+
+func (h *ItemsHandler) Adjust(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid item id", nil)
+		return
+	}
+
+	var req dto.CreateStockAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	movement, err := h.ItemsService.Adjust(r.Context(), user, id, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("stock adjusted",
+		zap.Int("item_id", id),
+		zap.Int("quantity", req.Quantity),
+		zap.String("reason", req.Reason),
+		zap.Int("adjusted_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusCreated, "stock adjusted successfully", movement)
+}
+
+func (h *ItemsHandler) Movements(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid item id", nil)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		utils.JSONError(w, http.StatusBadRequest, "invalid page", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(h.Config.Limit)
+	if err != nil {
+		utils.JSONError(w, http.StatusInternalServerError, "invalid limit config", nil)
+		return
+	}
+
+	movements, pagination, err := h.ItemsService.Movements(r.Context(), user, id, page, limit)
+	if err != nil {
+		h.Logger.Error("failed get stock movements", zap.Error(err))
+		utils.JSONError(w, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	utils.JSONWithPagination(w, http.StatusOK, "successfully get stock movements", movements, *pagination)
+}
+
+func (h *ItemsHandler) LowStock(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		utils.JSONError(w, http.StatusBadRequest, "invalid page", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(h.Config.Limit)
+	if err != nil {
+		utils.JSONError(w, http.StatusInternalServerError, "invalid limit config", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := dto.LowStockFilter{
+		WarehouseID: utils.StringToInt(query.Get("warehouse_id")),
+		CategoryID:  utils.StringToInt(query.Get("category_id")),
+	}
+
+	items, pagination, err := h.ItemsService.LowStock(r.Context(), user, filter, page, limit)
+	if err != nil {
+		h.Logger.Error("failed get low stock items", zap.Error(err))
+		utils.JSONError(w, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	utils.JSONWithPagination(w, http.StatusOK, "successfully get low stock items", items, *pagination)
+}
+
+func (h *ItemsHandler) PriceHistory(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid item id", nil)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		utils.JSONError(w, http.StatusBadRequest, "invalid page", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(h.Config.Limit)
+	if err != nil {
+		utils.JSONError(w, http.StatusInternalServerError, "invalid limit config", nil)
+		return
+	}
+
+	changes, pagination, err := h.ItemsService.PriceHistory(r.Context(), user, id, page, limit)
+	if err != nil {
+		h.Logger.Error("failed get price history", zap.Error(err))
+		utils.JSONError(w, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	utils.JSONWithPagination(w, http.StatusOK, "successfully get price history", changes, *pagination)
+}
+
+func (h *ItemsHandler) SchedulePriceChange(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid item id", nil)
+		return
+	}
+
+	var req dto.CreatePriceChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	change, err := h.ItemsService.SchedulePriceChange(r.Context(), user, id, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("item price change saved",
+		zap.Int("item_id", id),
+		zap.Int("change_id", change.ID),
+		zap.String("status", change.Status),
+		zap.Time("effective_at", change.EffectiveAt),
+		zap.Int("created_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusCreated, "price change "+change.Status, change)
+}
+
+func (h *ItemsHandler) CancelPriceChange(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid item id", nil)
+		return
+	}
+
+	changeID, err := strconv.Atoi(chi.URLParam(r, "changeId"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid price change id", nil)
+		return
+	}
+
+	if err := h.ItemsService.CancelPriceChange(r.Context(), user, id, changeID); err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("scheduled price change cancelled",
+		zap.Int("item_id", id),
+		zap.Int("change_id", changeID),
+		zap.Int("cancelled_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, "price change cancelled successfully", changeID)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/middleware"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/service"
+	"alfdwirhmn/inventory/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type NotificationHandler struct {
+	NotificationService service.NotificationService
+	Logger              *zap.Logger
+
+	Config utils.Configuration
+}
+
+func NewNotificationHandler(service service.NotificationService, logger *zap.Logger, config utils.Configuration) *NotificationHandler {
+	return &NotificationHandler{
+		NotificationService: service,
+		Logger:              logger,
+		Config:              config,
+	}
+}
+
+func (h *NotificationHandler) Lists(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		utils.JSONError(w, http.StatusBadRequest, "invalid page", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(h.Config.Limit)
+	if err != nil {
+		utils.JSONError(w, http.StatusInternalServerError, "invalid limit config", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := dto.NotificationFilter{
+		Type:       query.Get("type"),
+		UnreadOnly: query.Get("unread") == "true",
+	}
+
+	notifications, pagination, err := h.NotificationService.FindAll(r.Context(), user, filter, page, limit)
+	if err != nil {
+		h.Logger.Error("failed get notifications", zap.Error(err))
+		utils.JSONError(w, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	utils.JSONWithPagination(w, http.StatusOK, "successfully get notifications", notifications, *pagination)
+}
+
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid notification id", nil)
+		return
+	}
+
+	notification, err := h.NotificationService.MarkRead(r.Context(), user, id)
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "notification marked as read", notification)
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/middleware"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/service"
+	"alfdwirhmn/inventory/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+type PriceListHandler struct {
+	PriceListService service.PriceListService
+	Validator        *validator.Validate
+	Logger           *zap.Logger
+
+	Config utils.Configuration
+}
+
+func NewPriceListHandler(service service.PriceListService, validator *validator.Validate, logger *zap.Logger, config utils.Configuration) *PriceListHandler {
+	return &PriceListHandler{
+		PriceListService: service,
+		Validator:        validator,
+		Logger:           logger,
+		Config:           config,
+	}
+}
+
+func (h *PriceListHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req dto.CreatePriceListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	list, err := h.PriceListService.Create(r.Context(), user, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("price list created",
+		zap.Int("price_list_id", list.ID),
+		zap.Int("created_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusCreated, "price list created successfully", list)
+}
+
+func (h *PriceListHandler) Lists(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		utils.JSONError(w, http.StatusBadRequest, "invalid page", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(h.Config.Limit)
+	if err != nil {
+		utils.JSONError(w, http.StatusInternalServerError, "invalid limit config", nil)
+		return
+	}
+
+	lists, pagination, err := h.PriceListService.FindAll(r.Context(), page, limit)
+	if err != nil {
+		h.Logger.Error("failed get price list", zap.Error(err))
+		utils.JSONError(w, http.StatusInternalServerError, "failed", nil)
+		return
+	}
+
+	utils.JSONWithPagination(w, http.StatusOK, "succesfully get price list data", lists, *pagination)
+}
+
+func (h *PriceListHandler) DetailById(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid price list id", nil)
+		return
+	}
+
+	list, err := h.PriceListService.FindByID(r.Context(), user, id)
+	if err != nil {
+		utils.JSONError(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get price list detail", list)
+}
+
+func (h *PriceListHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid price list id", nil)
+		return
+	}
+
+	var req dto.UpdatePriceListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	list, err := h.PriceListService.Update(r.Context(), user, id, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("price list updated successfully",
+		zap.Int("price_list_id", list.ID),
+		zap.String("updated_by", user.Role),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, "price list updated successfully", list)
+}
+
+func (h *PriceListHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid price list id", nil)
+		return
+	}
+
+	if err := h.PriceListService.Delete(r.Context(), user, id); err != nil {
+		utils.JSONError(w, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("price list deleted successfully",
+		zap.Int("price_list_id", id),
+		zap.String("deleted_by", user.Role),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, "price list deleted successfully", id)
+}
+
+func (h *PriceListHandler) UpsertItem(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid price list id", nil)
+		return
+	}
+
+	var req dto.UpsertPriceListItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	item, err := h.PriceListService.UpsertItem(r.Context(), user, id, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("price list item saved",
+		zap.Int("price_list_id", id),
+		zap.Int("item_id", item.ItemID),
+		zap.Int("min_quantity", item.MinQuantity),
+		zap.Stringer("price", item.Price),
+		zap.Int("updated_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, "price list item saved successfully", item)
+}
+
+func (h *PriceListHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid price list id", nil)
+		return
+	}
+
+	priceItemID, err := strconv.Atoi(chi.URLParam(r, "priceItemId"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid price list item id", nil)
+		return
+	}
+
+	if err := h.PriceListService.DeleteItem(r.Context(), user, id, priceItemID); err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "price list item deleted successfully", priceItemID)
+}
+
+func (h *PriceListHandler) ItemPrices(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid item id", nil)
+		return
+	}
+
+	prices, err := h.PriceListService.ItemPrices(r.Context(), user, id)
+	if err != nil {
+		utils.JSONError(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get item prices", prices)
+}
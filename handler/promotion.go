@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/middleware"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/service"
+	"alfdwirhmn/inventory/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+type PromotionHandler struct {
+	PromotionService service.PromotionService
+	Validator        *validator.Validate
+	Logger           *zap.Logger
+
+	Config utils.Configuration
+}
+
+func NewPromotionHandler(service service.PromotionService, validator *validator.Validate, logger *zap.Logger, config utils.Configuration) *PromotionHandler {
+	return &PromotionHandler{
+		PromotionService: service,
+		Validator:        validator,
+		Logger:           logger,
+		Config:           config,
+	}
+}
+
+func (h *PromotionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req dto.CreatePromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	promo, err := h.PromotionService.Create(r.Context(), user, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("promotion created",
+		zap.Int("promotion_id", promo.ID),
+		zap.Int("created_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusCreated, "promotion created successfully", promo)
+}
+
+func (h *PromotionHandler) Lists(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		utils.JSONError(w, http.StatusBadRequest, "invalid page", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(h.Config.Limit)
+	if err != nil {
+		utils.JSONError(w, http.StatusInternalServerError, "invalid limit config", nil)
+		return
+	}
+
+	// ?active=true hanya promo yang sedang berjalan
+	activeOnly := r.URL.Query().Get("active") == "true"
+
+	promos, pagination, err := h.PromotionService.FindAll(r.Context(), activeOnly, page, limit)
+	if err != nil {
+		h.Logger.Error("failed get promotion", zap.Error(err))
+		utils.JSONError(w, http.StatusInternalServerError, "failed", nil)
+		return
+	}
+
+	utils.JSONWithPagination(w, http.StatusOK, "succesfully get promotion data", promos, *pagination)
+}
+
+func (h *PromotionHandler) DetailById(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid promotion id", nil)
+		return
+	}
+
+	promo, err := h.PromotionService.FindByID(r.Context(), user, id)
+	if err != nil {
+		utils.JSONError(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get promotion detail", promo)
+}
+
+func (h *PromotionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid promotion id", nil)
+		return
+	}
+
+	var req dto.UpdatePromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	promo, err := h.PromotionService.Update(r.Context(), user, id, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("promotion updated successfully",
+		zap.Int("promotion_id", promo.ID),
+		zap.String("updated_by", user.Role),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, "promotion updated successfully", promo)
+}
+
+func (h *PromotionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid promotion id", nil)
+		return
+	}
+
+	if err := h.PromotionService.Delete(r.Context(), user, id); err != nil {
+		utils.JSONError(w, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("promotion deleted successfully",
+		zap.Int("promotion_id", id),
+		zap.String("deleted_by", user.Role),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, "promotion deleted successfully", id)
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/middleware"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/service"
+	"alfdwirhmn/inventory/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+type PurchaseHandler struct {
+	PurchaseService service.PurchaseService
+	Validator       *validator.Validate
+	Logger          *zap.Logger
+
+	Config utils.Configuration
+}
+
+func NewPurchaseHandler(service service.PurchaseService, validator *validator.Validate, logger *zap.Logger, config utils.Configuration) *PurchaseHandler {
+	return &PurchaseHandler{
+		PurchaseService: service,
+		Validator:       validator,
+		Logger:          logger,
+		Config:          config,
+	}
+}
+
+func (h *PurchaseHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req dto.CreatePurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	po, err := h.PurchaseService.Create(r.Context(), user, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("purchase order created",
+		zap.Int("purchase_order_id", po.ID),
+		zap.String("po_number", po.PONumber),
+		zap.Stringer("total_amount", po.TotalAmount),
+		zap.Int("created_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusCreated, "purchase order created successfully", po)
+}
+
+func (h *PurchaseHandler) CreateFromReorder(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req dto.CreateReorderPurchaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	res, err := h.PurchaseService.CreateFromReorder(r.Context(), user, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("purchase orders created from reorder suggestions",
+		zap.Int("warehouse_id", req.WarehouseID),
+		zap.Int("purchase_orders", len(res.PurchaseOrders)),
+		zap.Int("skipped_items", len(res.Skipped)),
+		zap.Int("created_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusCreated, "purchase orders created successfully", res)
+}
+
+// filter ?status= ?supplier_id= ?warehouse_id=
+func (h *PurchaseHandler) Lists(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		utils.JSONError(w, http.StatusBadRequest, "invalid page", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(h.Config.Limit)
+	if err != nil {
+		utils.JSONError(w, http.StatusInternalServerError, "invalid limit config", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := dto.PurchaseOrderFilter{
+		Status:      query.Get("status"),
+		SupplierID:  utils.StringToInt(query.Get("supplier_id")),
+		WarehouseID: utils.StringToInt(query.Get("warehouse_id")),
+	}
+
+	orders, pagination, err := h.PurchaseService.FindAll(r.Context(), user, filter, page, limit)
+	if err != nil {
+		h.Logger.Error("failed get purchase orders", zap.Error(err))
+		utils.JSONError(w, http.StatusInternalServerError, "failed", nil)
+		return
+	}
+
+	utils.JSONWithPagination(w, http.StatusOK, "successfully get purchase order data", orders, *pagination)
+}
+
+func (h *PurchaseHandler) DetailById(w http.ResponseWriter, r *http.Request) {
+	h.handleTransition(w, r, "successfully get purchase order detail", func(ctx context.Context, usr *model.User, id int) (*model.PurchaseOrder, error) {
+		return h.PurchaseService.FindByID(ctx, usr, id)
+	})
+}
+
+func (h *PurchaseHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdatePurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	h.handleTransition(w, r, "purchase order updated", func(ctx context.Context, usr *model.User, id int) (*model.PurchaseOrder, error) {
+		return h.PurchaseService.Update(ctx, usr, id, req)
+	})
+}
+
+func (h *PurchaseHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.handleTransition(w, r, "purchase order approved", h.PurchaseService.Approve)
+}
+
+func (h *PurchaseHandler) Close(w http.ResponseWriter, r *http.Request) {
+	h.handleTransition(w, r, "purchase order closed", h.PurchaseService.Close)
+}
+
+func (h *PurchaseHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.handleTransition(w, r, "purchase order cancelled", h.PurchaseService.Cancel)
+}
+
+func (h *PurchaseHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid purchase order id", nil)
+		return
+	}
+
+	var req dto.ReceivePurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	receipt, err := h.PurchaseService.Receive(r.Context(), user, id, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("goods received",
+		zap.Int("purchase_order_id", id),
+		zap.Int("goods_receipt_id", receipt.ID),
+		zap.String("grn_number", receipt.GRNNumber),
+		zap.Stringer("total_amount", receipt.TotalAmount),
+		zap.Int("received_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusCreated, "goods received successfully", receipt)
+}
+
+// helper untuk endpoint /purchase-orders/{id}/... yang hanya butuh user & id
+func (h *PurchaseHandler) handleTransition(w http.ResponseWriter, r *http.Request, message string, fn func(ctx context.Context, usr *model.User, id int) (*model.PurchaseOrder, error)) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid purchase order id", nil)
+		return
+	}
+
+	po, err := fn(r.Context(), user, id)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info(message,
+		zap.Int("purchase_order_id", po.ID),
+		zap.String("status", po.Status),
+		zap.Int("user_id", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, message, po)
+}
@@ -0,0 +1,325 @@
+package handler
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/middleware"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/service"
+	"alfdwirhmn/inventory/utils"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ReportHandler struct {
+	ReportService service.ReportService
+	Logger        *zap.Logger
+
+	Config utils.Configuration
+}
+
+func NewReportHandler(service service.ReportService, logger *zap.Logger, config utils.Configuration) *ReportHandler {
+	return &ReportHandler{
+		ReportService: service,
+		Logger:        logger,
+		Config:        config,
+	}
+}
+
+// ?as_of=YYYY-MM-DD (default hari ini) & ?warehouse_id=
+func (h *ReportHandler) InventoryValuation(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	query := r.URL.Query()
+
+	asOf, err := utils.ParseDate(query.Get("as_of"), time.Now())
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid as_of, use YYYY-MM-DD", nil)
+		return
+	}
+
+	var warehouseID *int
+	if id := utils.StringToInt(query.Get("warehouse_id")); id > 0 {
+		warehouseID = &id
+	}
+
+	report, err := h.ReportService.InventoryValuation(r.Context(), user, warehouseID, asOf)
+	if err != nil {
+		h.Logger.Error("failed get inventory valuation", zap.Error(err))
+		utils.JSONError(w, reportErrorStatus(err), err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get inventory valuation", report)
+}
+
+// ?days= (default 30) ?warehouse_id= ?category_id= ?supplier_id=
+func (h *ReportHandler) ReorderSuggestions(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := dto.ReorderFilter{
+		WarehouseID: utils.StringToInt(query.Get("warehouse_id")),
+		CategoryID:  utils.StringToInt(query.Get("category_id")),
+		SupplierID:  utils.StringToInt(query.Get("supplier_id")),
+	}
+
+	if days := query.Get("days"); days != "" {
+		filter.Days = utils.StringToInt(days)
+		if filter.Days < 1 || filter.Days > 365 {
+			utils.JSONError(w, http.StatusBadRequest, "invalid days, use 1-365", nil)
+			return
+		}
+	}
+
+	report, err := h.ReportService.ReorderSuggestions(r.Context(), user, filter)
+	if err != nil {
+		h.Logger.Error("failed get reorder suggestions", zap.Error(err))
+		utils.JSONError(w, reportErrorStatus(err), err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get reorder suggestions", report)
+}
+
+// ?from= ?to= (YYYY-MM-DD, default awal bulan s/d hari ini) ?period=day|week|month
+// ?payment_status= ?warehouse_id=
+func (h *ReportHandler) Sales(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	query := r.URL.Query()
+
+	from, to, ok := parseReportRange(w, query)
+	if !ok {
+		return
+	}
+
+	period := query.Get("period")
+	switch period {
+	case "", dto.SalesPeriodDay, dto.SalesPeriodWeek, dto.SalesPeriodMonth:
+	default:
+		utils.JSONError(w, http.StatusBadRequest, "invalid period, use day, week or month", nil)
+		return
+	}
+
+	filter := dto.SalesReportFilter{
+		From:          from,
+		To:            to,
+		Period:        period,
+		PaymentStatus: query.Get("payment_status"),
+		WarehouseID:   utils.StringToInt(query.Get("warehouse_id")),
+	}
+
+	report, err := h.ReportService.Sales(r.Context(), user, filter)
+	if err != nil {
+		h.Logger.Error("failed get sales report", zap.Error(err))
+		utils.JSONError(w, reportErrorStatus(err), err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get sales report", report)
+}
+
+// ?from= ?to= seperti laporan penjualan, ?group_by=item|category|period ?period=day|week|month
+// ?category_id= ?sort=margin|margin_percent|revenue|cost|quantity|key ?order=asc|desc ?format=csv
+func (h *ReportHandler) GrossMargin(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	query := r.URL.Query()
+
+	from, to, ok := parseReportRange(w, query)
+	if !ok {
+		return
+	}
+
+	filter := dto.MarginReportFilter{
+		SalesReportFilter: dto.SalesReportFilter{
+			From:          from,
+			To:            to,
+			Period:        query.Get("period"),
+			PaymentStatus: query.Get("payment_status"),
+			WarehouseID:   utils.StringToInt(query.Get("warehouse_id")),
+		},
+		GroupBy:    query.Get("group_by"),
+		CategoryID: utils.StringToInt(query.Get("category_id")),
+		Sort:       query.Get("sort"),
+		Desc:       query.Get("order") != "asc",
+	}
+
+	switch filter.GroupBy {
+	case "", dto.MarginByItem, dto.MarginByCategory, dto.MarginByPeriod:
+	default:
+		utils.JSONError(w, http.StatusBadRequest, "invalid group_by, use item, category or period", nil)
+		return
+	}
+
+	switch filter.Period {
+	case "", dto.SalesPeriodDay, dto.SalesPeriodWeek, dto.SalesPeriodMonth:
+	default:
+		utils.JSONError(w, http.StatusBadRequest, "invalid period, use day, week or month", nil)
+		return
+	}
+
+	switch filter.Sort {
+	case "", "margin", "margin_percent", "revenue", "cost", "quantity", "key":
+	default:
+		utils.JSONError(w, http.StatusBadRequest, "invalid sort", nil)
+		return
+	}
+
+	if order := query.Get("order"); order != "" && order != "asc" && order != "desc" {
+		utils.JSONError(w, http.StatusBadRequest, "invalid order, use asc or desc", nil)
+		return
+	}
+
+	report, err := h.ReportService.GrossMargin(r.Context(), user, filter)
+	if err != nil {
+		h.Logger.Error("failed get gross margin report", zap.Error(err))
+		utils.JSONError(w, reportErrorStatus(err), err.Error(), nil)
+		return
+	}
+
+	if query.Get("format") == "csv" {
+		h.writeMarginCSV(w, report)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get gross margin report", report)
+}
+
+func (h *ReportHandler) writeMarginCSV(w http.ResponseWriter, report *dto.MarginReportResponseDTO) {
+	filename := fmt.Sprintf("gross-margin-%s-%s-%s.csv", report.GroupBy, report.From, report.To)
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"key", "name", "quantity", "revenue", "cost", "margin", "margin_percent"})
+
+	for _, row := range append(report.Rows, report.Total) {
+		cw.Write([]string{
+			row.Key,
+			row.Name,
+			strconv.Itoa(row.Quantity),
+			row.Revenue.String(),
+			row.Cost.String(),
+			row.Margin.String(),
+			strconv.FormatFloat(row.MarginPercent, 'f', 2, 64),
+		})
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		h.Logger.Error("failed write gross margin csv", zap.Error(err))
+	}
+}
+
+// parseReportRange baca ?from= & ?to= (YYYY-MM-DD), default awal bulan s/d hari ini.
+// return false kalau response error sudah ditulis
+// reportErrorStatus 403 hanya untuk error permission, error lain (query, dsb) 500
+func reportErrorStatus(err error) int {
+	if errors.Is(err, service.ErrReportForbidden) {
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+func parseReportRange(w http.ResponseWriter, query url.Values) (time.Time, time.Time, bool) {
+	now := time.Now()
+
+	from, err := utils.ParseDate(query.Get("from"), time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid from, use YYYY-MM-DD", nil)
+		return from, from, false
+	}
+
+	to, err := utils.ParseDate(query.Get("to"), time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid to, use YYYY-MM-DD", nil)
+		return from, to, false
+	}
+
+	if to.Before(from) {
+		utils.JSONError(w, http.StatusBadRequest, "to must not be before from", nil)
+		return from, to, false
+	}
+
+	return from, to, true
+}
+
+// ?warehouse_id= ?rack_id= ?category_id=
+func (h *ReportHandler) InventoryAging(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	report, err := h.ReportService.InventoryAging(r.Context(), user, agingFilter(r.URL.Query()))
+	if err != nil {
+		h.Logger.Error("failed get inventory aging", zap.Error(err))
+		utils.JSONError(w, reportErrorStatus(err), err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get inventory aging", report)
+}
+
+// ?days= (default 90) ?warehouse_id= ?rack_id= ?category_id=
+func (h *ReportHandler) DeadStock(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	query := r.URL.Query()
+
+	days := 90
+	if v := query.Get("days"); v != "" {
+		days = utils.StringToInt(v)
+		if days < 1 {
+			utils.JSONError(w, http.StatusBadRequest, "invalid days", nil)
+			return
+		}
+	}
+
+	report, err := h.ReportService.DeadStock(r.Context(), user, agingFilter(query), days)
+	if err != nil {
+		h.Logger.Error("failed get dead stock", zap.Error(err))
+		utils.JSONError(w, reportErrorStatus(err), err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get dead stock", report)
+}
+
+func agingFilter(query url.Values) dto.InventoryAgingFilter {
+	return dto.InventoryAgingFilter{
+		WarehouseID: utils.StringToInt(query.Get("warehouse_id")),
+		RackID:      utils.StringToInt(query.Get("rack_id")),
+		CategoryID:  utils.StringToInt(query.Get("category_id")),
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"alfdwirhmn/inventory/service"
 	"alfdwirhmn/inventory/utils"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -51,6 +52,10 @@ func (h *SaleHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	sale, err := h.SaleService.Create(r.Context(), user, req)
+	if errors.Is(err, service.ErrWarehouseRequired) {
+		utils.JSONError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
 	if err != nil {
 		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
 		return
@@ -62,24 +67,7 @@ func (h *SaleHandler) Create(w http.ResponseWriter, r *http.Request) {
 	)
 
 	utils.JSONSuccess(w, http.StatusCreated, "Sale created succesfully",
-		dto.SaleResponseDTO{
-			ID:            sale.ID,
-			InvoiceNumber: sale.InvoiceNumber,
-			CustomerName:  sale.CustomerName,
-			CustomerPhone: sale.CustomerPhone,
-			CustomerEmail: sale.CustomerEmail,
-			SaleDate:      sale.SaleDate,
-			TotalAmount:   sale.TotalAmount,
-			Discount:      sale.Discount,
-			Tax:           sale.Tax,
-			GrandTotal:    sale.GrandTotal,
-			PaymentMethod: sale.PaymentMethod,
-			PaymentStatus: sale.PaymentStatus,
-			Notes:         sale.Notes,
-			CreatedBy:     *sale.CreatedBy,
-			CreatedAt:     sale.CreatedAt,
-			UpdatedAt:     sale.UpdatedAt,
-		})
+		dto.ToSaleResponseDTO(sale))
 }
 
 func (h *SaleHandler) Lists(w http.ResponseWriter, r *http.Request) {
@@ -118,7 +106,32 @@ func (h *SaleHandler) FindById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	utils.JSONSuccess(w, http.StatusOK, "success", sale)
+	utils.JSONSuccess(w, http.StatusOK, "success", dto.SaleDetailResponseDTO{
+		SaleResponseDTO: dto.ToSaleResponseDTO(sale),
+		Items:           dto.ToSaleItemsResponseDTO(sale.Items),
+	})
+}
+
+func (h *SaleHandler) Items(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid sale id", err)
+		return
+	}
+
+	items, err := h.SaleService.Items(r.Context(), user, id)
+	if err != nil {
+		utils.JSONError(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get sale items", dto.ToSaleItemsResponseDTO(items))
 }
 
 func (h *SaleHandler) Update(w http.ResponseWriter, r *http.Request) {
@@ -188,22 +201,210 @@ func (h *SaleHandler) UpdateSalePaymentStatus(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	utils.JSONSuccess(w, http.StatusOK, "payment status updated", dto.SaleResponseDTO{
-		ID:            sale.ID,
-		InvoiceNumber: sale.InvoiceNumber,
-		CustomerName:  sale.CustomerName,
-		CustomerPhone: sale.CustomerPhone,
-		CustomerEmail: sale.CustomerEmail,
-		SaleDate:      sale.SaleDate,
-		TotalAmount:   sale.TotalAmount,
-		Discount:      sale.Discount,
-		Tax:           sale.Tax,
-		GrandTotal:    sale.GrandTotal,
-		PaymentMethod: sale.PaymentMethod,
-		PaymentStatus: sale.PaymentStatus,
-		Notes:         sale.Notes,
-		CreatedBy:     *sale.CreatedBy,
-		CreatedAt:     sale.CreatedAt,
-		UpdatedAt:     sale.UpdatedAt,
-	})
+	utils.JSONSuccess(w, http.StatusOK, "payment status updated", dto.ToSaleResponseDTO(sale))
+}
+
+func (h *SaleHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	saleID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid sale id", err)
+		return
+	}
+
+	var req dto.CancelSaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	sale, err := h.SaleService.Cancel(r.Context(), user, saleID, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("sale cancelled",
+		zap.Int("sale_id", sale.ID),
+		zap.Int("cancelled_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, "sale cancelled", dto.ToSaleResponseDTO(sale))
+}
+
+func (h *SaleHandler) CreateReturn(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	saleID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid sale id", err)
+		return
+	}
+
+	var req dto.CreateSaleReturnRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	ret, err := h.SaleService.CreateReturn(r.Context(), user, saleID, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("sale return created",
+		zap.Int("sale_id", saleID),
+		zap.Int("return_id", ret.ID),
+		zap.Stringer("refund_amount", ret.RefundAmount),
+	)
+
+	utils.JSONSuccess(w, http.StatusCreated, "sale return created successfully", ret)
+}
+
+func (h *SaleHandler) Returns(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	saleID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid sale id", err)
+		return
+	}
+
+	returns, err := h.SaleService.Returns(r.Context(), user, saleID)
+	if err != nil {
+		utils.JSONError(w, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get sale returns", returns)
+}
+
+func (h *SaleHandler) AddPayment(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	saleID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid sale id", err)
+		return
+	}
+
+	var req dto.CreateSalePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	sale, err := h.SaleService.AddPayment(r.Context(), user, saleID, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("sale payment recorded",
+		zap.Int("sale_id", saleID),
+		zap.Stringer("amount", req.Amount),
+		zap.String("payment_status", sale.PaymentStatus),
+	)
+
+	utils.JSONSuccess(w, http.StatusCreated, "payment recorded", dto.ToSalePaymentsResponseDTO(sale))
+}
+
+func (h *SaleHandler) Payments(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	saleID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid sale id", err)
+		return
+	}
+
+	sale, err := h.SaleService.Payments(r.Context(), user, saleID)
+	if err != nil {
+		utils.JSONError(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get sale payments", dto.ToSalePaymentsResponseDTO(sale))
+}
+
+func (h *SaleHandler) VoidPayment(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	saleID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid sale id", err)
+		return
+	}
+
+	paymentID, err := strconv.Atoi(chi.URLParam(r, "paymentId"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid payment id", err)
+		return
+	}
+
+	var req dto.VoidSalePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	sale, err := h.SaleService.VoidPayment(r.Context(), user, saleID, paymentID, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("sale payment voided",
+		zap.Int("sale_id", saleID),
+		zap.Int("payment_id", paymentID),
+		zap.Int("voided_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, "payment voided", dto.ToSalePaymentsResponseDTO(sale))
 }
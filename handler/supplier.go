@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/middleware"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/service"
+	"alfdwirhmn/inventory/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+type SupplierHandler struct {
+	SupplierService service.SupplierService
+	Validator       *validator.Validate
+	Logger          *zap.Logger
+
+	Config utils.Configuration
+}
+
+func NewSupplierHandler(service service.SupplierService, validator *validator.Validate, logger *zap.Logger, config utils.Configuration) *SupplierHandler {
+	return &SupplierHandler{
+		SupplierService: service,
+		Validator:       validator,
+		Logger:          logger,
+		Config:          config,
+	}
+}
+
+func (h *SupplierHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req dto.CreateSupplierRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	supplier, err := h.SupplierService.Create(r.Context(), user, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("supplier created",
+		zap.Int("supplier_id", supplier.ID),
+		zap.Int("created_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusCreated, "supplier created successfully", supplier)
+}
+
+// ?q= cari berdasarkan kode, nama atau contact person
+func (h *SupplierHandler) Lists(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		utils.JSONError(w, http.StatusBadRequest, "invalid page", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(h.Config.Limit)
+	if err != nil {
+		utils.JSONError(w, http.StatusInternalServerError, "invalid limit config", nil)
+		return
+	}
+
+	suppliers, pagination, err := h.SupplierService.FindAll(r.Context(), r.URL.Query().Get("q"), page, limit)
+	if err != nil {
+		h.Logger.Error("failed get supplier", zap.Error(err))
+		utils.JSONError(w, http.StatusInternalServerError, "failed", nil)
+		return
+	}
+
+	utils.JSONWithPagination(w, http.StatusOK, "successfully get supplier data", suppliers, *pagination)
+}
+
+func (h *SupplierHandler) DetailById(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid supplier id", nil)
+		return
+	}
+
+	supplier, err := h.SupplierService.FindByID(r.Context(), user, id)
+	if err != nil {
+		utils.JSONError(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get supplier detail", supplier)
+}
+
+func (h *SupplierHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid supplier id", nil)
+		return
+	}
+
+	var req dto.UpdateSupplierRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	supplier, err := h.SupplierService.Update(r.Context(), user, id, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("supplier updated successfully",
+		zap.Int("supplier_id", supplier.ID),
+		zap.String("updated_by", user.Role),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, "supplier updated successfully", supplier)
+}
+
+func (h *SupplierHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid supplier id", nil)
+		return
+	}
+
+	if err := h.SupplierService.Delete(r.Context(), user, id); err != nil {
+		utils.JSONError(w, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("supplier deleted successfully",
+		zap.Int("supplier_id", id),
+		zap.String("deleted_by", user.Role),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, "supplier deleted successfully", id)
+}
+
+func (h *SupplierHandler) Items(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid supplier id", nil)
+		return
+	}
+
+	items, err := h.SupplierService.Items(r.Context(), user, id)
+	if err != nil {
+		utils.JSONError(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get supplier items", items)
+}
+
+func (h *SupplierHandler) UpsertItem(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid supplier id", nil)
+		return
+	}
+
+	var req dto.UpsertItemSupplierRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	link, err := h.SupplierService.UpsertItem(r.Context(), user, id, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("supplier item saved",
+		zap.Int("supplier_id", id),
+		zap.Int("item_id", link.ItemID),
+		zap.Bool("is_preferred", link.IsPreferred),
+		zap.Int("updated_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, "supplier item saved successfully", link)
+}
+
+func (h *SupplierHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid supplier id", nil)
+		return
+	}
+
+	itemID, err := strconv.Atoi(chi.URLParam(r, "itemId"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid item id", nil)
+		return
+	}
+
+	if err := h.SupplierService.DeleteItem(r.Context(), user, id, itemID); err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "supplier item deleted successfully", itemID)
+}
+
+func (h *SupplierHandler) ItemSuppliers(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid item id", nil)
+		return
+	}
+
+	suppliers, err := h.SupplierService.ItemSuppliers(r.Context(), user, id)
+	if err != nil {
+		utils.JSONError(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get item suppliers", suppliers)
+}
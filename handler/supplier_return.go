@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/middleware"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/service"
+	"alfdwirhmn/inventory/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+type SupplierReturnHandler struct {
+	SupplierReturnService service.SupplierReturnService
+	Validator             *validator.Validate
+	Logger                *zap.Logger
+
+	Config utils.Configuration
+}
+
+func NewSupplierReturnHandler(service service.SupplierReturnService, validator *validator.Validate, logger *zap.Logger, config utils.Configuration) *SupplierReturnHandler {
+	return &SupplierReturnHandler{
+		SupplierReturnService: service,
+		Validator:             validator,
+		Logger:                logger,
+		Config:                config,
+	}
+}
+
+func (h *SupplierReturnHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req dto.CreateSupplierReturnRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	sr, err := h.SupplierReturnService.Create(r.Context(), user, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("supplier return created",
+		zap.Int("supplier_return_id", sr.ID),
+		zap.String("return_number", sr.ReturnNumber),
+		zap.Int("supplier_id", sr.SupplierID),
+		zap.Stringer("credit_amount", sr.CreditAmount),
+		zap.Int("created_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusCreated, "supplier return created successfully", sr)
+}
+
+// filter ?supplier_id= ?credit_status=
+func (h *SupplierReturnHandler) Lists(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		utils.JSONError(w, http.StatusBadRequest, "invalid page", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(h.Config.Limit)
+	if err != nil {
+		utils.JSONError(w, http.StatusInternalServerError, "invalid limit config", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := dto.SupplierReturnFilter{
+		SupplierID:   utils.StringToInt(query.Get("supplier_id")),
+		CreditStatus: query.Get("credit_status"),
+	}
+
+	returns, pagination, err := h.SupplierReturnService.FindAll(r.Context(), user, filter, page, limit)
+	if err != nil {
+		h.Logger.Error("failed get supplier returns", zap.Error(err))
+		utils.JSONError(w, http.StatusInternalServerError, "failed", nil)
+		return
+	}
+
+	utils.JSONWithPagination(w, http.StatusOK, "successfully get supplier return data", returns, *pagination)
+}
+
+func (h *SupplierReturnHandler) DetailById(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid supplier return id", nil)
+		return
+	}
+
+	sr, err := h.SupplierReturnService.FindByID(r.Context(), user, id)
+	if err != nil {
+		utils.JSONError(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get supplier return detail", sr)
+}
+
+func (h *SupplierReturnHandler) AddCredit(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid supplier return id", nil)
+		return
+	}
+
+	var req dto.AddSupplierCreditRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	credit, err := h.SupplierReturnService.AddCredit(r.Context(), user, id, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("supplier credit recorded",
+		zap.Int("supplier_return_id", id),
+		zap.Int("credit_id", credit.ID),
+		zap.Stringer("amount", credit.Amount),
+		zap.Int("created_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusCreated, "supplier credit recorded successfully", credit)
+}
+
+// id = supplier id, dipasang di /suppliers/{id}/credits
+func (h *SupplierReturnHandler) CreditSummary(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid supplier id", nil)
+		return
+	}
+
+	summary, err := h.SupplierReturnService.CreditSummary(r.Context(), user, id)
+	if err != nil {
+		utils.JSONError(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.JSONSuccess(w, http.StatusOK, "successfully get supplier credit summary", summary)
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/middleware"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/service"
+	"alfdwirhmn/inventory/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+type TransferHandler struct {
+	TransferService service.TransferService
+	Validator       *validator.Validate
+	Logger          *zap.Logger
+
+	Config utils.Configuration
+}
+
+func NewTransferHandler(service service.TransferService, validator *validator.Validate, logger *zap.Logger, config utils.Configuration) *TransferHandler {
+	return &TransferHandler{
+		TransferService: service,
+		Validator:       validator,
+		Logger:          logger,
+		Config:          config,
+	}
+}
+
+func (h *TransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req dto.CreateTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	transfer, err := h.TransferService.Create(r.Context(), user, req)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info("transfer created",
+		zap.Int("transfer_id", transfer.ID),
+		zap.Int("created_by", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusCreated, "transfer created successfully", transfer)
+}
+
+func (h *TransferHandler) Lists(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		utils.JSONError(w, http.StatusBadRequest, "invalid page", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(h.Config.Limit)
+	if err != nil {
+		utils.JSONError(w, http.StatusInternalServerError, "invalid limit config", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := dto.TransferFilter{
+		Status:                 query.Get("status"),
+		SourceWarehouseID:      utils.StringToInt(query.Get("source_warehouse_id")),
+		DestinationWarehouseID: utils.StringToInt(query.Get("destination_warehouse_id")),
+	}
+
+	transfers, pagination, err := h.TransferService.FindAll(r.Context(), user, filter, page, limit)
+	if err != nil {
+		h.Logger.Error("failed get transfers", zap.Error(err))
+		utils.JSONError(w, http.StatusInternalServerError, "failed", nil)
+		return
+	}
+
+	utils.JSONWithPagination(w, http.StatusOK, "successfully get transfer data", transfers, *pagination)
+}
+
+func (h *TransferHandler) DetailById(w http.ResponseWriter, r *http.Request) {
+	h.handleTransition(w, r, "successfully get transfer detail", func(ctx context.Context, usr *model.User, id int) (*model.StockTransfer, error) {
+		return h.TransferService.FindByID(ctx, usr, id)
+	})
+}
+
+func (h *TransferHandler) Ship(w http.ResponseWriter, r *http.Request) {
+	h.handleTransition(w, r, "transfer shipped", h.TransferService.Ship)
+}
+
+func (h *TransferHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.handleTransition(w, r, "transfer cancelled", h.TransferService.Cancel)
+}
+
+func (h *TransferHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	var req dto.ReceiveTransferRequest
+	// body boleh kosong, artinya terima semua sisa qty
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	h.handleTransition(w, r, "transfer received", func(ctx context.Context, usr *model.User, id int) (*model.StockTransfer, error) {
+		return h.TransferService.Receive(ctx, usr, id, req)
+	})
+}
+
+func (h *TransferHandler) Close(w http.ResponseWriter, r *http.Request) {
+	var req dto.CloseTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if validationErrors, err := utils.ValidateErrors(req); err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "validation failed", validationErrors)
+		return
+	}
+
+	h.handleTransition(w, r, "transfer closed", func(ctx context.Context, usr *model.User, id int) (*model.StockTransfer, error) {
+		return h.TransferService.Close(ctx, usr, id, req)
+	})
+}
+
+// helper untuk endpoint /transfers/{id}/... yang hanya butuh user & id
+func (h *TransferHandler) handleTransition(w http.ResponseWriter, r *http.Request, message string, fn func(ctx context.Context, usr *model.User, id int) (*model.StockTransfer, error)) {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*model.User)
+	if !ok {
+		utils.JSONError(w, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.JSONError(w, http.StatusBadRequest, "invalid transfer id", nil)
+		return
+	}
+
+	transfer, err := fn(r.Context(), user, id)
+	if err != nil {
+		utils.JSONError(w, http.StatusConflict, err.Error(), nil)
+		return
+	}
+
+	h.Logger.Info(message,
+		zap.Int("transfer_id", transfer.ID),
+		zap.String("status", transfer.Status),
+		zap.Int("user_id", user.ID),
+	)
+
+	utils.JSONSuccess(w, http.StatusOK, message, transfer)
+}
@@ -7,6 +7,7 @@ import (
 	"alfdwirhmn/inventory/router"
 	"alfdwirhmn/inventory/service"
 	"alfdwirhmn/inventory/utils"
+	"context"
 	"log"
 	"net/http"
 
@@ -34,11 +35,16 @@ func main() {
 	txManager := database.NewTxManager(db)
 
 	repo := repository.NewContainer(db, logger)
-	svc := service.NewContainer(repo, logger, txManager)
+	svc := service.NewContainer(repo, logger, txManager, config)
 	h := handler.NewContainer(svc, repo, logger, config)
 
 	r := router.NewRouter(h, logger)
 
+	// terapkan perubahan harga terjadwal di background
+	go service.RunPriceScheduler(context.Background(), svc.Items, config.PriceSchedulerInterval, logger)
+	// notifikasi item yang turun di bawah stok minimum
+	go service.RunLowStockChecker(context.Background(), svc.Notification, config.LowStockCheckInterval, logger)
+
 	// run server with port from config
 	log.Printf("Server running on port %s\n", config.Port)
 	if err := http.ListenAndServe(":"+config.Port, r); err != nil {
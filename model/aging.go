@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// umur stok per item & rak, dihitung dari tanggal mutasi masuk ke rak.
+// stok yang tersisa dianggap dari barang masuk paling baru
+type InventoryAging struct {
+	WarehouseID   int    `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+	RackID        int    `json:"rack_id"`
+	RackCode      string `json:"rack_code"`
+	ItemID        int    `json:"item_id"`
+	SKU           string `json:"sku"`
+	Name          string `json:"name"`
+
+	Quantity int   `json:"quantity"`
+	Value    Money `json:"value"` // nilai persediaan dari mutasi di rak ini, sama dengan valuasi
+
+	// qty per kelompok umur (hari)
+	Days0To30   int `json:"days_0_30"`
+	Days31To90  int `json:"days_31_90"`
+	Days91To180 int `json:"days_91_180"`
+	DaysOver180 int `json:"days_over_180"`
+
+	OldestReceivedAt time.Time  `json:"oldest_received_at"`
+	LastMovementAt   *time.Time `json:"last_movement_at,omitempty"` // mutasi terakhir di rak ini
+	LastSaleAt       *time.Time `json:"last_sale_at,omitempty"`     // penjualan terakhir item di gudang ini
+	IdleDays         int        `json:"idle_days"`                  // hari sejak mutasi / penjualan terakhir
+}
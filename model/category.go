@@ -7,6 +7,7 @@ type Category struct {
 	Code        string `json:"code" db:"code"`
 	Name        string `json:"name" db:"name"`
 	Description string `json:"description" db:"description"`
+	TaxRateID   *int   `json:"tax_rate_id,omitempty" db:"tax_rate_id"` // tarif pajak default item di kategori ini
 	IsActive    bool   `json:"is_active" db:"is_active"`
 
 	CreatedBy *int      `json:"created_by,omitempty" db:"created_by"`
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// metode harga pokok persediaan, diatur lewat COSTING_METHOD
+const (
+	CostingFIFO    = "fifo"
+	CostingAverage = "average"
+)
+
+// satu lapisan biaya dari barang masuk
+type CostLayer struct {
+	ID                int       `json:"id" db:"id"`
+	ItemID            int       `json:"item_id" db:"item_id"`
+	MovementID        int       `json:"movement_id" db:"movement_id"`
+	Quantity          int       `json:"quantity" db:"quantity"`
+	RemainingQuantity int       `json:"remaining_quantity" db:"remaining_quantity"`
+	UnitCost          Money     `json:"unit_cost" db:"unit_cost"`
+	RemainingValue    Money     `json:"remaining_value" db:"remaining_value"`
+	ReceivedAt        time.Time `json:"received_at" db:"received_at"`
+}
+
+// nilai persediaan satu item di satu gudang
+type InventoryValuation struct {
+	WarehouseID   int    `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+	WarehouseName string `json:"warehouse_name"`
+	ItemID        int    `json:"item_id"`
+	SKU           string `json:"sku"`
+	Name          string `json:"name"`
+	Quantity      int    `json:"quantity"`
+	UnitCost      Money  `json:"unit_cost"` // rata-rata, value / quantity
+	Value         Money  `json:"value"`
+}
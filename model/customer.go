@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+type Customer struct {
+	ID        int     `json:"id" db:"id"`
+	Name      string  `json:"name" db:"name"`
+	Phone     *string `json:"phone,omitempty" db:"phone"`
+	Email     *string `json:"email,omitempty" db:"email"`
+	Address   *string `json:"address,omitempty" db:"address"`
+	Notes     *string `json:"notes,omitempty" db:"notes"`
+	IsActive  bool    `json:"is_active" db:"is_active"`
+	CreatedBy *int    `json:"created_by,omitempty" db:"created_by"`
+
+	// daftar harga default customer, bisa dioverride per sale
+	PriceListID *int `json:"price_list_id,omitempty" db:"price_list_id"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ringkasan transaksi customer, sale cancelled tidak dihitung
+type CustomerSalesSummary struct {
+	TotalSales    int   `json:"total_sales"`
+	TotalAmount   Money `json:"total_amount"`
+	TotalRefunded Money `json:"total_refunded"`
+	TotalPaid     Money `json:"total_paid"`
+	Outstanding   Money `json:"outstanding"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type ItemLocation struct {
+	ID       int `json:"id" db:"id"`
+	ItemID   int `json:"item_id" db:"item_id"`
+	RackID   int `json:"rack_id" db:"rack_id"`
+	Quantity int `json:"quantity" db:"quantity"`
+
+	// hasil join racks & warehouses
+	RackCode      string `json:"rack_code" db:"rack_code"`
+	RackName      string `json:"rack_name" db:"rack_name"`
+	WarehouseID   int    `json:"warehouse_id" db:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code" db:"warehouse_code"`
+	WarehouseName string `json:"warehouse_name" db:"warehouse_name"`
+
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	PriceChangeScheduled = "scheduled"
+	PriceChangeApplied   = "applied"
+	PriceChangeCancelled = "cancelled"
+)
+
+// satu perubahan harga / cost item. New* kosong = tidak berubah,
+// Old* terisi setelah perubahan diterapkan
+type ItemPriceChange struct {
+	ID       int    `json:"id" db:"id"`
+	ItemID   int    `json:"item_id" db:"item_id"`
+	OldPrice *Money `json:"old_price,omitempty" db:"old_price"`
+	NewPrice *Money `json:"new_price,omitempty" db:"new_price"`
+	OldCost  *Money `json:"old_cost,omitempty" db:"old_cost"`
+	NewCost  *Money `json:"new_cost,omitempty" db:"new_cost"`
+
+	EffectiveAt time.Time  `json:"effective_at" db:"effective_at"`
+	Status      string     `json:"status" db:"status"`
+	Reason      *string    `json:"reason,omitempty" db:"reason"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty" db:"applied_at"`
+
+	CreatedBy *int      `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -7,14 +7,15 @@ type Item struct {
 
 	CategoryID int  `db:"category_id"`
 	RackID     *int `db:"rack_id"`
+	TaxRateID  *int `db:"tax_rate_id"` // kosong = ikut tarif pajak kategori
 
 	SKU          string  `db:"sku"`
 	Name         string  `db:"name"`
 	Description  *string `db:"description"`
 	Unit         string  `db:"unit"`
-	Price        float64 `db:"price"`
-	Cost         float64 `db:"cost"`
-	Stock        int     `db:"stock"`
+	Price        Money   `db:"price"`
+	Cost         Money   `db:"cost"`
+	Stock        int     `db:"stock"` // total dari item_locations
 	MinimumStock int     `db:"minimum_stock"`
 	Weight       float64 `db:"weight"`
 	Dimensions   *string `db:"dimensions"`
@@ -23,4 +24,19 @@ type Item struct {
 
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
+
+	Locations []ItemLocation `db:"-"`
+}
+
+// item dengan stok di bawah minimum, stok = per gudang kalau difilter gudang
+type LowStockItem struct {
+	ItemID       int    `json:"item_id" db:"item_id"`
+	SKU          string `json:"sku" db:"sku"`
+	Name         string `json:"name" db:"name"`
+	CategoryID   int    `json:"category_id" db:"category_id"`
+	CategoryName string `json:"category_name" db:"category_name"`
+	Unit         string `json:"unit" db:"unit"`
+	Stock        int    `json:"stock" db:"stock"`
+	MinimumStock int    `json:"minimum_stock" db:"minimum_stock"`
+	Shortage     int    `json:"shortage" db:"shortage"` // minimum_stock - stock
 }
@@ -0,0 +1,23 @@
+package model
+
+// laba kotor per item / kategori / periode
+type GrossMargin struct {
+	ID   *int   `json:"id,omitempty"` // item_id atau category_id
+	Key  string `json:"key"`          // sku, kode kategori atau tanggal awal periode
+	Name string `json:"name,omitempty"`
+
+	Quantity      int     `json:"quantity"`
+	Revenue       Money   `json:"revenue"` // tanpa pajak, setelah diskon baris & nota
+	Cost          Money   `json:"cost"`    // cogs tercatat, fallback qty x cost item
+	Margin        Money   `json:"margin"`
+	MarginPercent float64 `json:"margin_percent"`
+}
+
+// SetMargin hitung margin & persentase dari revenue dan cost
+func (g *GrossMargin) SetMargin() {
+	g.Margin = g.Revenue - g.Cost
+	g.MarginPercent = 0
+	if g.Revenue != 0 {
+		g.MarginPercent = float64(int64(g.Margin)*10000/int64(g.Revenue)) / 100
+	}
+}
@@ -0,0 +1,227 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money nilai uang dalam sen (2 desimal), sama dengan kolom DECIMAL(15,2).
+// disimpan sebagai integer supaya penjumlahan tidak drift seperti float64.
+// di JSON ditulis sebagai string "12500.00", input boleh string atau angka.
+//
+// aturan pembulatan: input maksimal 2 desimal (lebih dari itu ditolak),
+// hasil perkalian / pembagian dibulatkan half-up (menjauhi nol) ke sen terdekat.
+type Money int64
+
+const moneyScale = 100
+
+// NewMoney dari nilai rupiah utuh
+func NewMoney(units int64) Money {
+	return Money(units * moneyScale)
+}
+
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("empty money value")
+	}
+
+	input := s
+	neg := false
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("invalid money value %q", input)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("money %q has more than 2 decimals", input)
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+
+	for _, part := range []string{whole, frac} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return 0, fmt.Errorf("invalid money value %q", input)
+			}
+		}
+	}
+
+	// nilai dalam sen harus muat di int64, jangan sampai wrap
+	w, err := strconv.ParseInt(whole, 10, 64)
+	f, _ := strconv.ParseInt(frac, 10, 64)
+	if err != nil || w > (math.MaxInt64-f)/moneyScale {
+		return 0, fmt.Errorf("money %q is out of range", input)
+	}
+
+	m := Money(w*moneyScale + f)
+	if neg {
+		m = -m
+	}
+	return m, nil
+}
+
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/moneyScale, v%moneyScale)
+}
+
+// Mul mengalikan dengan qty. hasil di luar jangkauan int64 dijepit ke batas int64
+// (lihat saturate), tidak wrap jadi nilai acak.
+func (m Money) Mul(qty int) Money {
+	return m.MulRatio(int64(qty), 1)
+}
+
+// MulRatio menghitung m * num / den, dibulatkan half-up ke sen.
+// hasil di luar jangkauan int64 dijepit ke batas int64 (lihat saturate).
+func (m Money) MulRatio(num, den int64) Money {
+	if den == 0 {
+		return 0
+	}
+
+	n := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(num))
+	d := big.NewInt(den)
+	if d.Sign() < 0 {
+		n.Neg(n)
+		d.Neg(d)
+	}
+
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+
+	// sisa >= setengah pembagi dibulatkan menjauhi nol
+	r.Abs(r).Mul(r, big.NewInt(2))
+	if r.Cmp(d) >= 0 {
+		if n.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	if !q.IsInt64() {
+		return saturate(q.Sign())
+	}
+	return Money(q.Int64())
+}
+
+// saturate mengembalikan batas int64 sesuai tanda hasil yang overflow.
+// nilai ini jauh di atas DECIMAL(15,2) jadi insert/update ke database pasti
+// ditolak (numeric field overflow), tidak tersimpan diam-diam sebagai angka salah.
+func saturate(sign int) Money {
+	if sign < 0 {
+		return math.MinInt64
+	}
+	return math.MaxInt64
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+
+	v, err := ParseMoney(strings.Trim(s, `"`))
+	if err != nil {
+		return err
+	}
+
+	*m = v
+	return nil
+}
+
+// Scan dari kolom numeric, pgx mengirim nilainya dalam bentuk teks
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case string:
+		return m.scanText(v)
+	case []byte:
+		return m.scanText(string(v))
+	case int64:
+		*m = NewMoney(v)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+}
+
+func (m *Money) scanText(s string) error {
+	// numeric hasil agregat (SUM/AVG) bisa punya lebih dari 2 desimal
+	if _, frac, ok := strings.Cut(s, "."); ok && len(frac) > 2 {
+		r, ok := new(big.Rat).SetString(s)
+		if !ok {
+			return fmt.Errorf("invalid money value %q", s)
+		}
+		s = r.FloatString(2) // half away from zero
+	}
+
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+
+	*m = v
+	return nil
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Percent persentase 2 desimal (11.00 = 11%), format JSON / database sama dengan Money
+type Percent int64
+
+func (p Percent) String() string {
+	return Money(p).String()
+}
+
+// Of menghitung p% dari m (pajak exclusive)
+func (p Percent) Of(m Money) Money {
+	return m.MulRatio(int64(p), 100*moneyScale)
+}
+
+// Included memisahkan bagian pajak dari nilai yang sudah termasuk pajak (inclusive)
+func (p Percent) Included(m Money) Money {
+	return m.MulRatio(int64(p), int64(p)+100*moneyScale)
+}
+
+func (p Percent) MarshalJSON() ([]byte, error) {
+	return Money(p).MarshalJSON()
+}
+
+func (p *Percent) UnmarshalJSON(data []byte) error {
+	return (*Money)(p).UnmarshalJSON(data)
+}
+
+func (p *Percent) Scan(src any) error {
+	return (*Money)(p).Scan(src)
+}
+
+func (p Percent) Value() (driver.Value, error) {
+	return Money(p).Value()
+}
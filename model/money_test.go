@@ -0,0 +1,226 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{in: "0", want: 0},
+		{in: "12500", want: 1250000},
+		{in: "12500.5", want: 1250050},
+		{in: "12500.05", want: 1250005},
+		{in: " 7.10 ", want: 710},
+		{in: ".5", want: 50},
+		{in: "3.", want: 300},
+		{in: "+1.25", want: 125},
+		{in: "-1.25", want: -125},
+		{in: "-0.01", want: -1},
+		{in: "92233720368547758.07", want: 9223372036854775807},
+		{in: "-92233720368547758.07", want: -9223372036854775807},
+
+		// lebih dari 2 desimal ditolak, bukan dibulatkan
+		{in: "1.005", wantErr: true},
+		{in: "1.000", wantErr: true},
+
+		// di luar jangkauan int64 sen
+		{in: "92233720368547758.08", wantErr: true},
+		{in: "92233720368547759", wantErr: true},
+		{in: "-99999999999999999999", wantErr: true},
+
+		// input rusak
+		{in: "", wantErr: true},
+		{in: "   ", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "1,50", wantErr: true},
+		{in: "1.2.3", wantErr: true},
+		{in: "--1", wantErr: true},
+		{in: "1e3", wantErr: true},
+		{in: "1.-5", wantErr: true},
+		{in: "Rp100", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMoney(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMoney(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyMulRatio(t *testing.T) {
+	tests := []struct {
+		m        Money
+		num, den int64
+		want     Money
+	}{
+		{m: 1000, num: 1, den: 3, want: 333},
+		{m: 1000, num: 2, den: 3, want: 667},
+		{m: 5, num: 1, den: 2, want: 3},   // 2.5 -> 3
+		{m: 5, num: 1, den: 4, want: 1},   // 1.25 -> 1
+		{m: 3, num: 1, den: 2, want: 2},   // 1.5 -> 2
+		{m: -5, num: 1, den: 2, want: -3}, // -2.5 -> -3, menjauhi nol
+		{m: -5, num: 1, den: 4, want: -1}, // -1.25 -> -1
+		{m: 5, num: -1, den: 2, want: -3},
+		{m: 5, num: 1, den: -2, want: -3},
+		{m: -5, num: -1, den: 2, want: 3},
+		{m: -1000, num: 2, den: 3, want: -667},
+		{m: 1000, num: 0, den: 3, want: 0},
+		{m: 1000, num: 1, den: 0, want: 0},
+
+		// perkalian antara tidak overflow walau melebihi int64
+		{m: 9223372036854775807, num: 3, den: 3, want: 9223372036854775807},
+
+		// hasil di luar int64 dijepit ke batas, tidak wrap
+		{m: 9223372036854775807, num: 2, den: 1, want: 9223372036854775807},
+		{m: 9223372036854775807, num: -2, den: 1, want: -9223372036854775808},
+		{m: -9223372036854775808, num: -1, den: 1, want: 9223372036854775807},
+	}
+
+	for _, tt := range tests {
+		if got := tt.m.MulRatio(tt.num, tt.den); got != tt.want {
+			t.Errorf("Money(%d).MulRatio(%d, %d) = %d, want %d", tt.m, tt.num, tt.den, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyMul(t *testing.T) {
+	tests := []struct {
+		m    Money
+		qty  int
+		want Money
+	}{
+		{m: 12500, qty: 3, want: 37500},
+		{m: -12500, qty: 3, want: -37500},
+		{m: 12500, qty: 0, want: 0},
+
+		// overflow dijepit ke batas int64
+		{m: 4611686018427387904, qty: 2, want: 9223372036854775807},
+		{m: 4611686018427387904, qty: -3, want: -9223372036854775808},
+		{m: -9223372036854775808, qty: -1, want: 9223372036854775807},
+	}
+
+	for _, tt := range tests {
+		if got := tt.m.Mul(tt.qty); got != tt.want {
+			t.Errorf("Money(%d).Mul(%d) = %d, want %d", tt.m, tt.qty, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	type payload struct {
+		Amount Money  `json:"amount"`
+		Opt    *Money `json:"opt"`
+	}
+
+	tests := []struct {
+		in      string
+		want    Money
+		out     string
+		wantErr bool
+	}{
+		{in: `{"amount":"12500.50"}`, want: 1250050, out: `{"amount":"12500.50","opt":null}`},
+		{in: `{"amount":12500.5}`, want: 1250050, out: `{"amount":"12500.50","opt":null}`},
+		{in: `{"amount":-3}`, want: -300, out: `{"amount":"-3.00","opt":null}`},
+		{in: `{"amount":"0.07"}`, want: 7, out: `{"amount":"0.07","opt":null}`},
+		{in: `{"amount":null}`, want: 0, out: `{"amount":"0.00","opt":null}`},
+		{in: `{"amount":1.005}`, wantErr: true},
+		{in: `{"amount":"abc"}`, wantErr: true},
+		{in: `{"amount":1e3}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var p payload
+		err := json.Unmarshal([]byte(tt.in), &p)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("unmarshal %s = %v, want error", tt.in, p.Amount)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unmarshal %s error: %v", tt.in, err)
+			continue
+		}
+		if p.Amount != tt.want {
+			t.Errorf("unmarshal %s = %d, want %d", tt.in, p.Amount, tt.want)
+		}
+
+		out, err := json.Marshal(p)
+		if err != nil {
+			t.Errorf("marshal %s error: %v", tt.in, err)
+			continue
+		}
+		if string(out) != tt.out {
+			t.Errorf("marshal %s = %s, want %s", tt.in, out, tt.out)
+		}
+
+		// hasil marshal harus bisa dibaca lagi ke nilai yang sama
+		var back payload
+		if err := json.Unmarshal(out, &back); err != nil || back.Amount != p.Amount {
+			t.Errorf("round trip %s = %d (%v), want %d", out, back.Amount, err, p.Amount)
+		}
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		src     any
+		want    Money
+		wantErr bool
+	}{
+		{src: nil, want: 0},
+		{src: "12500.00", want: 1250000},
+		{src: []byte("12500.5"), want: 1250050},
+		{src: "-7.25", want: -725},
+		{src: int64(42), want: 4200},
+
+		// agregat numeric (SUM/AVG) dengan desimal panjang dibulatkan half-up
+		{src: "3333.3333333333333333", want: 333333},
+		{src: "0.005", want: 1},
+		{src: "0.0049999", want: 0},
+		{src: "2.675", want: 268},
+		{src: "-0.005", want: -1},
+		{src: "-12.3450000000", want: -1235},
+		{src: "100.000000", want: 10000},
+
+		{src: "abc", wantErr: true},
+		{src: "1.23x45", wantErr: true},
+		{src: 1.5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var m Money
+		err := m.Scan(tt.src)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Scan(%v) = %d, want error", tt.src, m)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Scan(%v) error: %v", tt.src, err)
+			continue
+		}
+		if m != tt.want {
+			t.Errorf("Scan(%v) = %d, want %d", tt.src, m, tt.want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// jenis notifikasi
+const (
+	NotificationLowStock = "low_stock"
+)
+
+type Notification struct {
+	ID         int    `json:"id" db:"id"`
+	Type       string `json:"type" db:"type"`
+	ItemID     *int   `json:"item_id,omitempty" db:"item_id"`
+	MovementID *int   `json:"movement_id,omitempty" db:"movement_id"`
+	Message    string `json:"message" db:"message"`
+
+	// snapshot stok saat notifikasi dibuat
+	Stock        *int `json:"stock,omitempty" db:"stock"`
+	MinimumStock *int `json:"minimum_stock,omitempty" db:"minimum_stock"`
+
+	IsRead     bool       `json:"is_read" db:"is_read"`
+	ReadBy     *int       `json:"read_by,omitempty" db:"read_by"`
+	ReadAt     *time.Time `json:"read_at,omitempty" db:"read_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
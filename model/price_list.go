@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+type PriceList struct {
+	ID          int     `json:"id" db:"id"`
+	Code        string  `json:"code" db:"code"`
+	Name        string  `json:"name" db:"name"`
+	Description *string `json:"description,omitempty" db:"description"`
+	IsActive    bool    `json:"is_active" db:"is_active"`
+	CreatedBy   *int    `json:"created_by,omitempty" db:"created_by"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	Items []PriceListItem `json:"items,omitempty"`
+}
+
+// harga item di satu daftar harga, berlaku untuk qty >= MinQuantity
+type PriceListItem struct {
+	ID          int    `json:"id" db:"id"`
+	PriceListID int    `json:"price_list_id" db:"price_list_id"`
+	ItemID      int    `json:"item_id" db:"item_id"`
+	SKU         string `json:"sku" db:"sku"`   // join items
+	Name        string `json:"name" db:"name"` // join items
+	MinQuantity int    `json:"min_quantity" db:"min_quantity"`
+	Price       Money  `json:"price" db:"price"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// harga satu item di semua daftar harga aktif
+type ItemPrice struct {
+	PriceListID   int    `json:"price_list_id"`
+	PriceListCode string `json:"price_list_code"`
+	PriceListName string `json:"price_list_name"`
+	MinQuantity   int    `json:"min_quantity"`
+	Price         Money  `json:"price"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+const (
+	PromoPercentage = "percentage"
+	PromoFixed      = "fixed"
+	PromoBuyXGetY   = "buy_x_get_y"
+)
+
+type Promotion struct {
+	ID        int    `json:"id" db:"id"`
+	Code      string `json:"code" db:"code"`
+	Name      string `json:"name" db:"name"`
+	PromoType string `json:"promo_type" db:"promo_type"`
+
+	// percentage: persen (10.00 = 10%), fixed: potongan per unit
+	Value       Money `json:"value" db:"value"`
+	BuyQuantity *int  `json:"buy_quantity,omitempty" db:"buy_quantity"`
+	GetQuantity *int  `json:"get_quantity,omitempty" db:"get_quantity"`
+
+	// keduanya kosong = semua item
+	ItemID     *int `json:"item_id,omitempty" db:"item_id"`
+	CategoryID *int `json:"category_id,omitempty" db:"category_id"`
+
+	StartsAt   time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at,omitempty" db:"ends_at"`
+	UsageLimit *int       `json:"usage_limit,omitempty" db:"usage_limit"`
+	UsageCount int        `json:"usage_count" db:"usage_count"`
+
+	IsActive  bool      `json:"is_active" db:"is_active"`
+	CreatedBy *int      `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Discount potongan promo untuk satu baris, tidak pernah melebihi harga * qty
+func (p *Promotion) Discount(price Money, qty int) Money {
+	gross := price.Mul(qty)
+
+	var d Money
+	switch p.PromoType {
+	case PromoPercentage:
+		d = Percent(p.Value).Of(gross)
+	case PromoFixed:
+		d = p.Value.Mul(qty)
+	case PromoBuyXGetY:
+		if p.BuyQuantity == nil || p.GetQuantity == nil {
+			return 0
+		}
+		set := *p.BuyQuantity + *p.GetQuantity
+		d = price.Mul(qty / set * *p.GetQuantity)
+	}
+
+	return min(d, gross)
+}
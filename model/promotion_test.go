@@ -0,0 +1,90 @@
+package model
+
+import "testing"
+
+func TestPromotionDiscount(t *testing.T) {
+	intp := func(v int) *int { return &v }
+
+	tests := []struct {
+		name  string
+		promo Promotion
+		price Money
+		qty   int
+		want  Money
+	}{
+		{
+			name:  "percentage",
+			promo: Promotion{PromoType: PromoPercentage, Value: 1000}, // 10%
+			price: 15000, qty: 3,
+			want: 4500,
+		},
+		{
+			name:  "percentage rounds half up",
+			promo: Promotion{PromoType: PromoPercentage, Value: 1250}, // 12.5%
+			price: 333, qty: 1,
+			want: 42, // 41.625
+		},
+		{
+			name:  "percentage above 100 capped at gross",
+			promo: Promotion{PromoType: PromoPercentage, Value: 15000},
+			price: 1000, qty: 2,
+			want: 2000,
+		},
+		{
+			name:  "fixed per unit",
+			promo: Promotion{PromoType: PromoFixed, Value: 500},
+			price: 2000, qty: 4,
+			want: 2000,
+		},
+		{
+			name:  "fixed capped at gross",
+			promo: Promotion{PromoType: PromoFixed, Value: 5000},
+			price: 2000, qty: 3,
+			want: 6000,
+		},
+		{
+			name:  "buy 2 get 1 with exact sets",
+			promo: Promotion{PromoType: PromoBuyXGetY, BuyQuantity: intp(2), GetQuantity: intp(1)},
+			price: 1000, qty: 6,
+			want: 2000,
+		},
+		{
+			name:  "buy 2 get 1 with incomplete set",
+			promo: Promotion{PromoType: PromoBuyXGetY, BuyQuantity: intp(2), GetQuantity: intp(1)},
+			price: 1000, qty: 5,
+			want: 1000,
+		},
+		{
+			name:  "buy 2 get 1 below one set",
+			promo: Promotion{PromoType: PromoBuyXGetY, BuyQuantity: intp(2), GetQuantity: intp(1)},
+			price: 1000, qty: 2,
+			want: 0,
+		},
+		{
+			name:  "buy 1 get 2",
+			promo: Promotion{PromoType: PromoBuyXGetY, BuyQuantity: intp(1), GetQuantity: intp(2)},
+			price: 1000, qty: 7,
+			want: 4000,
+		},
+		{
+			name:  "buy x get y without quantities",
+			promo: Promotion{PromoType: PromoBuyXGetY, BuyQuantity: intp(2)},
+			price: 1000, qty: 6,
+			want: 0,
+		},
+		{
+			name:  "unknown type",
+			promo: Promotion{PromoType: "bundle", Value: 1000},
+			price: 1000, qty: 1,
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.promo.Discount(tt.price, tt.qty); got != tt.want {
+				t.Errorf("Discount(%v, %d) = %v, want %v", tt.price, tt.qty, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+// status purchase order
+const (
+	PurchaseDraft             = "draft"
+	PurchaseApproved          = "approved"
+	PurchasePartiallyReceived = "partially_received"
+	PurchaseClosed            = "closed"
+	PurchaseCancelled         = "cancelled"
+)
+
+type PurchaseOrder struct {
+	ID          int    `json:"id" db:"id"`
+	PONumber    string `json:"po_number" db:"po_number"`
+	SupplierID  int    `json:"supplier_id" db:"supplier_id"`
+	WarehouseID int    `json:"warehouse_id" db:"warehouse_id"`
+	Status      string `json:"status" db:"status"`
+
+	// hasil join suppliers & warehouses
+	SupplierName  string `json:"supplier_name" db:"supplier_name"`
+	WarehouseCode string `json:"warehouse_code" db:"warehouse_code"`
+
+	OrderDate    time.Time  `json:"order_date" db:"order_date"`
+	ExpectedDate *time.Time `json:"expected_date,omitempty" db:"expected_date"`
+	TotalAmount  Money      `json:"total_amount" db:"total_amount"`
+	Notes        *string    `json:"notes,omitempty" db:"notes"`
+
+	CreatedBy  int        `json:"created_by" db:"created_by"`
+	ApprovedBy *int       `json:"approved_by,omitempty" db:"approved_by"`
+	ApprovedAt *time.Time `json:"approved_at,omitempty" db:"approved_at"`
+	ClosedAt   *time.Time `json:"closed_at,omitempty" db:"closed_at"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	Items    []PurchaseOrderItem `json:"items,omitempty" db:"-"`
+	Receipts []GoodsReceipt      `json:"receipts,omitempty" db:"-"`
+}
+
+type PurchaseOrderItem struct {
+	ID               int    `json:"id" db:"id"`
+	PurchaseOrderID  int    `json:"purchase_order_id" db:"purchase_order_id"`
+	ItemID           int    `json:"item_id" db:"item_id"`
+	SKU              string `json:"sku" db:"sku"`
+	Name             string `json:"name" db:"name"`
+	Quantity         int    `json:"quantity" db:"quantity"`
+	ReceivedQuantity int    `json:"received_quantity" db:"received_quantity"`
+	UnitCost         Money  `json:"unit_cost" db:"unit_cost"`
+	Subtotal         Money  `json:"subtotal" db:"subtotal"`
+}
+
+// penerimaan barang (GRN), stok masuk ke satu rak di gudang tujuan PO
+type GoodsReceipt struct {
+	ID              int       `json:"id" db:"id"`
+	GRNNumber       string    `json:"grn_number" db:"grn_number"`
+	PurchaseOrderID int       `json:"purchase_order_id" db:"purchase_order_id"`
+	RackID          int       `json:"rack_id" db:"rack_id"`
+	ReceivedDate    time.Time `json:"received_date" db:"received_date"`
+	TotalAmount     Money     `json:"total_amount" db:"total_amount"`
+	Notes           *string   `json:"notes,omitempty" db:"notes"`
+	ReceivedBy      int       `json:"received_by" db:"received_by"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+
+	Items []GoodsReceiptItem `json:"items,omitempty" db:"-"`
+}
+
+type GoodsReceiptItem struct {
+	ID                  int    `json:"id" db:"id"`
+	GoodsReceiptID      int    `json:"goods_receipt_id" db:"goods_receipt_id"`
+	PurchaseOrderItemID int    `json:"purchase_order_item_id" db:"purchase_order_item_id"`
+	ItemID              int    `json:"item_id" db:"item_id"`
+	SKU                 string `json:"sku" db:"sku"`
+	Name                string `json:"name" db:"name"`
+	Quantity            int    `json:"quantity" db:"quantity"`
+	ReturnedQuantity    int    `json:"returned_quantity" db:"returned_quantity"`
+	UnitCost            Money  `json:"unit_cost" db:"unit_cost"`
+	Subtotal            Money  `json:"subtotal" db:"subtotal"`
+}
@@ -0,0 +1,30 @@
+package model
+
+// saran pembelian ulang per item & gudang dari kecepatan penjualan
+type ReorderSuggestion struct {
+	WarehouseID   int    `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+	WarehouseName string `json:"warehouse_name"`
+	ItemID        int    `json:"item_id"`
+	SKU           string `json:"sku"`
+	Name          string `json:"name"`
+	Unit          string `json:"unit"`
+
+	// supplier utama (is_preferred), kosong kalau belum diatur
+	SupplierID   *int    `json:"supplier_id,omitempty"`
+	SupplierName *string `json:"supplier_name,omitempty"`
+
+	Stock        int     `json:"stock"`         // stok di gudang
+	OnOrder      int     `json:"on_order"`      // sisa qty PO draft / approved / partially_received
+	SoldQuantity int     `json:"sold_quantity"` // penjualan bersih (dikurangi retur) selama periode
+	DailyUsage   float64 `json:"daily_usage"`
+
+	LeadTimeDays      int `json:"lead_time_days"`
+	SafetyStock       int `json:"safety_stock"` // minimum_stock item
+	ReorderPoint      int `json:"reorder_point"`
+	MinOrderQuantity  int `json:"min_order_quantity"`
+	SuggestedQuantity int `json:"suggested_quantity"`
+
+	UnitCost      Money `json:"unit_cost"` // harga beli terakhir, fallback cost item
+	EstimatedCost Money `json:"estimated_cost"`
+}
@@ -5,33 +5,68 @@ import "time"
 type Sale struct {
 	ID            int
 	InvoiceNumber string
+	WarehouseID   *int
+	CustomerID    *int
 	CustomerName  *string
 	CustomerPhone *string
 	CustomerEmail *string
 	SaleDate      time.Time
-	TotalAmount   float64
-	Discount      float64
-	Tax           float64
-	GrandTotal    float64
+	TotalAmount   Money
+	Discount      Money
+	Tax           Money
+	GrandTotal    Money
 	PaymentMethod *string
 	PaymentStatus string
 	Notes         *string
+	CancelledBy   *int
+	CancelledAt   *time.Time
+	CancelReason  *string
 	CreatedBy     *int
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
+
+	Items    []SaleItem
+	Payments []SalePayment
 }
 
 type SaleItem struct {
 	ID        int
 	SaleID    int
 	ItemID    int
+	SKU       string // join items
+	Name      string // join items
 	Quantity  int
-	UnitPrice float64
-	Subtotal  float64
-	Discount  float64
+	UnitPrice Money
+	Subtotal  Money // harga * qty - diskon baris - diskon promo
+	Discount  Money
+
+	// daftar harga asal UnitPrice, kosong = harga dasar item
+	PriceListID *int
+
+	// promo yang dipakai baris ini
+	PromotionID       *int
+	PromotionDiscount Money
+
+	// harga pokok penjualan baris, dari ledger (FIFO / average)
+	Cogs Money
+
+	// snapshot pajak saat transaksi
+	TaxRateID    *int
+	TaxRate      Percent
+	TaxInclusive bool
+	TaxAmount    Money
+
 	CreatedAt time.Time
 }
 
+// Total nilai baris yang dibayar customer, pajak exclusive ditambahkan di atas subtotal
+func (si SaleItem) Total() Money {
+	if si.TaxInclusive {
+		return si.Subtotal
+	}
+	return si.Subtotal + si.TaxAmount
+}
+
 // type Sale struct {
 // 	ID            int       `db:"id"`
 // 	InvoiceNumber string    `db:"invoice_number"`
@@ -40,11 +75,6 @@ type SaleItem struct {
 // 	CustomerEmail *string   `db:"customer_email"`
 // 	SaleDate      time.Time `db:"sale_date"`
 
-// 	TotalAmount float64 `db:"total_amount"`
-// 	Discount    float64 `db:"discount"`
-// 	Tax         float64 `db:"tax"`
-// 	GrandTotal  float64 `db:"grand_total"`
-
 // 	PaymentMethod *string `db:"payment_method"`
 // 	PaymentStatus string  `db:"payment_status"`
 // 	Notes         *string `db:"notes"`
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// status pembayaran sale, diturunkan dari total sale_payments
+const (
+	PaymentPending       = "pending"
+	PaymentPartiallyPaid = "partially_paid"
+	PaymentPaid          = "paid"
+	PaymentCancelled     = "cancelled"
+)
+
+type SalePayment struct {
+	ID            int       `json:"id" db:"id"`
+	SaleID        int       `json:"sale_id" db:"sale_id"`
+	Amount        Money     `json:"amount" db:"amount"`
+	PaymentMethod string    `json:"payment_method" db:"payment_method"`
+	Reference     *string   `json:"reference,omitempty" db:"reference"`
+	PaidAt        time.Time `json:"paid_at" db:"paid_at"`
+
+	VoidedAt   *time.Time `json:"voided_at,omitempty" db:"voided_at"`
+	VoidedBy   *int       `json:"voided_by,omitempty" db:"voided_by"`
+	VoidReason *string    `json:"void_reason,omitempty" db:"void_reason"`
+
+	CreatedBy int       `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
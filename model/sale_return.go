@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+type SaleReturn struct {
+	ID           int       `json:"id" db:"id"`
+	SaleID       int       `json:"sale_id" db:"sale_id"`
+	RefundAmount Money     `json:"refund_amount" db:"refund_amount"`
+	RefundMethod *string   `json:"refund_method,omitempty" db:"refund_method"`
+	Reason       *string   `json:"reason,omitempty" db:"reason"`
+	CreatedBy    int       `json:"created_by" db:"created_by"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+
+	Items []SaleReturnItem `json:"items,omitempty" db:"-"`
+}
+
+type SaleReturnItem struct {
+	ID           int   `json:"id" db:"id"`
+	ReturnID     int   `json:"return_id" db:"return_id"`
+	SaleItemID   int   `json:"sale_item_id" db:"sale_item_id"`
+	ItemID       int   `json:"item_id" db:"item_id"`
+	RackID       int   `json:"rack_id" db:"rack_id"`
+	Quantity     int   `json:"quantity" db:"quantity"`
+	RefundAmount Money `json:"refund_amount" db:"refund_amount"`
+}
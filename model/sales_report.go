@@ -0,0 +1,53 @@
+package model
+
+// ringkasan penjualan dalam periode laporan
+type SalesSummary struct {
+	Transactions int   `json:"transactions"`
+	ItemsSold    int   `json:"items_sold"`
+	TotalAmount  Money `json:"total_amount"`
+	Discount     Money `json:"discount"`
+	Tax          Money `json:"tax"`
+	GrandTotal   Money `json:"grand_total"`
+	Refunds      Money `json:"refunds"` // refund retur dari sale di periode ini
+}
+
+// agregat per hari / minggu / bulan, period = tanggal awal periode
+type SalesPeriod struct {
+	Period       string `json:"period"`
+	Transactions int    `json:"transactions"`
+	TotalAmount  Money  `json:"total_amount"`
+	Discount     Money  `json:"discount"`
+	Tax          Money  `json:"tax"`
+	GrandTotal   Money  `json:"grand_total"`
+}
+
+// pembayaran yang diterima per metode (dari sale_payments, tidak termasuk void)
+type SalesByPaymentMethod struct {
+	PaymentMethod string `json:"payment_method"`
+	Payments      int    `json:"payments"`
+	Amount        Money  `json:"amount"`
+}
+
+type SalesByCashier struct {
+	UserID       int    `json:"user_id"`
+	Username     string `json:"username"`
+	FullName     string `json:"full_name"`
+	Transactions int    `json:"transactions"`
+	GrandTotal   Money  `json:"grand_total"`
+}
+
+// revenue = subtotal baris (setelah diskon baris & promo, sebelum diskon nota)
+type SalesByCategory struct {
+	CategoryID   int    `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	Quantity     int    `json:"quantity"`
+	Revenue      Money  `json:"revenue"`
+}
+
+type SalesByItem struct {
+	ItemID   int    `json:"item_id"`
+	SKU      string `json:"sku"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+	Revenue  Money  `json:"revenue"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// jenis mutasi stok
+const (
+	MovementSale         = "sale"
+	MovementAdjustment   = "adjustment"
+	MovementReceipt      = "receipt"
+	MovementTransfer     = "transfer"
+	MovementReturn       = "return"
+	MovementCancellation = "cancellation"
+	// barang dikembalikan ke supplier
+	MovementSupplierReturn = "supplier_return"
+)
+
+type StockMovement struct {
+	ID           int     `json:"id" db:"id"`
+	ItemID       int     `json:"item_id" db:"item_id"`
+	RackID       int     `json:"rack_id" db:"rack_id"`
+	MovementType string  `json:"movement_type" db:"movement_type"`
+	Quantity     int     `json:"quantity" db:"quantity"`       // positif masuk, negatif keluar
+	StockAfter   int     `json:"stock_after" db:"stock_after"` // total stok item setelah mutasi
+	Reason       *string `json:"reason,omitempty" db:"reason"` // khusus adjustment
+	ReferenceID  *int    `json:"reference_id,omitempty" db:"reference_id"`
+	Notes        *string `json:"notes,omitempty" db:"notes"`
+
+	// nilai persediaan mutasi, TotalCost bertanda sama dengan Quantity.
+	// isi TotalCost (positif) sebelum Post untuk barang masuk dengan harga pokok tertentu
+	UnitCost  Money `json:"unit_cost" db:"unit_cost"`
+	TotalCost Money `json:"total_cost" db:"total_cost"`
+
+	CreatedBy *int      `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
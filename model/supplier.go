@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+type Supplier struct {
+	ID               int     `json:"id" db:"id"`
+	Code             string  `json:"code" db:"code"`
+	Name             string  `json:"name" db:"name"`
+	ContactPerson    *string `json:"contact_person,omitempty" db:"contact_person"`
+	Phone            *string `json:"phone,omitempty" db:"phone"`
+	Email            *string `json:"email,omitempty" db:"email"`
+	Address          *string `json:"address,omitempty" db:"address"`
+	City             *string `json:"city,omitempty" db:"city"`
+	PaymentTermsDays int     `json:"payment_terms_days" db:"payment_terms_days"`
+	Notes            *string `json:"notes,omitempty" db:"notes"`
+	IsActive         bool    `json:"is_active" db:"is_active"`
+	CreatedBy        *int    `json:"created_by,omitempty" db:"created_by"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// item yang bisa dibeli dari supplier
+type ItemSupplier struct {
+	ID           int    `json:"id" db:"id"`
+	ItemID       int    `json:"item_id" db:"item_id"`
+	SKU          string `json:"sku" db:"sku"`   // join items
+	Name         string `json:"name" db:"name"` // join items
+	SupplierID   int    `json:"supplier_id" db:"supplier_id"`
+	SupplierName string `json:"supplier_name" db:"supplier_name"` // join suppliers
+
+	SupplierSKU       *string    `json:"supplier_sku,omitempty" db:"supplier_sku"`
+	LeadTimeDays      int        `json:"lead_time_days" db:"lead_time_days"`
+	MinOrderQuantity  int        `json:"min_order_quantity" db:"min_order_quantity"`
+	LastPurchasePrice *Money     `json:"last_purchase_price,omitempty" db:"last_purchase_price"`
+	LastPurchasedAt   *time.Time `json:"last_purchased_at,omitempty" db:"last_purchased_at"`
+	IsPreferred       bool       `json:"is_preferred" db:"is_preferred"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,68 @@
+package model
+
+import "time"
+
+// status kredit retur supplier, diturunkan dari total supplier_credits
+const (
+	CreditPending           = "pending"
+	CreditPartiallyCredited = "partially_credited"
+	CreditCredited          = "credited"
+)
+
+type SupplierReturn struct {
+	ID             int    `json:"id" db:"id"`
+	ReturnNumber   string `json:"return_number" db:"return_number"`
+	SupplierID     int    `json:"supplier_id" db:"supplier_id"`
+	SupplierName   string `json:"supplier_name" db:"supplier_name"` // join suppliers
+	GoodsReceiptID *int   `json:"goods_receipt_id,omitempty" db:"goods_receipt_id"`
+	RackID         int    `json:"rack_id" db:"rack_id"`
+
+	ReturnDate time.Time `json:"return_date" db:"return_date"`
+	Reason     *string   `json:"reason,omitempty" db:"reason"`
+	Notes      *string   `json:"notes,omitempty" db:"notes"`
+
+	// kredit yang diharapkan dari supplier vs yang sudah diterima
+	CreditAmount   Money  `json:"credit_amount" db:"credit_amount"`
+	CreditedAmount Money  `json:"credited_amount" db:"credited_amount"`
+	CreditStatus   string `json:"credit_status" db:"credit_status"`
+
+	CreatedBy int       `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	Items   []SupplierReturnItem `json:"items,omitempty" db:"-"`
+	Credits []SupplierCredit     `json:"credits,omitempty" db:"-"`
+}
+
+type SupplierReturnItem struct {
+	ID                 int    `json:"id" db:"id"`
+	SupplierReturnID   int    `json:"supplier_return_id" db:"supplier_return_id"`
+	ItemID             int    `json:"item_id" db:"item_id"`
+	SKU                string `json:"sku" db:"sku"`
+	Name               string `json:"name" db:"name"`
+	GoodsReceiptItemID *int   `json:"goods_receipt_item_id,omitempty" db:"goods_receipt_item_id"`
+	Quantity           int    `json:"quantity" db:"quantity"`
+	UnitCost           Money  `json:"unit_cost" db:"unit_cost"`
+	CreditAmount       Money  `json:"credit_amount" db:"credit_amount"`
+}
+
+// kredit / nota kredit yang diterima dari supplier
+type SupplierCredit struct {
+	ID               int       `json:"id" db:"id"`
+	SupplierReturnID int       `json:"supplier_return_id" db:"supplier_return_id"`
+	Amount           Money     `json:"amount" db:"amount"`
+	Reference        *string   `json:"reference,omitempty" db:"reference"`
+	CreditedAt       time.Time `json:"credited_at" db:"credited_at"`
+	Notes            *string   `json:"notes,omitempty" db:"notes"`
+	CreatedBy        int       `json:"created_by" db:"created_by"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
+
+// ringkasan kredit retur per supplier
+type SupplierCreditSummary struct {
+	SupplierID     int   `json:"supplier_id"`
+	TotalReturns   int   `json:"total_returns"`
+	CreditAmount   Money `json:"credit_amount"`
+	CreditedAmount Money `json:"credited_amount"`
+	Outstanding    Money `json:"outstanding"`
+}
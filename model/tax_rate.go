@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type TaxRate struct {
+	ID          int     `json:"id" db:"id"`
+	Code        string  `json:"code" db:"code"`
+	Name        string  `json:"name" db:"name"`
+	Rate        Percent `json:"rate" db:"rate"`
+	IsInclusive bool    `json:"is_inclusive" db:"is_inclusive"` // harga jual sudah termasuk pajak
+	IsActive    bool    `json:"is_active" db:"is_active"`
+	CreatedBy   *int    `json:"created_by,omitempty" db:"created_by"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Split memecah nilai baris (setelah diskon) jadi pajak dan nilai yang ditambahkan ke grand total.
+// inclusive: pajak sudah ada di harga, tidak menambah total. exclusive: pajak ditambahkan.
+func (t *TaxRate) Split(base Money) (tax, added Money) {
+	if t == nil || t.Rate == 0 || base <= 0 {
+		return 0, 0
+	}
+
+	if t.IsInclusive {
+		return t.Rate.Included(base), 0
+	}
+
+	tax = t.Rate.Of(base)
+	return tax, tax
+}
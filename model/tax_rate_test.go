@@ -0,0 +1,51 @@
+package model
+
+import "testing"
+
+func TestTaxRateSplit(t *testing.T) {
+	tests := []struct {
+		name      string
+		rate      *TaxRate
+		base      Money
+		wantTax   Money
+		wantAdded Money
+	}{
+		{name: "nil rate is exempt", rate: nil, base: 100000},
+		{name: "zero rate", rate: &TaxRate{Rate: 0}, base: 100000},
+		{name: "zero base", rate: &TaxRate{Rate: 1100}, base: 0},
+		{name: "negative base", rate: &TaxRate{Rate: 1100}, base: -100000},
+		{
+			name: "exclusive", rate: &TaxRate{Rate: 1100}, base: 100000,
+			wantTax: 11000, wantAdded: 11000,
+		},
+		{
+			name: "exclusive rounds half up", rate: &TaxRate{Rate: 1100}, base: 50, // 5.5
+			wantTax: 6, wantAdded: 6,
+		},
+		{
+			name: "exclusive fractional rate", rate: &TaxRate{Rate: 1250}, base: 999, // 124.875
+			wantTax: 125, wantAdded: 125,
+		},
+		{
+			name: "inclusive", rate: &TaxRate{Rate: 1100, IsInclusive: true}, base: 111000,
+			wantTax: 11000,
+		},
+		{
+			name: "inclusive rounds half up", rate: &TaxRate{Rate: 1100, IsInclusive: true}, base: 100000, // 9909.909...
+			wantTax: 9910,
+		},
+		{
+			name: "inclusive small amount", rate: &TaxRate{Rate: 1000, IsInclusive: true}, base: 11, // 1.0
+			wantTax: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tax, added := tt.rate.Split(tt.base)
+			if tax != tt.wantTax || added != tt.wantAdded {
+				t.Errorf("Split(%v) = (%v, %v), want (%v, %v)", tt.base, tax, added, tt.wantTax, tt.wantAdded)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// status dokumen transfer
+const (
+	TransferDraft             = "draft"
+	TransferInTransit         = "in_transit"
+	TransferPartiallyReceived = "partially_received"
+	TransferReceived          = "received"
+	TransferCancelled         = "cancelled"
+	// ditutup dengan qty kurang, sisa yang tidak sampai dicatat sebagai adjustment di rak asal
+	TransferClosed = "closed"
+)
+
+type StockTransfer struct {
+	ID                int    `json:"id" db:"id"`
+	SourceRackID      int    `json:"source_rack_id" db:"source_rack_id"`
+	DestinationRackID int    `json:"destination_rack_id" db:"destination_rack_id"`
+	Status            string `json:"status" db:"status"`
+
+	// hasil join racks
+	SourceWarehouseID      int `json:"source_warehouse_id" db:"source_warehouse_id"`
+	DestinationWarehouseID int `json:"destination_warehouse_id" db:"destination_warehouse_id"`
+
+	Notes      *string    `json:"notes,omitempty" db:"notes"`
+	CreatedBy  int        `json:"created_by" db:"created_by"`
+	ShippedBy  *int       `json:"shipped_by,omitempty" db:"shipped_by"`
+	ShippedAt  *time.Time `json:"shipped_at,omitempty" db:"shipped_at"`
+	ReceivedAt *time.Time `json:"received_at,omitempty" db:"received_at"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	Items []StockTransferItem `json:"items,omitempty" db:"-"`
+}
+
+type StockTransferItem struct {
+	ID               int    `json:"id" db:"id"`
+	TransferID       int    `json:"transfer_id" db:"transfer_id"`
+	ItemID           int    `json:"item_id" db:"item_id"`
+	SKU              string `json:"sku" db:"sku"`
+	Name             string `json:"name" db:"name"`
+	Quantity         int    `json:"quantity" db:"quantity"`
+	ReceivedQuantity int    `json:"received_quantity" db:"received_quantity"`
+}
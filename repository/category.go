@@ -35,9 +35,9 @@ func NewCategoryRepository(db database.PgxIface, log *zap.Logger) CategoryReposi
 
 func (r *categoryRepository) Create(ctx context.Context, ctg *model.Category) (*model.Category, error) {
 	query := `
-		INSERT INTO categories (code, name, description, is_active, created_by)
-		VALUES ($1, $2, $3, true, $4)
-		RETURNING id, code, name, description, is_active, created_by, created_at, updated_at
+		INSERT INTO categories (code, name, description, tax_rate_id, is_active, created_by)
+		VALUES ($1, $2, $3, $4, true, $5)
+		RETURNING id, code, name, description, tax_rate_id, is_active, created_by, created_at, updated_at
 	`
 
 	var ctgr model.Category
@@ -45,12 +45,14 @@ func (r *categoryRepository) Create(ctx context.Context, ctg *model.Category) (*
 		ctg.Code,
 		ctg.Name,
 		ctg.Description,
+		ctg.TaxRateID,
 		ctg.CreatedBy,
 	).Scan(
 		&ctgr.ID,
 		&ctgr.Code,
 		&ctgr.Name,
 		&ctgr.Description,
+		&ctgr.TaxRateID,
 		&ctgr.IsActive,
 		&ctgr.CreatedBy,
 		&ctgr.CreatedAt,
@@ -76,7 +78,7 @@ func (r *categoryRepository) Lists(page, limit int) ([]model.Category, int, erro
 	}
 
 	query := `
-			SELECT id, code, name, description, is_active, created_by, created_at, updated_at
+			SELECT id, code, name, description, tax_rate_id, is_active, created_by, created_at, updated_at
 			FROM categories
 			ORDER BY created_at DESC
 			LIMIT $1 OFFSET $2
@@ -100,6 +102,7 @@ func (r *categoryRepository) Lists(page, limit int) ([]model.Category, int, erro
 			&ctg.Code,
 			&ctg.Name,
 			&ctg.Description,
+			&ctg.TaxRateID,
 			&ctg.IsActive,
 			&ctg.CreatedBy,
 			&ctg.CreatedAt,
@@ -116,7 +119,7 @@ func (r *categoryRepository) Lists(page, limit int) ([]model.Category, int, erro
 
 func (r *categoryRepository) DetailById(id int) (*model.Category, error) {
 	query := `
-	SELECT id, code, name, description, is_active, created_by, created_at, updated_at
+	SELECT id, code, name, description, tax_rate_id, is_active, created_by, created_at, updated_at
 	FROM categories WHERE id = $1
 	`
 
@@ -127,6 +130,7 @@ func (r *categoryRepository) DetailById(id int) (*model.Category, error) {
 		&ct.Code,
 		&ct.Name,
 		&ct.Description,
+		&ct.TaxRateID,
 		&ct.IsActive,
 		&ct.CreatedBy,
 		&ct.CreatedAt,
@@ -147,10 +151,11 @@ func (r *categoryRepository) Update(ctx context.Context, id int, payload *model.
 			code = $1,
 			name = $2,
 			description = $3,
-			is_active = $4,
+			tax_rate_id = $4,
+			is_active = $5,
 			updated_at = CURRENT_TIMESTAMP
-		WHERE id = $5
-		RETURNING id, code, name, description, is_active, created_by, created_at, updated_at
+		WHERE id = $6
+		RETURNING id, code, name, description, tax_rate_id, is_active, created_by, created_at, updated_at
 	`
 
 	var category model.Category
@@ -159,6 +164,7 @@ func (r *categoryRepository) Update(ctx context.Context, id int, payload *model.
 		payload.Code,
 		payload.Name,
 		payload.Description,
+		payload.TaxRateID,
 		payload.IsActive,
 		id,
 	).Scan(
@@ -166,6 +172,7 @@ func (r *categoryRepository) Update(ctx context.Context, id int, payload *model.
 		&category.Code,
 		&category.Name,
 		&category.Description,
+		&category.TaxRateID,
 		&category.IsActive,
 		&category.CreatedBy,
 		&category.CreatedAt,
@@ -7,24 +7,64 @@ import (
 )
 
 type Container struct {
-	UserRepo      UserRepository
-	CategoryRepo  CategoryRepository
-	WarehouseRepo WarehouseRepository
-	RacksRepo     RacksRepository
-	ItemsRepo     ItemsRepository
-	SaleRepo      SaleRepository
+	UserRepo         UserRepository
+	CategoryRepo     CategoryRepository
+	TaxRateRepo      TaxRateRepository
+	PromotionRepo    PromotionRepository
+	PriceListRepo    PriceListRepository
+	PriceHistoryRepo PriceHistoryRepository
+	WarehouseRepo    WarehouseRepository
+	RacksRepo        RacksRepository
+	ItemsRepo        ItemsRepository
+	SaleRepo         SaleRepository
+	CustomerRepo     CustomerRepository
+	SupplierRepo     SupplierRepository
+
+	SaleReturnRepo  SaleReturnRepository
+	SalePaymentRepo SalePaymentRepository
+
+	ItemLocationRepo  ItemLocationRepository
+	StockMovementRepo StockMovementRepository
+	TransferRepo      TransferRepository
+
+	PurchaseOrderRepo  PurchaseOrderRepository
+	GoodsReceiptRepo   GoodsReceiptRepository
+	SupplierReturnRepo SupplierReturnRepository
+
+	NotificationRepo NotificationRepository
+	ReportRepo       ReportRepository
 
 	SessionRepo SessionRepository
 }
 
 func NewContainer(db database.PgxIface, log *zap.Logger) *Container {
 	return &Container{
-		UserRepo:      NewUserRepository(db, log),
-		CategoryRepo:  NewCategoryRepository(db, log),
-		WarehouseRepo: NewWarehouseRepository(db, log),
-		RacksRepo:     NewRacksRepository(db, log),
-		ItemsRepo:     NewItemsRepository(db, log),
-		SaleRepo:      NewSaleRepository(db, log),
+		UserRepo:         NewUserRepository(db, log),
+		CategoryRepo:     NewCategoryRepository(db, log),
+		TaxRateRepo:      NewTaxRateRepository(db, log),
+		PromotionRepo:    NewPromotionRepository(db, log),
+		PriceListRepo:    NewPriceListRepository(db, log),
+		PriceHistoryRepo: NewPriceHistoryRepository(db, log),
+		WarehouseRepo:    NewWarehouseRepository(db, log),
+		RacksRepo:        NewRacksRepository(db, log),
+		ItemsRepo:        NewItemsRepository(db, log),
+		SaleRepo:         NewSaleRepository(db, log),
+		CustomerRepo:     NewCustomerRepository(db, log),
+		SupplierRepo:     NewSupplierRepository(db, log),
+
+		SaleReturnRepo:  NewSaleReturnRepository(db, log),
+		SalePaymentRepo: NewSalePaymentRepository(db, log),
+
+		ItemLocationRepo:  NewItemLocationRepository(db, log),
+		StockMovementRepo: NewStockMovementRepository(db, log),
+		TransferRepo:      NewTransferRepository(db, log),
+
+		PurchaseOrderRepo:  NewPurchaseOrderRepository(db, log),
+		GoodsReceiptRepo:   NewGoodsReceiptRepository(db, log),
+		SupplierReturnRepo: NewSupplierReturnRepository(db, log),
+
+		NotificationRepo: NewNotificationRepository(db, log),
+		ReportRepo:       NewReportRepository(db, log),
 
 		SessionRepo: NewSessionRepository(db),
 		// SessionRepo: NewSessionRepository(db, log),
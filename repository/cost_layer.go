@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/model"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type CostLayerRepository interface {
+	Create(ctx context.Context, layer *model.CostLayer) error
+
+	// lapisan yang masih bersisa, urut FIFO dan di-lock
+	LockOpen(ctx context.Context, itemID int) ([]model.CostLayer, error)
+	Consume(ctx context.Context, id, qty int, value model.Money) error
+}
+
+type costLayerRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewCostLayerRepository(db DBTX, log *zap.Logger) CostLayerRepository {
+	return &costLayerRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+func (r *costLayerRepository) Create(ctx context.Context, layer *model.CostLayer) error {
+	query := `
+	INSERT INTO cost_layers (item_id, movement_id, quantity, remaining_quantity, unit_cost, remaining_value)
+	VALUES ($1, $2, $3, $3, $4, $5)
+	RETURNING id, remaining_quantity, received_at
+	`
+
+	err := r.DB.QueryRow(ctx, query,
+		layer.ItemID,
+		layer.MovementID,
+		layer.Quantity,
+		layer.UnitCost,
+		layer.RemainingValue,
+	).Scan(&layer.ID, &layer.RemainingQuantity, &layer.ReceivedAt)
+
+	if err != nil {
+		r.Logger.Error("failed to create cost layer", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *costLayerRepository) LockOpen(ctx context.Context, itemID int) ([]model.CostLayer, error) {
+	query := `
+	SELECT id, item_id, movement_id, quantity, remaining_quantity, unit_cost, remaining_value, received_at
+	FROM cost_layers
+	WHERE item_id = $1 AND remaining_quantity > 0
+	ORDER BY received_at, id
+	FOR UPDATE
+	`
+
+	rows, err := r.DB.Query(ctx, query, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var layers []model.CostLayer
+	for rows.Next() {
+		var l model.CostLayer
+		if err := rows.Scan(
+			&l.ID,
+			&l.ItemID,
+			&l.MovementID,
+			&l.Quantity,
+			&l.RemainingQuantity,
+			&l.UnitCost,
+			&l.RemainingValue,
+			&l.ReceivedAt,
+		); err != nil {
+			return nil, err
+		}
+		layers = append(layers, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return layers, nil
+}
+
+func (r *costLayerRepository) Consume(ctx context.Context, id, qty int, value model.Money) error {
+	_, err := r.DB.Exec(ctx, `
+	UPDATE cost_layers
+	SET remaining_quantity = remaining_quantity - $2, remaining_value = remaining_value - $3
+	WHERE id = $1
+	`, id, qty, value)
+	return err
+}
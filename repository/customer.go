@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type CustomerRepository interface {
+	Create(ctx context.Context, c *model.Customer) (*model.Customer, error)
+	Lists(ctx context.Context, search string, page, limit int) ([]model.Customer, int, error)
+	FindByID(ctx context.Context, id int) (*model.Customer, error)
+	Update(ctx context.Context, c *model.Customer) (*model.Customer, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type customerRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewCustomerRepository(db DBTX, log *zap.Logger) CustomerRepository {
+	return &customerRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+const customerColumns = `id, name, phone, email, address, notes, price_list_id, is_active, created_by, created_at, updated_at`
+
+func scanCustomer(row pgx.Row, c *model.Customer) error {
+	return row.Scan(
+		&c.ID,
+		&c.Name,
+		&c.Phone,
+		&c.Email,
+		&c.Address,
+		&c.Notes,
+		&c.PriceListID,
+		&c.IsActive,
+		&c.CreatedBy,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+}
+
+func (r *customerRepository) Create(ctx context.Context, c *model.Customer) (*model.Customer, error) {
+	query := `
+	INSERT INTO customers (name, phone, email, address, notes, price_list_id, is_active, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, true, $7)
+	RETURNING ` + customerColumns
+
+	var cust model.Customer
+	err := scanCustomer(r.DB.QueryRow(ctx, query,
+		c.Name,
+		c.Phone,
+		c.Email,
+		c.Address,
+		c.Notes,
+		c.PriceListID,
+		c.CreatedBy,
+	), &cust)
+
+	if err != nil {
+		r.Logger.Error("failed create customer", zap.Error(err))
+		return nil, err
+	}
+
+	r.Logger.Info("customer created successfully", zap.Int("id", cust.ID))
+	return &cust, nil
+}
+
+// search cocokkan nama, phone atau email
+func (r *customerRepository) Lists(ctx context.Context, search string, page, limit int) ([]model.Customer, int, error) {
+	offset := (page - 1) * limit
+
+	where := `WHERE is_active = true`
+	args := []any{}
+	if search != "" {
+		args = append(args, "%"+search+"%")
+		where += ` AND (name ILIKE $1 OR phone ILIKE $1 OR email ILIKE $1)`
+	}
+
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM customers `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+	SELECT %s FROM customers %s
+	ORDER BY name
+	LIMIT $%d OFFSET $%d
+	`, customerColumns, where, len(args)-1, len(args))
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var customers []model.Customer
+	for rows.Next() {
+		var c model.Customer
+		if err := scanCustomer(rows, &c); err != nil {
+			return nil, 0, err
+		}
+		customers = append(customers, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return customers, total, nil
+}
+
+func (r *customerRepository) FindByID(ctx context.Context, id int) (*model.Customer, error) {
+	var c model.Customer
+	err := scanCustomer(r.DB.QueryRow(ctx, `SELECT `+customerColumns+` FROM customers WHERE id = $1`, id), &c)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("customer not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (r *customerRepository) Update(ctx context.Context, c *model.Customer) (*model.Customer, error) {
+	query := `
+	UPDATE customers
+	SET name = $1, phone = $2, email = $3, address = $4, notes = $5, price_list_id = $6, is_active = $7
+	WHERE id = $8
+	RETURNING ` + customerColumns
+
+	var cust model.Customer
+	err := scanCustomer(r.DB.QueryRow(ctx, query,
+		c.Name,
+		c.Phone,
+		c.Email,
+		c.Address,
+		c.Notes,
+		c.PriceListID,
+		c.IsActive,
+		c.ID,
+	), &cust)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("customer not found")
+	}
+	if err != nil {
+		r.Logger.Error("failed update customer", zap.Error(err))
+		return nil, err
+	}
+
+	return &cust, nil
+}
+
+// soft delete, riwayat sale tetap menunjuk ke customer
+func (r *customerRepository) Delete(ctx context.Context, id int) error {
+	query := `
+	UPDATE customers
+	SET is_active = false
+	WHERE id = $1 AND is_active = true
+	`
+
+	res, err := r.DB.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("customer not found or already deleted")
+	}
+
+	return nil
+}
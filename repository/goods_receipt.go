@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/model"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type GoodsReceiptRepository interface {
+	Create(ctx context.Context, gr *model.GoodsReceipt) (*model.GoodsReceipt, error)
+	CreateItem(ctx context.Context, it *model.GoodsReceiptItem) error
+	// semua penerimaan satu PO beserta barisnya, urut tanggal terima
+	ListsByPurchaseOrder(ctx context.Context, purchaseOrderID int) ([]model.GoodsReceipt, error)
+	FindByID(ctx context.Context, id int) (*model.GoodsReceipt, error)
+
+	// catat qty yang diretur ke supplier, tidak boleh melebihi qty diterima
+	AddReturned(ctx context.Context, lineID, qty int) error
+}
+
+type goodsReceiptRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewGoodsReceiptRepository(db DBTX, log *zap.Logger) GoodsReceiptRepository {
+	return &goodsReceiptRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+const goodsReceiptColumns = `id, grn_number, purchase_order_id, rack_id, received_date, total_amount, notes, received_by, created_at`
+
+func scanGoodsReceipt(row pgx.Row, gr *model.GoodsReceipt) error {
+	return row.Scan(
+		&gr.ID,
+		&gr.GRNNumber,
+		&gr.PurchaseOrderID,
+		&gr.RackID,
+		&gr.ReceivedDate,
+		&gr.TotalAmount,
+		&gr.Notes,
+		&gr.ReceivedBy,
+		&gr.CreatedAt,
+	)
+}
+
+const goodsReceiptItemSelect = `
+	SELECT gri.id, gri.goods_receipt_id, gri.purchase_order_item_id, gri.item_id, i.sku, i.name,
+		gri.quantity, gri.returned_quantity, gri.unit_cost, gri.subtotal
+	FROM goods_receipt_items gri
+	JOIN items i ON i.id = gri.item_id
+	`
+
+func scanGoodsReceiptItem(row pgx.Row, it *model.GoodsReceiptItem) error {
+	return row.Scan(
+		&it.ID,
+		&it.GoodsReceiptID,
+		&it.PurchaseOrderItemID,
+		&it.ItemID,
+		&it.SKU,
+		&it.Name,
+		&it.Quantity,
+		&it.ReturnedQuantity,
+		&it.UnitCost,
+		&it.Subtotal,
+	)
+}
+
+func (r *goodsReceiptRepository) Create(ctx context.Context, gr *model.GoodsReceipt) (*model.GoodsReceipt, error) {
+	query := `
+	INSERT INTO goods_receipts (grn_number, purchase_order_id, rack_id, received_date, total_amount, notes, received_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING ` + goodsReceiptColumns
+
+	var receipt model.GoodsReceipt
+	err := scanGoodsReceipt(r.DB.QueryRow(ctx, query,
+		gr.GRNNumber,
+		gr.PurchaseOrderID,
+		gr.RackID,
+		gr.ReceivedDate,
+		gr.TotalAmount,
+		gr.Notes,
+		gr.ReceivedBy,
+	), &receipt)
+
+	if err != nil {
+		r.Logger.Error("failed to create goods receipt", zap.Error(err))
+		return nil, err
+	}
+
+	r.Logger.Info("goods receipt created", zap.Int("id", receipt.ID))
+	return &receipt, nil
+}
+
+func (r *goodsReceiptRepository) CreateItem(ctx context.Context, it *model.GoodsReceiptItem) error {
+	query := `
+	INSERT INTO goods_receipt_items (goods_receipt_id, purchase_order_item_id, item_id, quantity, unit_cost, subtotal)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id;
+	`
+
+	err := r.DB.QueryRow(ctx, query,
+		it.GoodsReceiptID,
+		it.PurchaseOrderItemID,
+		it.ItemID,
+		it.Quantity,
+		it.UnitCost,
+		it.Subtotal,
+	).Scan(&it.ID)
+
+	if err != nil {
+		r.Logger.Error("failed to create goods receipt item", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *goodsReceiptRepository) ListsByPurchaseOrder(ctx context.Context, purchaseOrderID int) ([]model.GoodsReceipt, error) {
+	rows, err := r.DB.Query(ctx, `
+	SELECT `+goodsReceiptColumns+` FROM goods_receipts
+	WHERE purchase_order_id = $1
+	ORDER BY received_date, id
+	`, purchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receipts []model.GoodsReceipt
+	index := map[int]int{}
+	for rows.Next() {
+		var gr model.GoodsReceipt
+		if err := scanGoodsReceipt(rows, &gr); err != nil {
+			return nil, err
+		}
+		index[gr.ID] = len(receipts)
+		receipts = append(receipts, gr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(receipts) == 0 {
+		return receipts, nil
+	}
+
+	// baris semua GRN diambil sekali lalu dibagi per GRN
+	lines, err := r.DB.Query(ctx, goodsReceiptItemSelect+`
+	JOIN goods_receipts gr ON gr.id = gri.goods_receipt_id
+	WHERE gr.purchase_order_id = $1
+	ORDER BY gri.goods_receipt_id, gri.item_id
+	`, purchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer lines.Close()
+
+	for lines.Next() {
+		var it model.GoodsReceiptItem
+		if err := scanGoodsReceiptItem(lines, &it); err != nil {
+			return nil, err
+		}
+
+		i := index[it.GoodsReceiptID]
+		receipts[i].Items = append(receipts[i].Items, it)
+	}
+
+	return receipts, lines.Err()
+}
+
+func (r *goodsReceiptRepository) FindByID(ctx context.Context, id int) (*model.GoodsReceipt, error) {
+	var gr model.GoodsReceipt
+	err := scanGoodsReceipt(r.DB.QueryRow(ctx, `SELECT `+goodsReceiptColumns+` FROM goods_receipts WHERE id = $1`, id), &gr)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("goods receipt not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.DB.Query(ctx, goodsReceiptItemSelect+`WHERE gri.goods_receipt_id = $1 ORDER BY gri.item_id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var it model.GoodsReceiptItem
+		if err := scanGoodsReceiptItem(rows, &it); err != nil {
+			return nil, err
+		}
+		gr.Items = append(gr.Items, it)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &gr, nil
+}
+
+func (r *goodsReceiptRepository) AddReturned(ctx context.Context, lineID, qty int) error {
+	query := `
+	UPDATE goods_receipt_items
+	SET returned_quantity = returned_quantity + $1
+	WHERE id = $2 AND returned_quantity + $1 <= quantity
+	`
+
+	res, err := r.DB.Exec(ctx, query, qty, lineID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("returned quantity exceeds received quantity")
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/model"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type ItemLocationRepository interface {
+	FindByItem(ctx context.Context, itemID int) ([]model.ItemLocation, error)
+	LockAvailable(ctx context.Context, itemID int, warehouseID *int) ([]model.ItemLocation, error)
+	AdjustQuantity(ctx context.Context, itemID, rackID, delta int) (int, error)
+}
+
+type itemLocationRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewItemLocationRepository(db DBTX, log *zap.Logger) ItemLocationRepository {
+	return &itemLocationRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+const itemLocationSelect = `
+	SELECT
+		l.id, l.item_id, l.rack_id, l.quantity,
+		r.code, r.name, w.id, w.code, w.name,
+		l.updated_at
+	FROM item_locations l
+	JOIN racks r ON r.id = l.rack_id
+	JOIN warehouses w ON w.id = r.warehouse_id
+`
+
+func (r *itemLocationRepository) FindByItem(ctx context.Context, itemID int) ([]model.ItemLocation, error) {
+	query := itemLocationSelect + `
+	WHERE l.item_id = $1
+	ORDER BY w.id, r.id
+	`
+
+	return r.scanLocations(ctx, query, itemID)
+}
+
+// lock baris stok per rak yang masih ada isinya, urut rack_id supaya urutan lock konsisten.
+// warehouseID opsional untuk membatasi ke rak di satu gudang
+func (r *itemLocationRepository) LockAvailable(ctx context.Context, itemID int, warehouseID *int) ([]model.ItemLocation, error) {
+	query := itemLocationSelect + `
+	WHERE l.item_id = $1 AND l.quantity > 0
+	  AND ($2::int IS NULL OR r.warehouse_id = $2)
+	ORDER BY l.rack_id
+	FOR UPDATE OF l
+	`
+
+	return r.scanLocations(ctx, query, itemID, warehouseID)
+}
+
+// tambah/kurang stok di satu rak, return qty terbaru di rak tsb
+func (r *itemLocationRepository) AdjustQuantity(ctx context.Context, itemID, rackID, delta int) (int, error) {
+	var qty int
+
+	if delta > 0 {
+		query := `
+		INSERT INTO item_locations (item_id, rack_id, quantity)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (item_id, rack_id)
+		DO UPDATE SET quantity = item_locations.quantity + EXCLUDED.quantity,
+		              updated_at = CURRENT_TIMESTAMP
+		RETURNING quantity
+		`
+		if err := r.DB.QueryRow(ctx, query, itemID, rackID, delta).Scan(&qty); err != nil {
+			r.Logger.Error("failed to add item location stock", zap.Error(err))
+			return 0, err
+		}
+		return qty, nil
+	}
+
+	query := `
+	UPDATE item_locations
+	SET quantity = quantity + $1,
+	    updated_at = CURRENT_TIMESTAMP
+	WHERE item_id = $2 AND rack_id = $3 AND quantity + $1 >= 0
+	RETURNING quantity
+	`
+	err := r.DB.QueryRow(ctx, query, delta, itemID, rackID).Scan(&qty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, errors.New("stock not enough in rack")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return qty, nil
+}
+
+func (r *itemLocationRepository) scanLocations(ctx context.Context, query string, args ...any) ([]model.ItemLocation, error) {
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var locations []model.ItemLocation
+	for rows.Next() {
+		var loc model.ItemLocation
+		if err := rows.Scan(
+			&loc.ID,
+			&loc.ItemID,
+			&loc.RackID,
+			&loc.Quantity,
+			&loc.RackCode,
+			&loc.RackName,
+			&loc.WarehouseID,
+			&loc.WarehouseCode,
+			&loc.WarehouseName,
+			&loc.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		locations = append(locations, loc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return locations, nil
+}
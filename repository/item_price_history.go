@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/model"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type PriceHistoryRepository interface {
+	Create(ctx context.Context, c *model.ItemPriceChange) (*model.ItemPriceChange, error)
+	ListsByItem(ctx context.Context, itemID, page, limit int) ([]model.ItemPriceChange, int, error)
+
+	// perubahan terjadwal yang sudah jatuh tempo, di-lock (skip yang sedang diproses worker lain)
+	DueScheduled(ctx context.Context, now time.Time, limit int) ([]model.ItemPriceChange, error)
+	// old kosong untuk field yang tidak ikut dijadwalkan
+	MarkApplied(ctx context.Context, id int, oldPrice, oldCost *model.Money) error
+	Cancel(ctx context.Context, itemID, id int) error
+}
+
+type priceHistoryRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewPriceHistoryRepository(db DBTX, log *zap.Logger) PriceHistoryRepository {
+	return &priceHistoryRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+const priceChangeColumns = `id, item_id, old_price, new_price, old_cost, new_cost, effective_at, status, reason, applied_at, created_by, created_at`
+
+func scanPriceChange(row pgx.Row, c *model.ItemPriceChange) error {
+	return row.Scan(
+		&c.ID,
+		&c.ItemID,
+		&c.OldPrice,
+		&c.NewPrice,
+		&c.OldCost,
+		&c.NewCost,
+		&c.EffectiveAt,
+		&c.Status,
+		&c.Reason,
+		&c.AppliedAt,
+		&c.CreatedBy,
+		&c.CreatedAt,
+	)
+}
+
+func (r *priceHistoryRepository) Create(ctx context.Context, c *model.ItemPriceChange) (*model.ItemPriceChange, error) {
+	query := `
+	INSERT INTO item_price_history (item_id, old_price, new_price, old_cost, new_cost, effective_at, status, reason, applied_at, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING ` + priceChangeColumns
+
+	var change model.ItemPriceChange
+	err := scanPriceChange(r.DB.QueryRow(ctx, query,
+		c.ItemID,
+		c.OldPrice,
+		c.NewPrice,
+		c.OldCost,
+		c.NewCost,
+		c.EffectiveAt,
+		c.Status,
+		c.Reason,
+		c.AppliedAt,
+		c.CreatedBy,
+	), &change)
+
+	if err != nil {
+		r.Logger.Error("failed create price history", zap.Error(err))
+		return nil, err
+	}
+
+	return &change, nil
+}
+
+// terbaru dulu, termasuk yang masih terjadwal
+func (r *priceHistoryRepository) ListsByItem(ctx context.Context, itemID, page, limit int) ([]model.ItemPriceChange, int, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM item_price_history WHERE item_id = $1`, itemID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.DB.Query(ctx, `
+	SELECT `+priceChangeColumns+` FROM item_price_history
+	WHERE item_id = $1
+	ORDER BY effective_at DESC, id DESC
+	LIMIT $2 OFFSET $3
+	`, itemID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var changes []model.ItemPriceChange
+	for rows.Next() {
+		var c model.ItemPriceChange
+		if err := scanPriceChange(rows, &c); err != nil {
+			return nil, 0, err
+		}
+		changes = append(changes, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return changes, total, nil
+}
+
+func (r *priceHistoryRepository) DueScheduled(ctx context.Context, now time.Time, limit int) ([]model.ItemPriceChange, error) {
+	// urut effective_at supaya dua jadwal untuk item yang sama diterapkan berurutan
+	rows, err := r.DB.Query(ctx, `
+	SELECT `+priceChangeColumns+` FROM item_price_history
+	WHERE status = 'scheduled' AND effective_at <= $1
+	ORDER BY effective_at, id
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+	`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []model.ItemPriceChange
+	for rows.Next() {
+		var c model.ItemPriceChange
+		if err := scanPriceChange(rows, &c); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func (r *priceHistoryRepository) MarkApplied(ctx context.Context, id int, oldPrice, oldCost *model.Money) error {
+	_, err := r.DB.Exec(ctx, `
+	UPDATE item_price_history
+	SET status = 'applied', old_price = $2, old_cost = $3, applied_at = CURRENT_TIMESTAMP
+	WHERE id = $1
+	`, id, oldPrice, oldCost)
+	return err
+}
+
+// hanya jadwal yang belum diterapkan yang bisa dibatalkan
+func (r *priceHistoryRepository) Cancel(ctx context.Context, itemID, id int) error {
+	res, err := r.DB.Exec(ctx, `
+	UPDATE item_price_history SET status = 'cancelled'
+	WHERE id = $1 AND item_id = $2 AND status = 'scheduled'
+	`, id, itemID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("scheduled price change not found")
+	}
+
+	return nil
+}
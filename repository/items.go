@@ -5,7 +5,10 @@ import (
 	"alfdwirhmn/inventory/model"
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
@@ -16,7 +19,11 @@ type ItemsRepository interface {
 	Delete(ctx context.Context, id int) error
 
 	FindByID(ctx context.Context, id int) (*model.Item, error)
-	ReduceStock(ctx context.Context, itemID int, qty int) error
+	LockByIDs(ctx context.Context, ids []int) ([]model.Item, error)
+	SyncStock(ctx context.Context, itemID int) (int, error)
+
+	// item aktif dengan stok di bawah minimum_stock
+	LowStock(ctx context.Context, filter dto.LowStockFilter, page, limit int) ([]model.LowStockItem, int, error)
 }
 
 type itemsRepository struct {
@@ -37,22 +44,23 @@ func (r *itemsRepository) Create(ctx context.Context, itm *model.Item) (*model.I
 		INSERT INTO items (
 			category_id, rack_id, sku, name, description,
 			unit, price, cost, stock, minimum_stock,
-			weight, dimensions, is_active, created_by
+			weight, dimensions, is_active, created_by, tax_rate_id
 		)
 		VALUES (
 			$1, $2, $3, $4, $5,
 			COALESCE($6, 'pcs'),
 			$7,
-			COALESCE($8, 0),
+			COALESCE($8::numeric, 0),
 			COALESCE($9, 0),
 			COALESCE($10, 5),
 			COALESCE($11, 0),
 			$12,
 			true,
-			$13
+			$13,
+			$14
 		)
 		RETURNING
-			id, category_id, rack_id, sku, name, description,
+			id, category_id, rack_id, tax_rate_id, sku, name, description,
 			unit, price, cost, stock, minimum_stock,
 			weight, dimensions, is_active,
 			created_by, created_at, updated_at
@@ -73,10 +81,12 @@ func (r *itemsRepository) Create(ctx context.Context, itm *model.Item) (*model.I
 		itm.Weight,
 		itm.Dimensions,
 		itm.CreatedBy,
+		itm.TaxRateID,
 	).Scan(
 		&items.ID,
 		&items.CategoryID,
 		&items.RackID,
+		&items.TaxRateID,
 		&items.SKU,
 		&items.Name,
 		&items.Description,
@@ -118,7 +128,7 @@ func (r *itemsRepository) Lists(page, limit int) ([]model.Item, int, error) {
 
 	query := `
 		SELECT
-			id, category_id, rack_id, sku, name, description,
+			id, category_id, rack_id, tax_rate_id, sku, name, description,
 			unit, price, cost, stock, minimum_stock,
 			weight, dimensions, is_active,
 			created_by, created_at, updated_at
@@ -143,6 +153,7 @@ func (r *itemsRepository) Lists(page, limit int) ([]model.Item, int, error) {
 			&itm.ID,
 			&itm.CategoryID,
 			&itm.RackID,
+			&itm.TaxRateID,
 			&itm.SKU,
 			&itm.Name,
 			&itm.Description,
@@ -185,16 +196,16 @@ func (r *itemsRepository) Update(ctx context.Context, id int, req dto.UpdateItem
 		unit          = COALESCE($6, unit),
 		price         = COALESCE($7, price),
 		cost          = COALESCE($8, cost),
-		stock         = COALESCE($9, stock),
-		minimum_stock = COALESCE($10, minimum_stock),
-		weight        = COALESCE($11, weight),
-		dimensions    = COALESCE($12, dimensions),
-		is_active     = COALESCE($13, is_active),
+		minimum_stock = COALESCE($9, minimum_stock),
+		weight        = COALESCE($10, weight),
+		dimensions    = COALESCE($11, dimensions),
+		is_active     = COALESCE($12, is_active),
+		tax_rate_id   = COALESCE($13, tax_rate_id),
 		updated_at    = CURRENT_TIMESTAMP
 	WHERE id = $14
 	AND is_active = true
 	RETURNING
-			id, category_id, rack_id, sku, name, description,
+			id, category_id, rack_id, tax_rate_id, sku, name, description,
 			unit, price, cost, stock, minimum_stock,
 			weight, dimensions, is_active,
 			created_by, created_at, updated_at
@@ -211,16 +222,17 @@ func (r *itemsRepository) Update(ctx context.Context, id int, req dto.UpdateItem
 		req.Unit,
 		req.Price,
 		req.Cost,
-		req.Stock,
 		req.MinimumStock,
 		req.Weight,
 		req.Dimensions,
 		req.IsActive,
+		req.TaxRateID,
 		id,
 	).Scan(
 		&item.ID,
 		&item.CategoryID,
 		&item.RackID,
+		&item.TaxRateID,
 		&item.SKU,
 		&item.Name,
 		&item.Description,
@@ -265,16 +277,17 @@ func (r *itemsRepository) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
-func (r *itemsRepository) FindByID(ctx context.Context, id int) (*model.Item, error) {
-	query := `SELECT id, category_id, rack_id, sku, name, description, price, unit, cost, stock, minimum_stock, weight, dimensions, is_active, created_by, created_at, updated_at
-			FROM items 
-			WHERE id = $1`
+const itemSelect = `
+	SELECT id, category_id, rack_id, tax_rate_id, sku, name, description, price, unit, cost, stock, minimum_stock, weight, dimensions, is_active, created_by, created_at, updated_at
+	FROM items
+`
 
-	item := &model.Item{}
-	err := r.DB.QueryRow(ctx, query, id).Scan(
+func scanItem(row pgx.Row, item *model.Item) error {
+	return row.Scan(
 		&item.ID,
 		&item.CategoryID,
 		&item.RackID,
+		&item.TaxRateID,
 		&item.SKU,
 		&item.Name,
 		&item.Description,
@@ -290,27 +303,141 @@ func (r *itemsRepository) FindByID(ctx context.Context, id int) (*model.Item, er
 		&item.CreatedAt,
 		&item.UpdatedAt,
 	)
-	if err != nil {
+}
+
+func (r *itemsRepository) FindByID(ctx context.Context, id int) (*model.Item, error) {
+	item := &model.Item{}
+	if err := scanItem(r.DB.QueryRow(ctx, itemSelect+`WHERE id = $1`, id), item); err != nil {
 		return nil, err
 	}
 	return item, nil
 }
 
-func (r *itemsRepository) ReduceStock(ctx context.Context, itemID, qty int) error {
+// lock baris items selalu urut id supaya transaksi paralel tidak deadlock
+func (r *itemsRepository) LockByIDs(ctx context.Context, ids []int) ([]model.Item, error) {
+	rows, err := r.DB.Query(ctx, itemSelect+`WHERE id = ANY($1) ORDER BY id FOR UPDATE`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.Item
+	for rows.Next() {
+		var item model.Item
+		if err := scanItem(rows, &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// stok item = total stok semua rak, return stok terbaru
+func (r *itemsRepository) SyncStock(ctx context.Context, itemID int) (int, error) {
 	q := `
 	UPDATE items
-	SET stock = stock - $1
-	WHERE id = $2 AND stock >= $1
+	SET stock = (
+		SELECT COALESCE(SUM(quantity), 0)
+		FROM item_locations
+		WHERE item_id = $1
+	),
+	    updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1
+	RETURNING stock
 	`
 
-	res, err := r.DB.Exec(ctx, q, qty, itemID)
+	var stock int
+	err := r.DB.QueryRow(ctx, q, itemID).Scan(&stock)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, errors.New("item not found")
+	}
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	return stock, nil
+}
+
+func (r *itemsRepository) LowStock(ctx context.Context, filter dto.LowStockFilter, page, limit int) ([]model.LowStockItem, int, error) {
+	offset := (page - 1) * limit
+
+	// tanpa filter gudang dibandingkan dengan total stok item,
+	// dengan filter gudang hanya item yang punya lokasi di gudang tsb & stok di gudang itu
+	stock := "i.stock"
+	from := `
+	FROM items i
+	JOIN categories c ON c.id = i.category_id
+	`
+	var args []any
+	conds := []string{"i.is_active = true"}
+
+	if filter.WarehouseID != 0 {
+		args = append(args, filter.WarehouseID)
+		from += fmt.Sprintf(`
+	CROSS JOIN LATERAL (
+		SELECT COUNT(*) AS racks, COALESCE(SUM(il.quantity), 0)::int AS quantity
+		FROM item_locations il
+		JOIN racks rk ON rk.id = il.rack_id
+		WHERE il.item_id = i.id AND rk.warehouse_id = $%d
+	) ws
+	`, len(args))
+		stock = "ws.quantity"
+		conds = append(conds, "ws.racks > 0")
+	}
+	if filter.CategoryID != 0 {
+		args = append(args, filter.CategoryID)
+		conds = append(conds, fmt.Sprintf("i.category_id = $%d", len(args)))
 	}
+	conds = append(conds, stock+" < i.minimum_stock")
+
+	where := "WHERE " + strings.Join(conds, " AND ")
 
-	if res.RowsAffected() == 0 {
-		return errors.New("stock not enough")
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) `+from+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
 	}
 
-	return nil
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+	SELECT i.id, i.sku, i.name, i.category_id, c.name, i.unit, %[1]s, i.minimum_stock, i.minimum_stock - %[1]s
+	`, stock) + from + where + fmt.Sprintf(`
+	ORDER BY i.minimum_stock - %s DESC, i.sku
+	LIMIT $%d OFFSET $%d
+	`, stock, len(args)-1, len(args))
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var items []model.LowStockItem
+	for rows.Next() {
+		var it model.LowStockItem
+		if err := rows.Scan(
+			&it.ItemID,
+			&it.SKU,
+			&it.Name,
+			&it.CategoryID,
+			&it.CategoryName,
+			&it.Unit,
+			&it.Stock,
+			&it.MinimumStock,
+			&it.Shortage,
+		); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, it)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
 }
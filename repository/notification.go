@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type NotificationRepository interface {
+	Lists(ctx context.Context, filter dto.NotificationFilter, page, limit int) ([]model.Notification, int, error)
+	FindByID(ctx context.Context, id int) (*model.Notification, error)
+	MarkRead(ctx context.Context, id, userID int) (*model.Notification, error)
+
+	// buka notifikasi untuk item yang baru turun di bawah minimum
+	CreateLowStock(ctx context.Context) ([]model.Notification, error)
+	// tutup notifikasi item yang stoknya sudah kembali normal
+	ResolveLowStock(ctx context.Context) (int, error)
+}
+
+type notificationRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewNotificationRepository(db DBTX, log *zap.Logger) NotificationRepository {
+	return &notificationRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+const notificationColumns = `id, type, item_id, movement_id, message, stock, minimum_stock, is_read, read_by, read_at, resolved_at, created_at`
+
+func scanNotification(row pgx.Row, n *model.Notification) error {
+	return row.Scan(
+		&n.ID,
+		&n.Type,
+		&n.ItemID,
+		&n.MovementID,
+		&n.Message,
+		&n.Stock,
+		&n.MinimumStock,
+		&n.IsRead,
+		&n.ReadBy,
+		&n.ReadAt,
+		&n.ResolvedAt,
+		&n.CreatedAt,
+	)
+}
+
+func (r *notificationRepository) Lists(ctx context.Context, filter dto.NotificationFilter, page, limit int) ([]model.Notification, int, error) {
+	offset := (page - 1) * limit
+
+	var conds []string
+	var args []any
+
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		conds = append(conds, fmt.Sprintf("type = $%d", len(args)))
+	}
+	if filter.UnreadOnly {
+		conds = append(conds, "is_read = false")
+	}
+
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM notifications `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, limit, offset)
+	query := `SELECT ` + notificationColumns + ` FROM notifications ` + where + fmt.Sprintf(`
+	ORDER BY created_at DESC, id DESC
+	LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args))
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var notifications []model.Notification
+	for rows.Next() {
+		var n model.Notification
+		if err := scanNotification(rows, &n); err != nil {
+			return nil, 0, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+func (r *notificationRepository) FindByID(ctx context.Context, id int) (*model.Notification, error) {
+	var n model.Notification
+	err := scanNotification(r.DB.QueryRow(ctx, `SELECT `+notificationColumns+` FROM notifications WHERE id = $1`, id), &n)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("notification not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &n, nil
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, id, userID int) (*model.Notification, error) {
+	query := `
+	UPDATE notifications
+	SET is_read = true, read_by = $2, read_at = NOW()
+	WHERE id = $1 AND is_read = false
+	RETURNING ` + notificationColumns
+
+	var n model.Notification
+	err := scanNotification(r.DB.QueryRow(ctx, query, id, userID), &n)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// sudah dibaca sebelumnya atau memang tidak ada
+		return r.FindByID(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &n, nil
+}
+
+func (r *notificationRepository) CreateLowStock(ctx context.Context) ([]model.Notification, error) {
+	// crossing ditentukan dari stok sekarang vs minimum_stock dan belum ada notifikasi terbuka,
+	// notifikasi ditutup ResolveLowStock saat stok kembali normal sehingga bisa muncul lagi.
+	// movement_id = mutasi keluar terakhir sebagai referensi. item yang belum pernah punya
+	// mutasi (baru dibuat, stok memang 0) tidak ikut.
+	// satu notifikasi terbuka per item dijaga unique index, aman untuk beberapa instance
+	query := `
+	INSERT INTO notifications (type, item_id, movement_id, message, stock, minimum_stock)
+	SELECT $1, i.id,
+		(SELECT sm.id FROM stock_movements sm
+		 WHERE sm.item_id = i.id AND sm.quantity < 0
+		 ORDER BY sm.id DESC LIMIT 1),
+		format('Stok %s (%s) tinggal %s %s, di bawah minimum %s', i.name, i.sku, i.stock, i.unit, i.minimum_stock),
+		i.stock, i.minimum_stock
+	FROM items i
+	WHERE i.is_active = true
+	  AND i.stock < i.minimum_stock
+	  AND EXISTS (SELECT 1 FROM stock_movements sm WHERE sm.item_id = i.id)
+	  AND NOT EXISTS (
+		SELECT 1 FROM notifications n
+		WHERE n.item_id = i.id AND n.type = $1 AND n.resolved_at IS NULL
+	  )
+	ON CONFLICT DO NOTHING
+	RETURNING ` + notificationColumns
+
+	rows, err := r.DB.Query(ctx, query, model.NotificationLowStock)
+	if err != nil {
+		r.Logger.Error("failed to create low stock notifications", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []model.Notification
+	for rows.Next() {
+		var n model.Notification
+		if err := scanNotification(rows, &n); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+func (r *notificationRepository) ResolveLowStock(ctx context.Context) (int, error) {
+	query := `
+	UPDATE notifications n
+	SET resolved_at = NOW()
+	FROM items i
+	WHERE i.id = n.item_id
+	  AND n.type = $1
+	  AND n.resolved_at IS NULL
+	  AND (i.stock >= i.minimum_stock OR i.is_active = false)
+	`
+
+	res, err := r.DB.Exec(ctx, query, model.NotificationLowStock)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.RowsAffected()), nil
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type NumberSequenceRepository interface {
+	Next(ctx context.Context, prefix, period string) (int, error)
+}
+
+type numberSequenceRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewNumberSequenceRepository(db DBTX, log *zap.Logger) NumberSequenceRepository {
+	return &numberSequenceRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+// naikkan sequence dan return nilai barunya. baris sequence ter-lock sampai transaksi selesai,
+// jadi kalau transaksi rollback nomornya ikut batal (tidak ada nomor yang loncat).
+func (r *numberSequenceRepository) Next(ctx context.Context, prefix, period string) (int, error) {
+	query := `
+	INSERT INTO number_sequences (prefix, period, last_value)
+	VALUES ($1, $2, 1)
+	ON CONFLICT (prefix, period)
+	DO UPDATE SET last_value = number_sequences.last_value + 1,
+	              updated_at = CURRENT_TIMESTAMP
+	RETURNING last_value
+	`
+
+	var seq int
+	if err := r.DB.QueryRow(ctx, query, prefix, period).Scan(&seq); err != nil {
+		r.Logger.Error("failed to get next sequence", zap.String("prefix", prefix), zap.Error(err))
+		return 0, err
+	}
+
+	return seq, nil
+}
@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/model"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type PriceListRepository interface {
+	Create(ctx context.Context, pl *model.PriceList) (*model.PriceList, error)
+	Lists(ctx context.Context, page, limit int) ([]model.PriceList, int, error)
+	FindByID(ctx context.Context, id int) (*model.PriceList, error)
+	Update(ctx context.Context, pl *model.PriceList) (*model.PriceList, error)
+	Delete(ctx context.Context, id int) error
+
+	Items(ctx context.Context, priceListID int) ([]model.PriceListItem, error)
+	// insert atau ganti harga untuk kombinasi item + min_quantity yang sama
+	UpsertItem(ctx context.Context, it *model.PriceListItem) (*model.PriceListItem, error)
+	DeleteItem(ctx context.Context, priceListID, id int) error
+
+	// harga item di semua daftar harga aktif
+	ForItem(ctx context.Context, itemID int) ([]model.ItemPrice, error)
+
+	// harga per item untuk qty tertentu, pakai tingkat min_quantity tertinggi yang terpenuhi.
+	// item tanpa harga di daftar ini tidak ada di map
+	PricesFor(ctx context.Context, priceListID int, qty map[int]int) (map[int]model.Money, error)
+}
+
+type priceListRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewPriceListRepository(db DBTX, log *zap.Logger) PriceListRepository {
+	return &priceListRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+const priceListColumns = `id, code, name, description, is_active, created_by, created_at, updated_at`
+
+func scanPriceList(row pgx.Row, pl *model.PriceList) error {
+	return row.Scan(
+		&pl.ID,
+		&pl.Code,
+		&pl.Name,
+		&pl.Description,
+		&pl.IsActive,
+		&pl.CreatedBy,
+		&pl.CreatedAt,
+		&pl.UpdatedAt,
+	)
+}
+
+func (r *priceListRepository) Create(ctx context.Context, pl *model.PriceList) (*model.PriceList, error) {
+	query := `
+	INSERT INTO price_lists (code, name, description, is_active, created_by)
+	VALUES ($1, $2, $3, true, $4)
+	RETURNING ` + priceListColumns
+
+	var list model.PriceList
+	err := scanPriceList(r.DB.QueryRow(ctx, query,
+		pl.Code,
+		pl.Name,
+		pl.Description,
+		pl.CreatedBy,
+	), &list)
+
+	if err != nil {
+		r.Logger.Error("failed create price list", zap.Error(err))
+		return nil, err
+	}
+
+	r.Logger.Info("price list created successfully", zap.Int("id", list.ID))
+	return &list, nil
+}
+
+func (r *priceListRepository) Lists(ctx context.Context, page, limit int) ([]model.PriceList, int, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM price_lists`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.DB.Query(ctx, `SELECT `+priceListColumns+` FROM price_lists ORDER BY code LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var lists []model.PriceList
+	for rows.Next() {
+		var pl model.PriceList
+		if err := scanPriceList(rows, &pl); err != nil {
+			return nil, 0, err
+		}
+		lists = append(lists, pl)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return lists, total, nil
+}
+
+func (r *priceListRepository) FindByID(ctx context.Context, id int) (*model.PriceList, error) {
+	var pl model.PriceList
+	err := scanPriceList(r.DB.QueryRow(ctx, `SELECT `+priceListColumns+` FROM price_lists WHERE id = $1`, id), &pl)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("price list not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pl, nil
+}
+
+func (r *priceListRepository) Update(ctx context.Context, pl *model.PriceList) (*model.PriceList, error) {
+	query := `
+	UPDATE price_lists
+	SET code = $1, name = $2, description = $3, is_active = $4
+	WHERE id = $5
+	RETURNING ` + priceListColumns
+
+	var list model.PriceList
+	err := scanPriceList(r.DB.QueryRow(ctx, query,
+		pl.Code,
+		pl.Name,
+		pl.Description,
+		pl.IsActive,
+		pl.ID,
+	), &list)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("price list not found")
+	}
+	if err != nil {
+		r.Logger.Error("failed update price list", zap.Error(err))
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+// soft delete, customer yang memakai daftar ini kembali ke harga dasar item
+func (r *priceListRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.DB.Exec(ctx, `UPDATE price_lists SET is_active = false WHERE id = $1 AND is_active = true`, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("price list not found or already deleted")
+	}
+
+	return nil
+}
+
+func (r *priceListRepository) Items(ctx context.Context, priceListID int) ([]model.PriceListItem, error) {
+	query := `
+	SELECT pli.id, pli.price_list_id, pli.item_id, i.sku, i.name, pli.min_quantity, pli.price, pli.created_at, pli.updated_at
+	FROM price_list_items pli
+	JOIN items i ON i.id = pli.item_id
+	WHERE pli.price_list_id = $1
+	ORDER BY i.sku, pli.min_quantity
+	`
+
+	rows, err := r.DB.Query(ctx, query, priceListID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.PriceListItem
+	for rows.Next() {
+		var it model.PriceListItem
+		if err := rows.Scan(
+			&it.ID,
+			&it.PriceListID,
+			&it.ItemID,
+			&it.SKU,
+			&it.Name,
+			&it.MinQuantity,
+			&it.Price,
+			&it.CreatedAt,
+			&it.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *priceListRepository) UpsertItem(ctx context.Context, it *model.PriceListItem) (*model.PriceListItem, error) {
+	query := `
+	INSERT INTO price_list_items (price_list_id, item_id, min_quantity, price)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (price_list_id, item_id, min_quantity) DO UPDATE SET price = EXCLUDED.price
+	RETURNING id, price_list_id, item_id, min_quantity, price, created_at, updated_at
+	`
+
+	var res model.PriceListItem
+	err := r.DB.QueryRow(ctx, query,
+		it.PriceListID,
+		it.ItemID,
+		it.MinQuantity,
+		it.Price,
+	).Scan(
+		&res.ID,
+		&res.PriceListID,
+		&res.ItemID,
+		&res.MinQuantity,
+		&res.Price,
+		&res.CreatedAt,
+		&res.UpdatedAt,
+	)
+
+	if err != nil {
+		r.Logger.Error("failed upsert price list item", zap.Error(err))
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *priceListRepository) DeleteItem(ctx context.Context, priceListID, id int) error {
+	res, err := r.DB.Exec(ctx, `DELETE FROM price_list_items WHERE id = $1 AND price_list_id = $2`, id, priceListID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("price list item not found")
+	}
+
+	return nil
+}
+
+func (r *priceListRepository) ForItem(ctx context.Context, itemID int) ([]model.ItemPrice, error) {
+	query := `
+	SELECT pl.id, pl.code, pl.name, pli.min_quantity, pli.price
+	FROM price_list_items pli
+	JOIN price_lists pl ON pl.id = pli.price_list_id
+	WHERE pli.item_id = $1 AND pl.is_active = true
+	ORDER BY pl.code, pli.min_quantity
+	`
+
+	rows, err := r.DB.Query(ctx, query, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := []model.ItemPrice{}
+	for rows.Next() {
+		var p model.ItemPrice
+		if err := rows.Scan(
+			&p.PriceListID,
+			&p.PriceListCode,
+			&p.PriceListName,
+			&p.MinQuantity,
+			&p.Price,
+		); err != nil {
+			return nil, err
+		}
+		prices = append(prices, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+func (r *priceListRepository) PricesFor(ctx context.Context, priceListID int, qty map[int]int) (map[int]model.Money, error) {
+	itemIDs := make([]int, 0, len(qty))
+	quantities := make([]int, 0, len(qty))
+	for itemID, q := range qty {
+		itemIDs = append(itemIDs, itemID)
+		quantities = append(quantities, q)
+	}
+
+	// daftar harga nonaktif dianggap tidak ada, jatuh ke harga dasar item
+	query := `
+	SELECT DISTINCT ON (pli.item_id) pli.item_id, pli.price
+	FROM price_list_items pli
+	JOIN price_lists pl ON pl.id = pli.price_list_id AND pl.is_active = true
+	JOIN unnest($2::int[], $3::int[]) AS q(item_id, qty) ON q.item_id = pli.item_id
+	WHERE pli.price_list_id = $1 AND pli.min_quantity <= q.qty
+	ORDER BY pli.item_id, pli.min_quantity DESC
+	`
+
+	rows, err := r.DB.Query(ctx, query, priceListID, itemIDs, quantities)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := map[int]model.Money{}
+	for rows.Next() {
+		var itemID int
+		var price model.Money
+		if err := rows.Scan(&itemID, &price); err != nil {
+			return nil, err
+		}
+		prices[itemID] = price
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
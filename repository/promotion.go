@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/model"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type PromotionRepository interface {
+	Create(ctx context.Context, p *model.Promotion) (*model.Promotion, error)
+	Lists(ctx context.Context, activeOnly bool, page, limit int) ([]model.Promotion, int, error)
+	FindByID(ctx context.Context, id int) (*model.Promotion, error)
+	Update(ctx context.Context, p *model.Promotion) (*model.Promotion, error)
+	Delete(ctx context.Context, id int) error
+
+	// promo aktif pada waktu at per item: promo item, promo kategori item, dan promo semua item
+	ForItems(ctx context.Context, itemIDs []int, at time.Time) (map[int][]model.Promotion, error)
+
+	// tambah usage_count, false kalau kuota sudah habis
+	IncrementUsage(ctx context.Context, id int) (bool, error)
+	// kurangi usage_count, dipakai saat sale yang memakai promo di-cancel
+	DecrementUsage(ctx context.Context, id int) error
+}
+
+type promotionRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewPromotionRepository(db DBTX, log *zap.Logger) PromotionRepository {
+	return &promotionRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+const promotionColumns = `id, code, name, promo_type, value, buy_quantity, get_quantity, item_id, category_id,
+	starts_at, ends_at, usage_limit, usage_count, is_active, created_by, created_at, updated_at`
+
+func scanPromotion(row pgx.Row, p *model.Promotion) error {
+	return row.Scan(
+		&p.ID,
+		&p.Code,
+		&p.Name,
+		&p.PromoType,
+		&p.Value,
+		&p.BuyQuantity,
+		&p.GetQuantity,
+		&p.ItemID,
+		&p.CategoryID,
+		&p.StartsAt,
+		&p.EndsAt,
+		&p.UsageLimit,
+		&p.UsageCount,
+		&p.IsActive,
+		&p.CreatedBy,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+}
+
+func (r *promotionRepository) Create(ctx context.Context, p *model.Promotion) (*model.Promotion, error) {
+	query := `
+	INSERT INTO promotions (code, name, promo_type, value, buy_quantity, get_quantity, item_id, category_id, starts_at, ends_at, usage_limit, is_active, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, true, $12)
+	RETURNING ` + promotionColumns
+
+	var promo model.Promotion
+	err := scanPromotion(r.DB.QueryRow(ctx, query,
+		p.Code,
+		p.Name,
+		p.PromoType,
+		p.Value,
+		p.BuyQuantity,
+		p.GetQuantity,
+		p.ItemID,
+		p.CategoryID,
+		p.StartsAt,
+		p.EndsAt,
+		p.UsageLimit,
+		p.CreatedBy,
+	), &promo)
+
+	if err != nil {
+		r.Logger.Error("failed create promotion", zap.Error(err))
+		return nil, err
+	}
+
+	r.Logger.Info("promotion created successfully", zap.Int("id", promo.ID))
+	return &promo, nil
+}
+
+func (r *promotionRepository) Lists(ctx context.Context, activeOnly bool, page, limit int) ([]model.Promotion, int, error) {
+	offset := (page - 1) * limit
+
+	where := ``
+	if activeOnly {
+		where = ` WHERE is_active = true AND starts_at <= NOW() AND (ends_at IS NULL OR ends_at > NOW())`
+	}
+
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM promotions`+where).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.DB.Query(ctx, `SELECT `+promotionColumns+` FROM promotions`+where+` ORDER BY starts_at DESC, id DESC LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var promos []model.Promotion
+	for rows.Next() {
+		var p model.Promotion
+		if err := scanPromotion(rows, &p); err != nil {
+			return nil, 0, err
+		}
+		promos = append(promos, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return promos, total, nil
+}
+
+func (r *promotionRepository) FindByID(ctx context.Context, id int) (*model.Promotion, error) {
+	var p model.Promotion
+	err := scanPromotion(r.DB.QueryRow(ctx, `SELECT `+promotionColumns+` FROM promotions WHERE id = $1`, id), &p)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("promotion not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// usage_count tidak ikut diubah, hanya bertambah lewat sale
+func (r *promotionRepository) Update(ctx context.Context, p *model.Promotion) (*model.Promotion, error) {
+	query := `
+	UPDATE promotions
+	SET code = $1, name = $2, promo_type = $3, value = $4, buy_quantity = $5, get_quantity = $6,
+		item_id = $7, category_id = $8, starts_at = $9, ends_at = $10, usage_limit = $11, is_active = $12
+	WHERE id = $13
+	RETURNING ` + promotionColumns
+
+	var promo model.Promotion
+	err := scanPromotion(r.DB.QueryRow(ctx, query,
+		p.Code,
+		p.Name,
+		p.PromoType,
+		p.Value,
+		p.BuyQuantity,
+		p.GetQuantity,
+		p.ItemID,
+		p.CategoryID,
+		p.StartsAt,
+		p.EndsAt,
+		p.UsageLimit,
+		p.IsActive,
+		p.ID,
+	), &promo)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("promotion not found")
+	}
+	if err != nil {
+		r.Logger.Error("failed update promotion", zap.Error(err))
+		return nil, err
+	}
+
+	return &promo, nil
+}
+
+// soft delete, sale lama tetap merujuk promo yang dipakai
+func (r *promotionRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.DB.Exec(ctx, `UPDATE promotions SET is_active = false WHERE id = $1 AND is_active = true`, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("promotion not found or already deleted")
+	}
+
+	return nil
+}
+
+func (r *promotionRepository) ForItems(ctx context.Context, itemIDs []int, at time.Time) (map[int][]model.Promotion, error) {
+	query := `
+	SELECT i.id, p.id, p.code, p.name, p.promo_type, p.value, p.buy_quantity, p.get_quantity, p.item_id, p.category_id,
+		p.starts_at, p.ends_at, p.usage_limit, p.usage_count, p.is_active, p.created_by, p.created_at, p.updated_at
+	FROM items i
+	JOIN promotions p ON p.item_id = i.id
+		OR p.category_id = i.category_id
+		OR (p.item_id IS NULL AND p.category_id IS NULL)
+	WHERE i.id = ANY($1)
+		AND p.is_active = true
+		AND p.starts_at <= $2
+		AND (p.ends_at IS NULL OR p.ends_at > $2)
+		AND (p.usage_limit IS NULL OR p.usage_count < p.usage_limit)
+	ORDER BY i.id, p.id
+	`
+
+	rows, err := r.DB.Query(ctx, query, itemIDs, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promos := map[int][]model.Promotion{}
+	for rows.Next() {
+		var itemID int
+		var p model.Promotion
+		if err := rows.Scan(
+			&itemID,
+			&p.ID,
+			&p.Code,
+			&p.Name,
+			&p.PromoType,
+			&p.Value,
+			&p.BuyQuantity,
+			&p.GetQuantity,
+			&p.ItemID,
+			&p.CategoryID,
+			&p.StartsAt,
+			&p.EndsAt,
+			&p.UsageLimit,
+			&p.UsageCount,
+			&p.IsActive,
+			&p.CreatedBy,
+			&p.CreatedAt,
+			&p.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		promos[itemID] = append(promos[itemID], p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return promos, nil
+}
+
+func (r *promotionRepository) IncrementUsage(ctx context.Context, id int) (bool, error) {
+	// kondisi kuota dicek ulang di UPDATE, sale paralel antri di row lock promo
+	res, err := r.DB.Exec(ctx, `
+	UPDATE promotions SET usage_count = usage_count + 1
+	WHERE id = $1 AND (usage_limit IS NULL OR usage_count < usage_limit)
+	`, id)
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+func (r *promotionRepository) DecrementUsage(ctx context.Context, id int) error {
+	_, err := r.DB.Exec(ctx, `
+	UPDATE promotions SET usage_count = usage_count - 1
+	WHERE id = $1 AND usage_count > 0
+	`, id)
+	return err
+}
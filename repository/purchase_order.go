@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type PurchaseOrderRepository interface {
+	Create(ctx context.Context, po *model.PurchaseOrder) (*model.PurchaseOrder, error)
+	CreateItem(ctx context.Context, it *model.PurchaseOrderItem) error
+	// ganti header & semua baris, hanya untuk draft
+	Update(ctx context.Context, po *model.PurchaseOrder) error
+	DeleteItems(ctx context.Context, purchaseOrderID int) error
+
+	Lists(ctx context.Context, filter dto.PurchaseOrderFilter, page, limit int) ([]model.PurchaseOrder, int, error)
+	FindByID(ctx context.Context, id int) (*model.PurchaseOrder, error)
+	FindByIDForUpdate(ctx context.Context, id int) (*model.PurchaseOrder, error)
+	FindItems(ctx context.Context, purchaseOrderID int) ([]model.PurchaseOrderItem, error)
+
+	// perubahan status
+	Approve(ctx context.Context, id, userID int) error
+	UpdateStatus(ctx context.Context, id int, status string) error
+	AddReceived(ctx context.Context, lineID, qty int) error
+}
+
+type purchaseOrderRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewPurchaseOrderRepository(db DBTX, log *zap.Logger) PurchaseOrderRepository {
+	return &purchaseOrderRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+const purchaseOrderSelect = `
+	SELECT
+		po.id, po.po_number, po.supplier_id, po.warehouse_id, po.status,
+		s.name, w.code,
+		po.order_date, po.expected_date, po.total_amount, po.notes,
+		po.created_by, po.approved_by, po.approved_at, po.closed_at,
+		po.created_at, po.updated_at
+	FROM purchase_orders po
+	JOIN suppliers s ON s.id = po.supplier_id
+	JOIN warehouses w ON w.id = po.warehouse_id
+`
+
+func scanPurchaseOrder(row pgx.Row, po *model.PurchaseOrder) error {
+	return row.Scan(
+		&po.ID,
+		&po.PONumber,
+		&po.SupplierID,
+		&po.WarehouseID,
+		&po.Status,
+		&po.SupplierName,
+		&po.WarehouseCode,
+		&po.OrderDate,
+		&po.ExpectedDate,
+		&po.TotalAmount,
+		&po.Notes,
+		&po.CreatedBy,
+		&po.ApprovedBy,
+		&po.ApprovedAt,
+		&po.ClosedAt,
+		&po.CreatedAt,
+		&po.UpdatedAt,
+	)
+}
+
+func (r *purchaseOrderRepository) Create(ctx context.Context, po *model.PurchaseOrder) (*model.PurchaseOrder, error) {
+	query := `
+	INSERT INTO purchase_orders (po_number, supplier_id, warehouse_id, status, order_date, expected_date, total_amount, notes, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING id;
+	`
+
+	var id int
+	err := r.DB.QueryRow(ctx, query,
+		po.PONumber,
+		po.SupplierID,
+		po.WarehouseID,
+		model.PurchaseDraft,
+		po.OrderDate,
+		po.ExpectedDate,
+		po.TotalAmount,
+		po.Notes,
+		po.CreatedBy,
+	).Scan(&id)
+
+	if err != nil {
+		r.Logger.Error("failed to create purchase order", zap.Error(err))
+		return nil, err
+	}
+
+	r.Logger.Info("purchase order created", zap.Int("id", id))
+	return r.FindByID(ctx, id)
+}
+
+func (r *purchaseOrderRepository) CreateItem(ctx context.Context, it *model.PurchaseOrderItem) error {
+	query := `
+	INSERT INTO purchase_order_items (purchase_order_id, item_id, quantity, unit_cost, subtotal)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id;
+	`
+
+	err := r.DB.QueryRow(ctx, query,
+		it.PurchaseOrderID,
+		it.ItemID,
+		it.Quantity,
+		it.UnitCost,
+		it.Subtotal,
+	).Scan(&it.ID)
+
+	if err != nil {
+		r.Logger.Error("failed to create purchase order item", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *purchaseOrderRepository) Update(ctx context.Context, po *model.PurchaseOrder) error {
+	query := `
+	UPDATE purchase_orders
+	SET supplier_id = $1,
+	    expected_date = $2,
+	    total_amount = $3,
+	    notes = $4
+	WHERE id = $5 AND status = 'draft'
+	`
+
+	res, err := r.DB.Exec(ctx, query, po.SupplierID, po.ExpectedDate, po.TotalAmount, po.Notes, po.ID)
+	if err != nil {
+		r.Logger.Error("failed to update purchase order", zap.Error(err))
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("purchase order not found or not a draft")
+	}
+
+	return nil
+}
+
+func (r *purchaseOrderRepository) DeleteItems(ctx context.Context, purchaseOrderID int) error {
+	_, err := r.DB.Exec(ctx, `DELETE FROM purchase_order_items WHERE purchase_order_id = $1`, purchaseOrderID)
+	return err
+}
+
+func (r *purchaseOrderRepository) Lists(ctx context.Context, filter dto.PurchaseOrderFilter, page, limit int) ([]model.PurchaseOrder, int, error) {
+	offset := (page - 1) * limit
+
+	var conds []string
+	var args []any
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conds = append(conds, fmt.Sprintf("po.status = $%d", len(args)))
+	}
+	if filter.SupplierID != 0 {
+		args = append(args, filter.SupplierID)
+		conds = append(conds, fmt.Sprintf("po.supplier_id = $%d", len(args)))
+	}
+	if filter.WarehouseID != 0 {
+		args = append(args, filter.WarehouseID)
+		conds = append(conds, fmt.Sprintf("po.warehouse_id = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM purchase_orders po `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, limit, offset)
+	query := purchaseOrderSelect + where + fmt.Sprintf(`
+	ORDER BY po.order_date DESC, po.id DESC
+	LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args))
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var orders []model.PurchaseOrder
+	for rows.Next() {
+		var po model.PurchaseOrder
+		if err := scanPurchaseOrder(rows, &po); err != nil {
+			return nil, 0, err
+		}
+		orders = append(orders, po)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return orders, total, nil
+}
+
+func (r *purchaseOrderRepository) FindByID(ctx context.Context, id int) (*model.PurchaseOrder, error) {
+	return r.findOne(ctx, purchaseOrderSelect+`WHERE po.id = $1`, id)
+}
+
+// lock dokumen PO selama perubahan status / penerimaan
+func (r *purchaseOrderRepository) FindByIDForUpdate(ctx context.Context, id int) (*model.PurchaseOrder, error) {
+	return r.findOne(ctx, purchaseOrderSelect+`WHERE po.id = $1 FOR UPDATE OF po`, id)
+}
+
+func (r *purchaseOrderRepository) findOne(ctx context.Context, query string, id int) (*model.PurchaseOrder, error) {
+	var po model.PurchaseOrder
+	err := scanPurchaseOrder(r.DB.QueryRow(ctx, query, id), &po)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("purchase order not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &po, nil
+}
+
+func (r *purchaseOrderRepository) FindItems(ctx context.Context, purchaseOrderID int) ([]model.PurchaseOrderItem, error) {
+	query := `
+	SELECT poi.id, poi.purchase_order_id, poi.item_id, i.sku, i.name,
+		poi.quantity, poi.received_quantity, poi.unit_cost, poi.subtotal
+	FROM purchase_order_items poi
+	JOIN items i ON i.id = poi.item_id
+	WHERE poi.purchase_order_id = $1
+	ORDER BY poi.item_id
+	`
+
+	rows, err := r.DB.Query(ctx, query, purchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.PurchaseOrderItem
+	for rows.Next() {
+		var it model.PurchaseOrderItem
+		if err := rows.Scan(
+			&it.ID,
+			&it.PurchaseOrderID,
+			&it.ItemID,
+			&it.SKU,
+			&it.Name,
+			&it.Quantity,
+			&it.ReceivedQuantity,
+			&it.UnitCost,
+			&it.Subtotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *purchaseOrderRepository) Approve(ctx context.Context, id, userID int) error {
+	query := `
+	UPDATE purchase_orders
+	SET status = $1,
+	    approved_by = $2,
+	    approved_at = NOW()
+	WHERE id = $3
+	`
+
+	res, err := r.DB.Exec(ctx, query, model.PurchaseApproved, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("purchase order not found")
+	}
+
+	return nil
+}
+
+func (r *purchaseOrderRepository) UpdateStatus(ctx context.Context, id int, status string) error {
+	query := `
+	UPDATE purchase_orders
+	SET status = $1,
+	    closed_at = CASE WHEN $1 = 'closed' THEN NOW() ELSE closed_at END
+	WHERE id = $2
+	`
+
+	res, err := r.DB.Exec(ctx, query, status, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("purchase order not found")
+	}
+
+	return nil
+}
+
+func (r *purchaseOrderRepository) AddReceived(ctx context.Context, lineID, qty int) error {
+	query := `
+	UPDATE purchase_order_items
+	SET received_quantity = received_quantity + $1
+	WHERE id = $2 AND received_quantity + $1 <= quantity
+	`
+
+	res, err := r.DB.Exec(ctx, query, qty, lineID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("received quantity exceeds ordered quantity")
+	}
+
+	return nil
+}
@@ -0,0 +1,578 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ReportRepository interface {
+	// stok, penjualan bersih [from, to), qty on order & supplier utama per item & gudang.
+	// kolom hasil hitungan (reorder point, saran qty) diisi service
+	ReorderCandidates(ctx context.Context, filter dto.ReorderFilter, from, to time.Time) ([]model.ReorderSuggestion, error)
+
+	// laporan penjualan, semua dihitung dengan agregasi SQL
+	SalesSummary(ctx context.Context, filter dto.SalesReportFilter) (*model.SalesSummary, error)
+	SalesByPeriod(ctx context.Context, filter dto.SalesReportFilter) ([]model.SalesPeriod, error)
+	SalesByPaymentMethod(ctx context.Context, filter dto.SalesReportFilter) ([]model.SalesByPaymentMethod, error)
+	SalesByCashier(ctx context.Context, filter dto.SalesReportFilter) ([]model.SalesByCashier, error)
+	SalesByCategory(ctx context.Context, filter dto.SalesReportFilter) ([]model.SalesByCategory, error)
+	SalesByItem(ctx context.Context, filter dto.SalesReportFilter) ([]model.SalesByItem, error)
+
+	// revenue, cost & margin per item / kategori / periode, bersih setelah retur
+	GrossMargin(ctx context.Context, filter dto.MarginReportFilter) ([]model.GrossMargin, error)
+
+	// umur stok per item & rak yang masih ada stoknya
+	InventoryAging(ctx context.Context, filter dto.InventoryAgingFilter) ([]model.InventoryAging, error)
+}
+
+type reportRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewReportRepository(db DBTX, log *zap.Logger) ReportRepository {
+	return &reportRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+func (r *reportRepository) ReorderCandidates(ctx context.Context, filter dto.ReorderFilter, from, to time.Time) ([]model.ReorderSuggestion, error) {
+	args := []any{from, to}
+	conds := []string{"i.is_active = true", "w.is_active = true"}
+
+	if filter.WarehouseID != 0 {
+		args = append(args, filter.WarehouseID)
+		conds = append(conds, fmt.Sprintf("w.id = $%d", len(args)))
+	}
+	if filter.CategoryID != 0 {
+		args = append(args, filter.CategoryID)
+		conds = append(conds, fmt.Sprintf("i.category_id = $%d", len(args)))
+	}
+	if filter.SupplierID != 0 {
+		args = append(args, filter.SupplierID)
+		conds = append(conds, fmt.Sprintf("sup.id = $%d", len(args)))
+	}
+
+	// item & gudang yang punya lokasi stok atau penjualan selama periode
+	query := `
+	WITH sold AS (
+		SELECT si.item_id, s.warehouse_id, SUM(si.quantity) AS quantity
+		FROM sale_items si
+		JOIN sales s ON s.id = si.sale_id
+		WHERE s.payment_status <> 'cancelled'
+			AND s.warehouse_id IS NOT NULL
+			AND s.sale_date >= $1 AND s.sale_date < $2
+		GROUP BY si.item_id, s.warehouse_id
+	), returned AS (
+		SELECT sri.item_id, s.warehouse_id, SUM(sri.quantity) AS quantity
+		FROM sale_return_items sri
+		JOIN sale_items si ON si.id = sri.sale_item_id
+		JOIN sales s ON s.id = si.sale_id
+		WHERE s.payment_status <> 'cancelled'
+			AND s.warehouse_id IS NOT NULL
+			AND s.sale_date >= $1 AND s.sale_date < $2
+		GROUP BY sri.item_id, s.warehouse_id
+	), stock AS (
+		SELECT il.item_id, rk.warehouse_id, SUM(il.quantity) AS quantity
+		FROM item_locations il
+		JOIN racks rk ON rk.id = il.rack_id
+		GROUP BY il.item_id, rk.warehouse_id
+	), on_order AS (
+		SELECT poi.item_id, po.warehouse_id, SUM(poi.quantity - poi.received_quantity) AS quantity
+		FROM purchase_order_items poi
+		JOIN purchase_orders po ON po.id = poi.purchase_order_id
+		WHERE po.status IN ('draft', 'approved', 'partially_received')
+		GROUP BY poi.item_id, po.warehouse_id
+	), pairs AS (
+		SELECT item_id, warehouse_id FROM sold
+		UNION
+		SELECT item_id, warehouse_id FROM stock
+	)
+	SELECT w.id, w.code, w.name, i.id, i.sku, i.name, i.unit,
+		sup.id, sup.name,
+		COALESCE(st.quantity, 0)::int,
+		COALESCE(oo.quantity, 0)::int,
+		(COALESCE(sd.quantity, 0) - COALESCE(rt.quantity, 0))::int,
+		COALESCE(isup.lead_time_days, 0),
+		i.minimum_stock,
+		COALESCE(isup.min_order_quantity, 1),
+		COALESCE(isup.last_purchase_price, i.cost, 0)
+	FROM pairs p
+	JOIN items i ON i.id = p.item_id
+	JOIN warehouses w ON w.id = p.warehouse_id
+	LEFT JOIN sold sd ON sd.item_id = p.item_id AND sd.warehouse_id = p.warehouse_id
+	LEFT JOIN returned rt ON rt.item_id = p.item_id AND rt.warehouse_id = p.warehouse_id
+	LEFT JOIN stock st ON st.item_id = p.item_id AND st.warehouse_id = p.warehouse_id
+	LEFT JOIN on_order oo ON oo.item_id = p.item_id AND oo.warehouse_id = p.warehouse_id
+	LEFT JOIN (item_suppliers isup
+		JOIN suppliers sup ON sup.id = isup.supplier_id AND sup.is_active = true
+	) ON isup.item_id = i.id AND isup.is_preferred
+	WHERE ` + strings.Join(conds, " AND ") + `
+	ORDER BY w.code, i.sku
+	`
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []model.ReorderSuggestion
+	for rows.Next() {
+		var c model.ReorderSuggestion
+		if err := rows.Scan(
+			&c.WarehouseID,
+			&c.WarehouseCode,
+			&c.WarehouseName,
+			&c.ItemID,
+			&c.SKU,
+			&c.Name,
+			&c.Unit,
+			&c.SupplierID,
+			&c.SupplierName,
+			&c.Stock,
+			&c.OnOrder,
+			&c.SoldQuantity,
+			&c.LeadTimeDays,
+			&c.SafetyStock,
+			&c.MinOrderQuantity,
+			&c.UnitCost,
+		); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
+// salesConds filter sale (alias s) sesuai periode, status bayar & gudang
+func salesConds(filter dto.SalesReportFilter) (string, []any) {
+	args := []any{filter.From, filter.To.AddDate(0, 0, 1)}
+	conds := []string{"s.sale_date >= $1", "s.sale_date < $2"}
+
+	if filter.PaymentStatus != "" {
+		args = append(args, filter.PaymentStatus)
+		conds = append(conds, fmt.Sprintf("s.payment_status = $%d", len(args)))
+	} else {
+		conds = append(conds, "s.payment_status <> 'cancelled'")
+	}
+	if filter.WarehouseID != 0 {
+		args = append(args, filter.WarehouseID)
+		conds = append(conds, fmt.Sprintf("s.warehouse_id = $%d", len(args)))
+	}
+
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+func (r *reportRepository) SalesSummary(ctx context.Context, filter dto.SalesReportFilter) (*model.SalesSummary, error) {
+	where, args := salesConds(filter)
+
+	query := `
+	SELECT COUNT(*),
+		COALESCE(SUM(s.total_amount), 0),
+		COALESCE(SUM(s.discount), 0),
+		COALESCE(SUM(s.tax), 0),
+		COALESCE(SUM(s.grand_total), 0),
+		COALESCE((
+			SELECT SUM(si.quantity) FROM sale_items si
+			JOIN sales s ON s.id = si.sale_id
+			` + where + `
+		), 0),
+		COALESCE((
+			SELECT SUM(sr.refund_amount) FROM sale_returns sr
+			JOIN sales s ON s.id = sr.sale_id
+			` + where + `
+		), 0)
+	FROM sales s
+	` + where
+
+	var summary model.SalesSummary
+	err := r.DB.QueryRow(ctx, query, args...).Scan(
+		&summary.Transactions,
+		&summary.TotalAmount,
+		&summary.Discount,
+		&summary.Tax,
+		&summary.GrandTotal,
+		&summary.ItemsSold,
+		&summary.Refunds,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+func (r *reportRepository) SalesByPeriod(ctx context.Context, filter dto.SalesReportFilter) ([]model.SalesPeriod, error) {
+	where, args := salesConds(filter)
+	args = append(args, filter.Period)
+
+	query := fmt.Sprintf(`
+	SELECT to_char(date_trunc($%d, s.sale_date), 'YYYY-MM-DD') AS period,
+		COUNT(*),
+		SUM(s.total_amount),
+		COALESCE(SUM(s.discount), 0),
+		COALESCE(SUM(s.tax), 0),
+		SUM(s.grand_total)
+	FROM sales s
+	`, len(args)) + where + `
+	GROUP BY 1
+	ORDER BY 1
+	`
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	periods := []model.SalesPeriod{}
+	for rows.Next() {
+		var p model.SalesPeriod
+		if err := rows.Scan(&p.Period, &p.Transactions, &p.TotalAmount, &p.Discount, &p.Tax, &p.GrandTotal); err != nil {
+			return nil, err
+		}
+		periods = append(periods, p)
+	}
+
+	return periods, rows.Err()
+}
+
+func (r *reportRepository) SalesByPaymentMethod(ctx context.Context, filter dto.SalesReportFilter) ([]model.SalesByPaymentMethod, error) {
+	where, args := salesConds(filter)
+
+	// satu sale bisa dibayar dengan beberapa metode, jadi dihitung dari pembayarannya
+	query := `
+	SELECT sp.payment_method, COUNT(*), SUM(sp.amount)
+	FROM sale_payments sp
+	JOIN sales s ON s.id = sp.sale_id
+	` + where + ` AND sp.voided_at IS NULL
+	GROUP BY sp.payment_method
+	ORDER BY SUM(sp.amount) DESC
+	`
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	methods := []model.SalesByPaymentMethod{}
+	for rows.Next() {
+		var m model.SalesByPaymentMethod
+		if err := rows.Scan(&m.PaymentMethod, &m.Payments, &m.Amount); err != nil {
+			return nil, err
+		}
+		methods = append(methods, m)
+	}
+
+	return methods, rows.Err()
+}
+
+func (r *reportRepository) SalesByCashier(ctx context.Context, filter dto.SalesReportFilter) ([]model.SalesByCashier, error) {
+	where, args := salesConds(filter)
+
+	query := `
+	SELECT u.id, u.username, u.full_name, COUNT(*), SUM(s.grand_total)
+	FROM sales s
+	JOIN users u ON u.id = s.created_by
+	` + where + `
+	GROUP BY u.id, u.username, u.full_name
+	ORDER BY SUM(s.grand_total) DESC
+	`
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cashiers := []model.SalesByCashier{}
+	for rows.Next() {
+		var c model.SalesByCashier
+		if err := rows.Scan(&c.UserID, &c.Username, &c.FullName, &c.Transactions, &c.GrandTotal); err != nil {
+			return nil, err
+		}
+		cashiers = append(cashiers, c)
+	}
+
+	return cashiers, rows.Err()
+}
+
+func (r *reportRepository) SalesByCategory(ctx context.Context, filter dto.SalesReportFilter) ([]model.SalesByCategory, error) {
+	where, args := salesConds(filter)
+
+	query := `
+	SELECT c.id, c.name, SUM(si.quantity), SUM(si.subtotal)
+	FROM sale_items si
+	JOIN sales s ON s.id = si.sale_id
+	JOIN items i ON i.id = si.item_id
+	JOIN categories c ON c.id = i.category_id
+	` + where + `
+	GROUP BY c.id, c.name
+	ORDER BY SUM(si.subtotal) DESC
+	`
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []model.SalesByCategory{}
+	for rows.Next() {
+		var c model.SalesByCategory
+		if err := rows.Scan(&c.CategoryID, &c.CategoryName, &c.Quantity, &c.Revenue); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+
+	return categories, rows.Err()
+}
+
+func (r *reportRepository) SalesByItem(ctx context.Context, filter dto.SalesReportFilter) ([]model.SalesByItem, error) {
+	where, args := salesConds(filter)
+
+	query := `
+	SELECT i.id, i.sku, i.name, SUM(si.quantity), SUM(si.subtotal)
+	FROM sale_items si
+	JOIN sales s ON s.id = si.sale_id
+	JOIN items i ON i.id = si.item_id
+	` + where + `
+	GROUP BY i.id, i.sku, i.name
+	ORDER BY SUM(si.subtotal) DESC, i.sku
+	`
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []model.SalesByItem{}
+	for rows.Next() {
+		var it model.SalesByItem
+		if err := rows.Scan(&it.ItemID, &it.SKU, &it.Name, &it.Quantity, &it.Revenue); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+
+	return items, rows.Err()
+}
+
+func (r *reportRepository) GrossMargin(ctx context.Context, filter dto.MarginReportFilter) ([]model.GrossMargin, error) {
+	where, args := salesConds(filter.SalesReportFilter)
+
+	if filter.CategoryID != 0 {
+		args = append(args, filter.CategoryID)
+		where += fmt.Sprintf(" AND i.category_id = $%d", len(args))
+	}
+
+	var columns, group string
+	switch filter.GroupBy {
+	case dto.MarginByCategory:
+		columns = "c.id, c.code, c.name"
+		group = "c.id, c.code, c.name"
+	case dto.MarginByPeriod:
+		args = append(args, filter.Period)
+		columns = fmt.Sprintf("NULL::int, to_char(date_trunc($%d, l.sale_date), 'YYYY-MM-DD'), NULL", len(args))
+		group = "2"
+	default:
+		columns = "i.id, i.sku, i.name"
+		group = "i.id, i.sku, i.name"
+	}
+
+	// per baris: revenue tanpa pajak inclusive & setelah bagian diskon nota,
+	// cost dari cogs tercatat (fallback cost item), keduanya dikurangi proporsi qty retur
+	query := `
+	WITH returned AS (
+		SELECT sale_item_id, SUM(quantity) AS quantity
+		FROM sale_return_items
+		GROUP BY sale_item_id
+	), lines AS (
+		SELECT si.item_id, s.sale_date,
+			si.quantity - COALESCE(rt.quantity, 0) AS quantity,
+			(si.subtotal
+				- COALESCE(s.discount * si.subtotal / NULLIF(s.total_amount, 0), 0)
+				- CASE WHEN si.tax_inclusive THEN si.tax_amount ELSE 0 END
+			) * (si.quantity - COALESCE(rt.quantity, 0)) / si.quantity AS revenue,
+			(CASE WHEN si.cogs > 0 THEN si.cogs ELSE si.quantity * COALESCE(i.cost, 0) END)
+				* (si.quantity - COALESCE(rt.quantity, 0)) / si.quantity AS cost
+		FROM sale_items si
+		JOIN sales s ON s.id = si.sale_id
+		JOIN items i ON i.id = si.item_id
+		LEFT JOIN returned rt ON rt.sale_item_id = si.id
+		` + where + `
+	)
+	SELECT ` + columns + `,
+		SUM(l.quantity)::int,
+		ROUND(SUM(l.revenue), 2),
+		ROUND(SUM(l.cost), 2)
+	FROM lines l
+	JOIN items i ON i.id = l.item_id
+	JOIN categories c ON c.id = i.category_id
+	GROUP BY ` + group + `
+	ORDER BY 2
+	`
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	margins := []model.GrossMargin{}
+	for rows.Next() {
+		var g model.GrossMargin
+		var name *string
+		if err := rows.Scan(&g.ID, &g.Key, &name, &g.Quantity, &g.Revenue, &g.Cost); err != nil {
+			return nil, err
+		}
+		if name != nil {
+			g.Name = *name
+		}
+		g.SetMargin()
+		margins = append(margins, g)
+	}
+
+	return margins, rows.Err()
+}
+
+func (r *reportRepository) InventoryAging(ctx context.Context, filter dto.InventoryAgingFilter) ([]model.InventoryAging, error) {
+	var args []any
+	conds := []string{"il.quantity > 0"}
+
+	if filter.WarehouseID != 0 {
+		args = append(args, filter.WarehouseID)
+		conds = append(conds, fmt.Sprintf("rk.warehouse_id = $%d", len(args)))
+	}
+	if filter.RackID != 0 {
+		args = append(args, filter.RackID)
+		conds = append(conds, fmt.Sprintf("il.rack_id = $%d", len(args)))
+	}
+	if filter.CategoryID != 0 {
+		args = append(args, filter.CategoryID)
+		conds = append(conds, fmt.Sprintf("i.category_id = $%d", len(args)))
+	}
+
+	// stok di rak dipasangkan ke mutasi masuk paling baru (termasuk transfer masuk),
+	// sisa yang tidak tertutup mutasi (data lama) dianggap masuk saat item dibuat.
+	// nilai = SUM(total_cost) mutasi di rak, sama dengan dasar valuasi persediaan
+	query := `
+	WITH stock AS (
+		SELECT il.item_id, il.rack_id, rk.warehouse_id, il.quantity
+		FROM item_locations il
+		JOIN racks rk ON rk.id = il.rack_id
+		JOIN items i ON i.id = il.item_id
+		WHERE ` + strings.Join(conds, " AND ") + `
+	), incoming AS (
+		SELECT sm.item_id, sm.rack_id, sm.quantity, sm.created_at,
+			SUM(sm.quantity) OVER (
+				PARTITION BY sm.item_id, sm.rack_id
+				ORDER BY sm.created_at DESC, sm.id DESC
+			) AS running
+		FROM stock_movements sm
+		JOIN stock st ON st.item_id = sm.item_id AND st.rack_id = sm.rack_id
+		WHERE sm.quantity > 0
+	), layers AS (
+		SELECT inc.item_id, inc.rack_id, inc.created_at AS received_at,
+			LEAST(inc.quantity, st.quantity - (inc.running - inc.quantity)) AS quantity
+		FROM incoming inc
+		JOIN stock st ON st.item_id = inc.item_id AND st.rack_id = inc.rack_id
+		WHERE inc.running - inc.quantity < st.quantity
+		UNION ALL
+		SELECT st.item_id, st.rack_id, COALESCE(i.created_at, LOCALTIMESTAMP), st.quantity - COALESCE(cov.quantity, 0)
+		FROM stock st
+		JOIN items i ON i.id = st.item_id
+		LEFT JOIN (
+			SELECT item_id, rack_id, SUM(quantity) AS quantity FROM incoming GROUP BY item_id, rack_id
+		) cov ON cov.item_id = st.item_id AND cov.rack_id = st.rack_id
+		WHERE st.quantity > COALESCE(cov.quantity, 0)
+	), aged AS (
+		SELECT item_id, rack_id, quantity, received_at,
+			CURRENT_DATE - received_at::date AS age
+		FROM layers
+	), last_move AS (
+		SELECT sm.item_id, sm.rack_id, MAX(sm.created_at) AS at,
+			SUM(sm.total_cost) AS value
+		FROM stock_movements sm
+		JOIN stock st ON st.item_id = sm.item_id AND st.rack_id = sm.rack_id
+		GROUP BY sm.item_id, sm.rack_id
+	), last_sale AS (
+		SELECT si.item_id, s.warehouse_id, MAX(s.sale_date) AS at
+		FROM sale_items si
+		JOIN sales s ON s.id = si.sale_id
+		WHERE s.payment_status <> 'cancelled'
+		GROUP BY si.item_id, s.warehouse_id
+	)
+	SELECT w.id, w.code, rk.id, rk.code, i.id, i.sku, i.name,
+		st.quantity,
+		COALESCE(lm.value, 0),
+		COALESCE(SUM(a.quantity) FILTER (WHERE a.age <= 30), 0)::int,
+		COALESCE(SUM(a.quantity) FILTER (WHERE a.age BETWEEN 31 AND 90), 0)::int,
+		COALESCE(SUM(a.quantity) FILTER (WHERE a.age BETWEEN 91 AND 180), 0)::int,
+		COALESCE(SUM(a.quantity) FILTER (WHERE a.age > 180), 0)::int,
+		MIN(a.received_at),
+		lm.at,
+		ls.at,
+		CURRENT_DATE - COALESCE(GREATEST(lm.at, ls.at), MIN(a.received_at))::date
+	FROM stock st
+	JOIN items i ON i.id = st.item_id
+	JOIN racks rk ON rk.id = st.rack_id
+	JOIN warehouses w ON w.id = st.warehouse_id
+	JOIN aged a ON a.item_id = st.item_id AND a.rack_id = st.rack_id
+	LEFT JOIN last_move lm ON lm.item_id = st.item_id AND lm.rack_id = st.rack_id
+	LEFT JOIN last_sale ls ON ls.item_id = st.item_id AND ls.warehouse_id = st.warehouse_id
+	GROUP BY w.id, w.code, rk.id, rk.code, i.id, i.sku, i.name, st.quantity, lm.at, lm.value, ls.at
+	ORDER BY w.code, rk.code, i.sku
+	`
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aging := []model.InventoryAging{}
+	for rows.Next() {
+		var a model.InventoryAging
+		if err := rows.Scan(
+			&a.WarehouseID,
+			&a.WarehouseCode,
+			&a.RackID,
+			&a.RackCode,
+			&a.ItemID,
+			&a.SKU,
+			&a.Name,
+			&a.Quantity,
+			&a.Value,
+			&a.Days0To30,
+			&a.Days31To90,
+			&a.Days91To180,
+			&a.DaysOver180,
+			&a.OldestReceivedAt,
+			&a.LastMovementAt,
+			&a.LastSaleAt,
+			&a.IdleDays,
+		); err != nil {
+			return nil, err
+		}
+		aging = append(aging, a)
+	}
+
+	return aging, rows.Err()
+}
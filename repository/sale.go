@@ -4,6 +4,7 @@ import (
 	"alfdwirhmn/inventory/model"
 	"context"
 	"errors"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -17,9 +18,18 @@ type SaleRepository interface {
 
 	// transaction status update
 	UpdatePaymentStatus(ctx context.Context, id int, status string) error
+	Cancel(ctx context.Context, id, userID int, reason string) error
 
 	CreateItem(ctx context.Context, item *model.SaleItem) error
+	FindItems(ctx context.Context, saleID int) ([]model.SaleItem, error)
 	FindByID(ctx context.Context, id int) (*model.Sale, error)
+
+	// lock header sale selama transaksi (retur, cancel, dll)
+	FindDetailByIDForUpdate(ctx context.Context, id int) (*model.Sale, error)
+
+	// riwayat belanja customer
+	ListsByCustomer(ctx context.Context, customerID, page, limit int) ([]model.Sale, int, error)
+	SummaryByCustomer(ctx context.Context, customerID int) (*model.CustomerSalesSummary, error)
 }
 
 type saleRepository struct {
@@ -36,9 +46,9 @@ func NewSaleRepository(db DBTX, log *zap.Logger) SaleRepository {
 
 func (s *saleRepository) Create(ctx context.Context, sl *model.Sale) (*model.Sale, error) {
 	query := `
-	INSERT INTO sales (invoice_number, customer_name, customer_phone, customer_email, total_amount, discount, tax, grand_total, payment_method, notes, created_by) 
-	VALUES ($1, $2, $3, $4, $5, COALESCE($6, 0), COALESCE($7, 0), $8, $9, $10, $11)
-	RETURNING id, invoice_number, customer_name, customer_phone, customer_email, sale_date, total_amount, discount, tax, grand_total, payment_method, payment_status, notes, created_by, created_at, updated_at;
+	INSERT INTO sales (invoice_number, warehouse_id, customer_id, customer_name, customer_phone, customer_email, sale_date, total_amount, discount, tax, grand_total, payment_method, notes, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, CURRENT_TIMESTAMP), $8, COALESCE($9::numeric, 0), COALESCE($10::numeric, 0), $11, $12, $13, $14)
+	RETURNING id, invoice_number, warehouse_id, customer_id, customer_name, customer_phone, customer_email, sale_date, total_amount, discount, tax, grand_total, payment_method, payment_status, notes, created_by, created_at, updated_at;
 	`
 
 	var customerName, customerPhone, customerEmail, paymentMethod, notes *string
@@ -46,11 +56,20 @@ func (s *saleRepository) Create(ctx context.Context, sl *model.Sale) (*model.Sal
 
 	var sale model.Sale
 
+	// sale_date kosong = sekarang, diisi untuk import sale lama
+	var saleDate *time.Time
+	if !sl.SaleDate.IsZero() {
+		saleDate = &sl.SaleDate
+	}
+
 	err := s.DB.QueryRow(ctx, query,
 		sl.InvoiceNumber,
+		sl.WarehouseID,
+		sl.CustomerID,
 		sl.CustomerName,
 		sl.CustomerPhone,
 		sl.CustomerEmail,
+		saleDate,
 		sl.TotalAmount,
 		sl.Discount,
 		sl.Tax,
@@ -61,6 +80,8 @@ func (s *saleRepository) Create(ctx context.Context, sl *model.Sale) (*model.Sal
 	).Scan(
 		&sale.ID,
 		&sale.InvoiceNumber,
+		&sale.WarehouseID,
+		&sale.CustomerID,
 		&customerName,
 		&customerPhone,
 		&customerEmail,
@@ -158,8 +179,8 @@ func (s *saleRepository) Lists(ctx context.Context, page, limit int) ([]model.Sa
 
 func (s *saleRepository) CreateItem(ctx context.Context, item *model.SaleItem) error {
 	query := `
-	INSERT INTO sale_items (sale_id, item_id, quantity, unit_price, subtotal, discount)
-	VALUES ($1, $2, $3, $4, $5, $6)
+	INSERT INTO sale_items (sale_id, item_id, quantity, unit_price, subtotal, discount, tax_rate_id, tax_rate, tax_inclusive, tax_amount, price_list_id, promotion_id, promotion_discount, cogs)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	RETURNING id;
 	`
 
@@ -171,6 +192,14 @@ func (s *saleRepository) CreateItem(ctx context.Context, item *model.SaleItem) e
 		item.UnitPrice,
 		item.Subtotal,
 		item.Discount,
+		item.TaxRateID,
+		item.TaxRate,
+		item.TaxInclusive,
+		item.TaxAmount,
+		item.PriceListID,
+		item.PromotionID,
+		item.PromotionDiscount,
+		item.Cogs,
 	).Scan(&id)
 
 	if err != nil {
@@ -183,6 +212,60 @@ func (s *saleRepository) CreateItem(ctx context.Context, item *model.SaleItem) e
 	return nil
 }
 
+func (s *saleRepository) FindItems(ctx context.Context, saleID int) ([]model.SaleItem, error) {
+	query := `
+	SELECT
+		si.id, si.sale_id, si.item_id, i.sku, i.name,
+		si.quantity, si.unit_price, si.subtotal, si.discount,
+		si.tax_rate_id, si.tax_rate, si.tax_inclusive, si.tax_amount,
+		si.price_list_id, si.promotion_id, si.promotion_discount, si.cogs, si.created_at
+	FROM sale_items si
+	JOIN items i ON i.id = si.item_id
+	WHERE si.sale_id = $1
+	ORDER BY si.id
+	`
+
+	rows, err := s.DB.Query(ctx, query, saleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.SaleItem
+	for rows.Next() {
+		var it model.SaleItem
+		if err := rows.Scan(
+			&it.ID,
+			&it.SaleID,
+			&it.ItemID,
+			&it.SKU,
+			&it.Name,
+			&it.Quantity,
+			&it.UnitPrice,
+			&it.Subtotal,
+			&it.Discount,
+			&it.TaxRateID,
+			&it.TaxRate,
+			&it.TaxInclusive,
+			&it.TaxAmount,
+			&it.PriceListID,
+			&it.PromotionID,
+			&it.PromotionDiscount,
+			&it.Cogs,
+			&it.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
 func (r *saleRepository) FindByID(ctx context.Context, id int) (*model.Sale, error) {
 	q := `SELECT id, invoice_number, grand_total FROM sales WHERE id=$1`
 
@@ -198,20 +281,32 @@ func (r *saleRepository) FindByID(ctx context.Context, id int) (*model.Sale, err
 	return s, nil
 }
 
-func (r *saleRepository) FindDetailByID(ctx context.Context, id int) (*model.Sale, error) {
-	query := `
+const saleDetailSelect = `
 	SELECT
-		id, invoice_number, customer_name, customer_phone, customer_email,
+		id, invoice_number, warehouse_id, customer_id, customer_name, customer_phone, customer_email,
 		sale_date, total_amount, discount, tax, grand_total,
-		payment_method, payment_status, notes, created_by, created_at, updated_at
+		payment_method, payment_status, notes,
+		cancelled_by, cancelled_at, cancel_reason,
+		created_by, created_at, updated_at
 	FROM sales
 	WHERE id = $1
-	`
+`
+
+func (r *saleRepository) FindDetailByID(ctx context.Context, id int) (*model.Sale, error) {
+	return r.findDetail(ctx, saleDetailSelect, id)
+}
 
+func (r *saleRepository) FindDetailByIDForUpdate(ctx context.Context, id int) (*model.Sale, error) {
+	return r.findDetail(ctx, saleDetailSelect+` FOR UPDATE`, id)
+}
+
+func (r *saleRepository) findDetail(ctx context.Context, query string, id int) (*model.Sale, error) {
 	sale := &model.Sale{}
 	err := r.DB.QueryRow(ctx, query, id).Scan(
 		&sale.ID,
 		&sale.InvoiceNumber,
+		&sale.WarehouseID,
+		&sale.CustomerID,
 		&sale.CustomerName,
 		&sale.CustomerPhone,
 		&sale.CustomerEmail,
@@ -223,6 +318,9 @@ func (r *saleRepository) FindDetailByID(ctx context.Context, id int) (*model.Sal
 		&sale.PaymentMethod,
 		&sale.PaymentStatus,
 		&sale.Notes,
+		&sale.CancelledBy,
+		&sale.CancelledAt,
+		&sale.CancelReason,
 		&sale.CreatedBy,
 		&sale.CreatedAt,
 		&sale.UpdatedAt,
@@ -276,7 +374,132 @@ func (r *saleRepository) UpdatePaymentStatus(ctx context.Context, id int, status
 	return nil
 }
 
+func (r *saleRepository) Cancel(ctx context.Context, id, userID int, reason string) error {
+	query := `
+	UPDATE sales
+	SET payment_status = 'cancelled',
+	    cancelled_by = $1,
+	    cancelled_at = NOW(),
+	    cancel_reason = $2,
+	    updated_at = NOW()
+	WHERE id = $3 AND payment_status <> 'cancelled'
+	`
+
+	res, err := r.DB.Exec(ctx, query, userID, reason, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("sale not found or already cancelled")
+	}
+
+	return nil
+}
+
+// hanya sale yang sudah cancelled, stoknya sudah dikembalikan lewat Cancel
 func (r *saleRepository) Delete(ctx context.Context, id int) error {
-	_, err := r.DB.Exec(ctx, `DELETE FROM sales WHERE id = $1`, id)
-	return err
+	tag, err := r.DB.Exec(ctx, `DELETE FROM sales WHERE id = $1 AND payment_status = 'cancelled'`, id)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New("sale not found or not cancelled, cancel the sale first")
+	}
+
+	return nil
+}
+
+func (r *saleRepository) ListsByCustomer(ctx context.Context, customerID, page, limit int) ([]model.Sale, int, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM sales WHERE customer_id = $1`, customerID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+	SELECT id, invoice_number, customer_name, sale_date, total_amount, discount, tax,
+	       grand_total, payment_status, payment_method, created_at
+	FROM sales
+	WHERE customer_id = $1
+	ORDER BY sale_date DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.DB.Query(ctx, query, customerID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var sales []model.Sale
+	for rows.Next() {
+		sl := model.Sale{CustomerID: &customerID}
+		if err := rows.Scan(
+			&sl.ID,
+			&sl.InvoiceNumber,
+			&sl.CustomerName,
+			&sl.SaleDate,
+			&sl.TotalAmount,
+			&sl.Discount,
+			&sl.Tax,
+			&sl.GrandTotal,
+			&sl.PaymentStatus,
+			&sl.PaymentMethod,
+			&sl.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		sales = append(sales, sl)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return sales, total, nil
+}
+
+func (r *saleRepository) SummaryByCustomer(ctx context.Context, customerID int) (*model.CustomerSalesSummary, error) {
+	// outstanding per sale = grand total - refund retur - pembayaran, sama dengan settlePaymentStatus
+	query := `
+	SELECT
+		COUNT(*),
+		COALESCE(SUM(s.grand_total), 0),
+		COALESCE(SUM(rf.refunded), 0),
+		COALESCE(SUM(p.paid), 0),
+		COALESCE(SUM(GREATEST(
+			GREATEST(s.grand_total - COALESCE(rf.refunded, 0), 0) - COALESCE(p.paid, 0),
+			0
+		)), 0)
+	FROM sales s
+	LEFT JOIN (
+		SELECT sale_id, SUM(amount) AS paid
+		FROM sale_payments
+		WHERE voided_at IS NULL
+		GROUP BY sale_id
+	) p ON p.sale_id = s.id
+	LEFT JOIN (
+		SELECT sale_id, SUM(refund_amount) AS refunded
+		FROM sale_returns
+		GROUP BY sale_id
+	) rf ON rf.sale_id = s.id
+	WHERE s.customer_id = $1 AND s.payment_status <> 'cancelled'
+	`
+
+	var sum model.CustomerSalesSummary
+	err := r.DB.QueryRow(ctx, query, customerID).Scan(
+		&sum.TotalSales,
+		&sum.TotalAmount,
+		&sum.TotalRefunded,
+		&sum.TotalPaid,
+		&sum.Outstanding,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sum, nil
 }
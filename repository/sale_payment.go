@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/model"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type SalePaymentRepository interface {
+	Create(ctx context.Context, p *model.SalePayment) error
+	ListsBySale(ctx context.Context, saleID int) ([]model.SalePayment, error)
+	FindByID(ctx context.Context, saleID, id int) (*model.SalePayment, error)
+	Void(ctx context.Context, id, userID int, reason string) error
+
+	// total pembayaran yang belum di-void
+	TotalPaid(ctx context.Context, saleID int) (model.Money, error)
+}
+
+type salePaymentRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewSalePaymentRepository(db DBTX, log *zap.Logger) SalePaymentRepository {
+	return &salePaymentRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+const salePaymentSelect = `
+	SELECT id, sale_id, amount, payment_method, reference, paid_at,
+	       voided_at, voided_by, void_reason, created_by, created_at
+	FROM sale_payments
+`
+
+func scanSalePayment(row pgx.Row, p *model.SalePayment) error {
+	return row.Scan(
+		&p.ID,
+		&p.SaleID,
+		&p.Amount,
+		&p.PaymentMethod,
+		&p.Reference,
+		&p.PaidAt,
+		&p.VoidedAt,
+		&p.VoidedBy,
+		&p.VoidReason,
+		&p.CreatedBy,
+		&p.CreatedAt,
+	)
+}
+
+func (r *salePaymentRepository) Create(ctx context.Context, p *model.SalePayment) error {
+	query := `
+	INSERT INTO sale_payments (sale_id, amount, payment_method, reference, created_by)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, paid_at, created_at;
+	`
+
+	err := r.DB.QueryRow(ctx, query,
+		p.SaleID,
+		p.Amount,
+		p.PaymentMethod,
+		p.Reference,
+		p.CreatedBy,
+	).Scan(&p.ID, &p.PaidAt, &p.CreatedAt)
+
+	if err != nil {
+		r.Logger.Error("failed to create sale payment", zap.Error(err))
+		return err
+	}
+
+	r.Logger.Info("sale payment created", zap.Int("id", p.ID), zap.Int("sale_id", p.SaleID))
+	return nil
+}
+
+func (r *salePaymentRepository) ListsBySale(ctx context.Context, saleID int) ([]model.SalePayment, error) {
+	rows, err := r.DB.Query(ctx, salePaymentSelect+`WHERE sale_id = $1 ORDER BY paid_at, id`, saleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []model.SalePayment
+	for rows.Next() {
+		var p model.SalePayment
+		if err := scanSalePayment(rows, &p); err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+func (r *salePaymentRepository) FindByID(ctx context.Context, saleID, id int) (*model.SalePayment, error) {
+	var p model.SalePayment
+	err := scanSalePayment(r.DB.QueryRow(ctx, salePaymentSelect+`WHERE sale_id = $1 AND id = $2`, saleID, id), &p)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("payment not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func (r *salePaymentRepository) Void(ctx context.Context, id, userID int, reason string) error {
+	query := `
+	UPDATE sale_payments
+	SET voided_at = NOW(),
+	    voided_by = $1,
+	    void_reason = $2
+	WHERE id = $3 AND voided_at IS NULL
+	`
+
+	res, err := r.DB.Exec(ctx, query, userID, reason, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("payment not found or already voided")
+	}
+
+	return nil
+}
+
+func (r *salePaymentRepository) TotalPaid(ctx context.Context, saleID int) (model.Money, error) {
+	query := `
+	SELECT COALESCE(SUM(amount), 0)
+	FROM sale_payments
+	WHERE sale_id = $1 AND voided_at IS NULL
+	`
+
+	var total model.Money
+	if err := r.DB.QueryRow(ctx, query, saleID).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/model"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type SaleReturnRepository interface {
+	Create(ctx context.Context, ret *model.SaleReturn) error
+	CreateItem(ctx context.Context, it *model.SaleReturnItem) error
+	ListsBySale(ctx context.Context, saleID int) ([]model.SaleReturn, error)
+
+	// total qty yang sudah diretur per sale_item_id
+	ReturnedQuantities(ctx context.Context, saleID int) (map[int]int, error)
+	// total refund semua retur satu sale
+	TotalRefunded(ctx context.Context, saleID int) (model.Money, error)
+}
+
+type saleReturnRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewSaleReturnRepository(db DBTX, log *zap.Logger) SaleReturnRepository {
+	return &saleReturnRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+func (r *saleReturnRepository) Create(ctx context.Context, ret *model.SaleReturn) error {
+	query := `
+	INSERT INTO sale_returns (sale_id, refund_amount, refund_method, reason, created_by)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, created_at;
+	`
+
+	err := r.DB.QueryRow(ctx, query,
+		ret.SaleID,
+		ret.RefundAmount,
+		ret.RefundMethod,
+		ret.Reason,
+		ret.CreatedBy,
+	).Scan(&ret.ID, &ret.CreatedAt)
+
+	if err != nil {
+		r.Logger.Error("failed to create sale return", zap.Error(err))
+		return err
+	}
+
+	r.Logger.Info("sale return created", zap.Int("id", ret.ID), zap.Int("sale_id", ret.SaleID))
+	return nil
+}
+
+func (r *saleReturnRepository) CreateItem(ctx context.Context, it *model.SaleReturnItem) error {
+	query := `
+	INSERT INTO sale_return_items (return_id, sale_item_id, item_id, rack_id, quantity, refund_amount)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id;
+	`
+
+	err := r.DB.QueryRow(ctx, query,
+		it.ReturnID,
+		it.SaleItemID,
+		it.ItemID,
+		it.RackID,
+		it.Quantity,
+		it.RefundAmount,
+	).Scan(&it.ID)
+
+	if err != nil {
+		r.Logger.Error("failed to create sale return item", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *saleReturnRepository) ListsBySale(ctx context.Context, saleID int) ([]model.SaleReturn, error) {
+	query := `
+	SELECT id, sale_id, refund_amount, refund_method, reason, created_by, created_at
+	FROM sale_returns
+	WHERE sale_id = $1
+	ORDER BY created_at, id
+	`
+
+	rows, err := r.DB.Query(ctx, query, saleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var returns []model.SaleReturn
+	index := map[int]int{}
+	for rows.Next() {
+		var ret model.SaleReturn
+		if err := rows.Scan(
+			&ret.ID,
+			&ret.SaleID,
+			&ret.RefundAmount,
+			&ret.RefundMethod,
+			&ret.Reason,
+			&ret.CreatedBy,
+			&ret.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		index[ret.ID] = len(returns)
+		returns = append(returns, ret)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	itemQuery := `
+	SELECT ri.id, ri.return_id, ri.sale_item_id, ri.item_id, ri.rack_id, ri.quantity, ri.refund_amount
+	FROM sale_return_items ri
+	JOIN sale_returns sr ON sr.id = ri.return_id
+	WHERE sr.sale_id = $1
+	ORDER BY ri.id
+	`
+
+	itemRows, err := r.DB.Query(ctx, itemQuery, saleID)
+	if err != nil {
+		return nil, err
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var it model.SaleReturnItem
+		if err := itemRows.Scan(
+			&it.ID,
+			&it.ReturnID,
+			&it.SaleItemID,
+			&it.ItemID,
+			&it.RackID,
+			&it.Quantity,
+			&it.RefundAmount,
+		); err != nil {
+			return nil, err
+		}
+		i := index[it.ReturnID]
+		returns[i].Items = append(returns[i].Items, it)
+	}
+
+	return returns, itemRows.Err()
+}
+
+func (r *saleReturnRepository) ReturnedQuantities(ctx context.Context, saleID int) (map[int]int, error) {
+	query := `
+	SELECT ri.sale_item_id, SUM(ri.quantity)
+	FROM sale_return_items ri
+	JOIN sale_returns sr ON sr.id = ri.return_id
+	WHERE sr.sale_id = $1
+	GROUP BY ri.sale_item_id
+	`
+
+	rows, err := r.DB.Query(ctx, query, saleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	returned := map[int]int{}
+	for rows.Next() {
+		var saleItemID, qty int
+		if err := rows.Scan(&saleItemID, &qty); err != nil {
+			return nil, err
+		}
+		returned[saleItemID] = qty
+	}
+
+	return returned, rows.Err()
+}
+
+func (r *saleReturnRepository) TotalRefunded(ctx context.Context, saleID int) (model.Money, error) {
+	var total model.Money
+	err := r.DB.QueryRow(ctx, `SELECT COALESCE(SUM(refund_amount), 0) FROM sale_returns WHERE sale_id = $1`, saleID).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
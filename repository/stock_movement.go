@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/model"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type StockMovementRepository interface {
+	Create(ctx context.Context, mv *model.StockMovement) error
+	ListsByItem(ctx context.Context, itemID, page, limit int) ([]model.StockMovement, int, error)
+	FindByReference(ctx context.Context, movementType string, referenceID int) ([]model.StockMovement, error)
+
+	// nilai persediaan item saat ini (total_cost semua mutasi)
+	ValueOnHand(ctx context.Context, itemID int) (model.Money, error)
+	// nilai yang sudah keluar untuk satu item di satu dokumen (positif)
+	OutgoingCost(ctx context.Context, movementType string, referenceID, itemID int) (model.Money, error)
+
+	// qty & nilai persediaan per gudang per item dari mutasi sebelum waktu until
+	Valuation(ctx context.Context, warehouseID *int, until time.Time) ([]model.InventoryValuation, error)
+}
+
+type stockMovementRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewStockMovementRepository(db DBTX, log *zap.Logger) StockMovementRepository {
+	return &stockMovementRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+func (r *stockMovementRepository) Create(ctx context.Context, mv *model.StockMovement) error {
+	query := `
+	INSERT INTO stock_movements (item_id, rack_id, movement_type, quantity, stock_after, reason, reference_id, notes, unit_cost, total_cost, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	RETURNING id, created_at;
+	`
+
+	err := r.DB.QueryRow(ctx, query,
+		mv.ItemID,
+		mv.RackID,
+		mv.MovementType,
+		mv.Quantity,
+		mv.StockAfter,
+		mv.Reason,
+		mv.ReferenceID,
+		mv.Notes,
+		mv.UnitCost,
+		mv.TotalCost,
+		mv.CreatedBy,
+	).Scan(&mv.ID, &mv.CreatedAt)
+
+	if err != nil {
+		r.Logger.Error("failed to create stock movement", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *stockMovementRepository) ListsByItem(ctx context.Context, itemID, page, limit int) ([]model.StockMovement, int, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM stock_movements WHERE item_id = $1`
+	if err := r.DB.QueryRow(ctx, countQuery, itemID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+	SELECT id, item_id, rack_id, movement_type, quantity, stock_after, reason, reference_id, notes, unit_cost, total_cost, created_by, created_at
+	FROM stock_movements
+	WHERE item_id = $1
+	ORDER BY created_at DESC, id DESC
+	LIMIT $2 OFFSET $3;
+	`
+
+	movements, err := r.scanMovements(ctx, query, itemID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return movements, total, nil
+}
+
+// semua mutasi milik satu dokumen (sale, transfer, retur, ...)
+func (r *stockMovementRepository) FindByReference(ctx context.Context, movementType string, referenceID int) ([]model.StockMovement, error) {
+	query := `
+	SELECT id, item_id, rack_id, movement_type, quantity, stock_after, reason, reference_id, notes, unit_cost, total_cost, created_by, created_at
+	FROM stock_movements
+	WHERE movement_type = $1 AND reference_id = $2
+	ORDER BY item_id, rack_id, id
+	`
+
+	return r.scanMovements(ctx, query, movementType, referenceID)
+}
+
+func (r *stockMovementRepository) scanMovements(ctx context.Context, query string, args ...any) ([]model.StockMovement, error) {
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []model.StockMovement
+	for rows.Next() {
+		var mv model.StockMovement
+		if err := rows.Scan(
+			&mv.ID,
+			&mv.ItemID,
+			&mv.RackID,
+			&mv.MovementType,
+			&mv.Quantity,
+			&mv.StockAfter,
+			&mv.Reason,
+			&mv.ReferenceID,
+			&mv.Notes,
+			&mv.UnitCost,
+			&mv.TotalCost,
+			&mv.CreatedBy,
+			&mv.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		movements = append(movements, mv)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+func (r *stockMovementRepository) ValueOnHand(ctx context.Context, itemID int) (model.Money, error) {
+	var value model.Money
+	err := r.DB.QueryRow(ctx, `SELECT COALESCE(SUM(total_cost), 0) FROM stock_movements WHERE item_id = $1`, itemID).Scan(&value)
+	return value, err
+}
+
+func (r *stockMovementRepository) OutgoingCost(ctx context.Context, movementType string, referenceID, itemID int) (model.Money, error) {
+	query := `
+	SELECT COALESCE(-SUM(total_cost), 0)
+	FROM stock_movements
+	WHERE movement_type = $1 AND reference_id = $2 AND item_id = $3 AND quantity < 0
+	`
+
+	var cost model.Money
+	err := r.DB.QueryRow(ctx, query, movementType, referenceID, itemID).Scan(&cost)
+	return cost, err
+}
+
+func (r *stockMovementRepository) Valuation(ctx context.Context, warehouseID *int, until time.Time) ([]model.InventoryValuation, error) {
+	// stok & nilai direkonstruksi dari mutasi, barang dalam perjalanan (transfer) tidak masuk gudang manapun
+	query := `
+	SELECT w.id, w.code, w.name, i.id, i.sku, i.name,
+		SUM(sm.quantity) AS quantity,
+		SUM(sm.total_cost) AS value
+	FROM stock_movements sm
+	JOIN racks r ON r.id = sm.rack_id
+	JOIN warehouses w ON w.id = r.warehouse_id
+	JOIN items i ON i.id = sm.item_id
+	WHERE sm.created_at < $1
+		AND ($2::int IS NULL OR w.id = $2)
+	GROUP BY w.id, w.code, w.name, i.id, i.sku, i.name
+	HAVING SUM(sm.quantity) <> 0 OR SUM(sm.total_cost) <> 0
+	ORDER BY w.code, i.sku
+	`
+
+	rows, err := r.DB.Query(ctx, query, until, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	valuation := []model.InventoryValuation{}
+	for rows.Next() {
+		var v model.InventoryValuation
+		if err := rows.Scan(
+			&v.WarehouseID,
+			&v.WarehouseCode,
+			&v.WarehouseName,
+			&v.ItemID,
+			&v.SKU,
+			&v.Name,
+			&v.Quantity,
+			&v.Value,
+		); err != nil {
+			return nil, err
+		}
+		if v.Quantity > 0 {
+			v.UnitCost = v.Value.MulRatio(1, int64(v.Quantity))
+		}
+		valuation = append(valuation, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return valuation, nil
+}
@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/model"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type SupplierRepository interface {
+	Create(ctx context.Context, s *model.Supplier) (*model.Supplier, error)
+	Lists(ctx context.Context, search string, page, limit int) ([]model.Supplier, int, error)
+	FindByID(ctx context.Context, id int) (*model.Supplier, error)
+	Update(ctx context.Context, s *model.Supplier) (*model.Supplier, error)
+	Delete(ctx context.Context, id int) error
+
+	// relasi item - supplier
+	ItemsBySupplier(ctx context.Context, supplierID int) ([]model.ItemSupplier, error)
+	SuppliersByItem(ctx context.Context, itemID int) ([]model.ItemSupplier, error)
+	UpsertItem(ctx context.Context, is *model.ItemSupplier) (*model.ItemSupplier, error)
+	DeleteItem(ctx context.Context, supplierID, itemID int) error
+	// lepas status supplier utama item, dipanggil sebelum memasang supplier utama baru
+	ClearPreferred(ctx context.Context, itemID int) error
+	// catat harga beli terakhir, relasi dibuat kalau belum ada
+	UpdateLastPurchase(ctx context.Context, itemID, supplierID int, price model.Money, at time.Time) error
+}
+
+type supplierRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewSupplierRepository(db DBTX, log *zap.Logger) SupplierRepository {
+	return &supplierRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+const supplierColumns = `id, code, name, contact_person, phone, email, address, city, payment_terms_days, notes, is_active, created_by, created_at, updated_at`
+
+func scanSupplier(row pgx.Row, s *model.Supplier) error {
+	return row.Scan(
+		&s.ID,
+		&s.Code,
+		&s.Name,
+		&s.ContactPerson,
+		&s.Phone,
+		&s.Email,
+		&s.Address,
+		&s.City,
+		&s.PaymentTermsDays,
+		&s.Notes,
+		&s.IsActive,
+		&s.CreatedBy,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+}
+
+func (r *supplierRepository) Create(ctx context.Context, s *model.Supplier) (*model.Supplier, error) {
+	query := `
+	INSERT INTO suppliers (code, name, contact_person, phone, email, address, city, payment_terms_days, notes, is_active, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, true, $10)
+	RETURNING ` + supplierColumns
+
+	var supplier model.Supplier
+	err := scanSupplier(r.DB.QueryRow(ctx, query,
+		s.Code,
+		s.Name,
+		s.ContactPerson,
+		s.Phone,
+		s.Email,
+		s.Address,
+		s.City,
+		s.PaymentTermsDays,
+		s.Notes,
+		s.CreatedBy,
+	), &supplier)
+
+	if err != nil {
+		r.Logger.Error("failed create supplier", zap.Error(err))
+		return nil, err
+	}
+
+	r.Logger.Info("supplier created successfully", zap.Int("id", supplier.ID))
+	return &supplier, nil
+}
+
+// search cocokkan kode, nama atau contact person
+func (r *supplierRepository) Lists(ctx context.Context, search string, page, limit int) ([]model.Supplier, int, error) {
+	offset := (page - 1) * limit
+
+	where := `WHERE is_active = true`
+	args := []any{}
+	if search != "" {
+		args = append(args, "%"+search+"%")
+		where += ` AND (code ILIKE $1 OR name ILIKE $1 OR contact_person ILIKE $1)`
+	}
+
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM suppliers `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+	SELECT %s FROM suppliers %s
+	ORDER BY name
+	LIMIT $%d OFFSET $%d
+	`, supplierColumns, where, len(args)-1, len(args))
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var suppliers []model.Supplier
+	for rows.Next() {
+		var s model.Supplier
+		if err := scanSupplier(rows, &s); err != nil {
+			return nil, 0, err
+		}
+		suppliers = append(suppliers, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return suppliers, total, nil
+}
+
+func (r *supplierRepository) FindByID(ctx context.Context, id int) (*model.Supplier, error) {
+	var s model.Supplier
+	err := scanSupplier(r.DB.QueryRow(ctx, `SELECT `+supplierColumns+` FROM suppliers WHERE id = $1`, id), &s)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("supplier not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (r *supplierRepository) Update(ctx context.Context, s *model.Supplier) (*model.Supplier, error) {
+	query := `
+	UPDATE suppliers
+	SET code = $1, name = $2, contact_person = $3, phone = $4, email = $5, address = $6, city = $7,
+		payment_terms_days = $8, notes = $9, is_active = $10
+	WHERE id = $11
+	RETURNING ` + supplierColumns
+
+	var supplier model.Supplier
+	err := scanSupplier(r.DB.QueryRow(ctx, query,
+		s.Code,
+		s.Name,
+		s.ContactPerson,
+		s.Phone,
+		s.Email,
+		s.Address,
+		s.City,
+		s.PaymentTermsDays,
+		s.Notes,
+		s.IsActive,
+		s.ID,
+	), &supplier)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("supplier not found")
+	}
+	if err != nil {
+		r.Logger.Error("failed update supplier", zap.Error(err))
+		return nil, err
+	}
+
+	return &supplier, nil
+}
+
+// soft delete, dokumen pembelian lama tetap merujuk supplier
+func (r *supplierRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.DB.Exec(ctx, `UPDATE suppliers SET is_active = false WHERE id = $1 AND is_active = true`, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("supplier not found or already deleted")
+	}
+
+	return nil
+}
+
+const itemSupplierSelect = `
+	SELECT isu.id, isu.item_id, i.sku, i.name, isu.supplier_id, s.name,
+		isu.supplier_sku, isu.lead_time_days, isu.min_order_quantity,
+		isu.last_purchase_price, isu.last_purchased_at, isu.is_preferred,
+		isu.created_at, isu.updated_at
+	FROM item_suppliers isu
+	JOIN items i ON i.id = isu.item_id
+	JOIN suppliers s ON s.id = isu.supplier_id
+	`
+
+func scanItemSupplier(row pgx.Row, is *model.ItemSupplier) error {
+	return row.Scan(
+		&is.ID,
+		&is.ItemID,
+		&is.SKU,
+		&is.Name,
+		&is.SupplierID,
+		&is.SupplierName,
+		&is.SupplierSKU,
+		&is.LeadTimeDays,
+		&is.MinOrderQuantity,
+		&is.LastPurchasePrice,
+		&is.LastPurchasedAt,
+		&is.IsPreferred,
+		&is.CreatedAt,
+		&is.UpdatedAt,
+	)
+}
+
+func (r *supplierRepository) ItemsBySupplier(ctx context.Context, supplierID int) ([]model.ItemSupplier, error) {
+	return r.listItemSuppliers(ctx, itemSupplierSelect+`WHERE isu.supplier_id = $1 ORDER BY i.sku`, supplierID)
+}
+
+// supplier utama dulu
+func (r *supplierRepository) SuppliersByItem(ctx context.Context, itemID int) ([]model.ItemSupplier, error) {
+	return r.listItemSuppliers(ctx, itemSupplierSelect+`WHERE isu.item_id = $1 ORDER BY isu.is_preferred DESC, s.name`, itemID)
+}
+
+func (r *supplierRepository) listItemSuppliers(ctx context.Context, query string, args ...any) ([]model.ItemSupplier, error) {
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []model.ItemSupplier{}
+	for rows.Next() {
+		var is model.ItemSupplier
+		if err := scanItemSupplier(rows, &is); err != nil {
+			return nil, err
+		}
+		links = append(links, is)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+func (r *supplierRepository) UpsertItem(ctx context.Context, is *model.ItemSupplier) (*model.ItemSupplier, error) {
+	query := `
+	INSERT INTO item_suppliers (item_id, supplier_id, supplier_sku, lead_time_days, min_order_quantity, last_purchase_price, is_preferred)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (item_id, supplier_id) DO UPDATE SET
+		supplier_sku = EXCLUDED.supplier_sku,
+		lead_time_days = EXCLUDED.lead_time_days,
+		min_order_quantity = EXCLUDED.min_order_quantity,
+		last_purchase_price = COALESCE(EXCLUDED.last_purchase_price, item_suppliers.last_purchase_price),
+		is_preferred = EXCLUDED.is_preferred
+	RETURNING id
+	`
+
+	var id int
+	err := r.DB.QueryRow(ctx, query,
+		is.ItemID,
+		is.SupplierID,
+		is.SupplierSKU,
+		is.LeadTimeDays,
+		is.MinOrderQuantity,
+		is.LastPurchasePrice,
+		is.IsPreferred,
+	).Scan(&id)
+
+	if err != nil {
+		r.Logger.Error("failed upsert item supplier", zap.Error(err))
+		return nil, err
+	}
+
+	var link model.ItemSupplier
+	if err := scanItemSupplier(r.DB.QueryRow(ctx, itemSupplierSelect+`WHERE isu.id = $1`, id), &link); err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+func (r *supplierRepository) DeleteItem(ctx context.Context, supplierID, itemID int) error {
+	res, err := r.DB.Exec(ctx, `DELETE FROM item_suppliers WHERE supplier_id = $1 AND item_id = $2`, supplierID, itemID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("item is not linked to this supplier")
+	}
+
+	return nil
+}
+
+func (r *supplierRepository) ClearPreferred(ctx context.Context, itemID int) error {
+	_, err := r.DB.Exec(ctx, `UPDATE item_suppliers SET is_preferred = false WHERE item_id = $1 AND is_preferred`, itemID)
+	return err
+}
+
+func (r *supplierRepository) UpdateLastPurchase(ctx context.Context, itemID, supplierID int, price model.Money, at time.Time) error {
+	query := `
+	INSERT INTO item_suppliers (item_id, supplier_id, last_purchase_price, last_purchased_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (item_id, supplier_id) DO UPDATE SET
+		last_purchase_price = EXCLUDED.last_purchase_price,
+		last_purchased_at = EXCLUDED.last_purchased_at
+	`
+
+	_, err := r.DB.Exec(ctx, query, itemID, supplierID, price, at)
+	return err
+}
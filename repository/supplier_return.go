@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type SupplierReturnRepository interface {
+	Create(ctx context.Context, sr *model.SupplierReturn) (*model.SupplierReturn, error)
+	CreateItem(ctx context.Context, it *model.SupplierReturnItem) error
+	Lists(ctx context.Context, filter dto.SupplierReturnFilter, page, limit int) ([]model.SupplierReturn, int, error)
+	FindByID(ctx context.Context, id int) (*model.SupplierReturn, error)
+	FindByIDForUpdate(ctx context.Context, id int) (*model.SupplierReturn, error)
+	FindItems(ctx context.Context, returnID int) ([]model.SupplierReturnItem, error)
+
+	// kredit dari supplier, status kredit retur ikut dihitung ulang
+	CreateCredit(ctx context.Context, c *model.SupplierCredit) (*model.SupplierCredit, error)
+	FindCredits(ctx context.Context, returnID int) ([]model.SupplierCredit, error)
+	AddCredited(ctx context.Context, id int, amount model.Money) error
+
+	CreditSummary(ctx context.Context, supplierID int) (*model.SupplierCreditSummary, error)
+}
+
+type supplierReturnRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewSupplierReturnRepository(db DBTX, log *zap.Logger) SupplierReturnRepository {
+	return &supplierReturnRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+const supplierReturnSelect = `
+	SELECT
+		sr.id, sr.return_number, sr.supplier_id, s.name, sr.goods_receipt_id, sr.rack_id,
+		sr.return_date, sr.reason, sr.notes,
+		sr.credit_amount, sr.credited_amount, sr.credit_status,
+		sr.created_by, sr.created_at, sr.updated_at
+	FROM supplier_returns sr
+	JOIN suppliers s ON s.id = sr.supplier_id
+`
+
+func scanSupplierReturn(row pgx.Row, sr *model.SupplierReturn) error {
+	return row.Scan(
+		&sr.ID,
+		&sr.ReturnNumber,
+		&sr.SupplierID,
+		&sr.SupplierName,
+		&sr.GoodsReceiptID,
+		&sr.RackID,
+		&sr.ReturnDate,
+		&sr.Reason,
+		&sr.Notes,
+		&sr.CreditAmount,
+		&sr.CreditedAmount,
+		&sr.CreditStatus,
+		&sr.CreatedBy,
+		&sr.CreatedAt,
+		&sr.UpdatedAt,
+	)
+}
+
+func (r *supplierReturnRepository) Create(ctx context.Context, sr *model.SupplierReturn) (*model.SupplierReturn, error) {
+	query := `
+	INSERT INTO supplier_returns (return_number, supplier_id, goods_receipt_id, rack_id, return_date, reason, notes, credit_amount, credit_status, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING id;
+	`
+
+	var id int
+	err := r.DB.QueryRow(ctx, query,
+		sr.ReturnNumber,
+		sr.SupplierID,
+		sr.GoodsReceiptID,
+		sr.RackID,
+		sr.ReturnDate,
+		sr.Reason,
+		sr.Notes,
+		sr.CreditAmount,
+		model.CreditPending,
+		sr.CreatedBy,
+	).Scan(&id)
+
+	if err != nil {
+		r.Logger.Error("failed to create supplier return", zap.Error(err))
+		return nil, err
+	}
+
+	r.Logger.Info("supplier return created", zap.Int("id", id))
+	return r.FindByID(ctx, id)
+}
+
+func (r *supplierReturnRepository) CreateItem(ctx context.Context, it *model.SupplierReturnItem) error {
+	query := `
+	INSERT INTO supplier_return_items (supplier_return_id, item_id, goods_receipt_item_id, quantity, unit_cost, credit_amount)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id;
+	`
+
+	err := r.DB.QueryRow(ctx, query,
+		it.SupplierReturnID,
+		it.ItemID,
+		it.GoodsReceiptItemID,
+		it.Quantity,
+		it.UnitCost,
+		it.CreditAmount,
+	).Scan(&it.ID)
+
+	if err != nil {
+		r.Logger.Error("failed to create supplier return item", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *supplierReturnRepository) Lists(ctx context.Context, filter dto.SupplierReturnFilter, page, limit int) ([]model.SupplierReturn, int, error) {
+	offset := (page - 1) * limit
+
+	var conds []string
+	var args []any
+
+	if filter.SupplierID != 0 {
+		args = append(args, filter.SupplierID)
+		conds = append(conds, fmt.Sprintf("sr.supplier_id = $%d", len(args)))
+	}
+	if filter.CreditStatus != "" {
+		args = append(args, filter.CreditStatus)
+		conds = append(conds, fmt.Sprintf("sr.credit_status = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM supplier_returns sr `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, limit, offset)
+	query := supplierReturnSelect + where + fmt.Sprintf(`
+	ORDER BY sr.return_date DESC, sr.id DESC
+	LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args))
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var returns []model.SupplierReturn
+	for rows.Next() {
+		var sr model.SupplierReturn
+		if err := scanSupplierReturn(rows, &sr); err != nil {
+			return nil, 0, err
+		}
+		returns = append(returns, sr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return returns, total, nil
+}
+
+func (r *supplierReturnRepository) FindByID(ctx context.Context, id int) (*model.SupplierReturn, error) {
+	return r.findOne(ctx, supplierReturnSelect+`WHERE sr.id = $1`, id)
+}
+
+// lock dokumen retur selama pencatatan kredit
+func (r *supplierReturnRepository) FindByIDForUpdate(ctx context.Context, id int) (*model.SupplierReturn, error) {
+	return r.findOne(ctx, supplierReturnSelect+`WHERE sr.id = $1 FOR UPDATE OF sr`, id)
+}
+
+func (r *supplierReturnRepository) findOne(ctx context.Context, query string, id int) (*model.SupplierReturn, error) {
+	var sr model.SupplierReturn
+	err := scanSupplierReturn(r.DB.QueryRow(ctx, query, id), &sr)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("supplier return not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &sr, nil
+}
+
+func (r *supplierReturnRepository) FindItems(ctx context.Context, returnID int) ([]model.SupplierReturnItem, error) {
+	query := `
+	SELECT sri.id, sri.supplier_return_id, sri.item_id, i.sku, i.name, sri.goods_receipt_item_id,
+		sri.quantity, sri.unit_cost, sri.credit_amount
+	FROM supplier_return_items sri
+	JOIN items i ON i.id = sri.item_id
+	WHERE sri.supplier_return_id = $1
+	ORDER BY sri.item_id
+	`
+
+	rows, err := r.DB.Query(ctx, query, returnID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.SupplierReturnItem
+	for rows.Next() {
+		var it model.SupplierReturnItem
+		if err := rows.Scan(
+			&it.ID,
+			&it.SupplierReturnID,
+			&it.ItemID,
+			&it.SKU,
+			&it.Name,
+			&it.GoodsReceiptItemID,
+			&it.Quantity,
+			&it.UnitCost,
+			&it.CreditAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *supplierReturnRepository) CreateCredit(ctx context.Context, c *model.SupplierCredit) (*model.SupplierCredit, error) {
+	query := `
+	INSERT INTO supplier_credits (supplier_return_id, amount, reference, credited_at, notes, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id, supplier_return_id, amount, reference, credited_at, notes, created_by, created_at
+	`
+
+	var credit model.SupplierCredit
+	err := r.DB.QueryRow(ctx, query,
+		c.SupplierReturnID,
+		c.Amount,
+		c.Reference,
+		c.CreditedAt,
+		c.Notes,
+		c.CreatedBy,
+	).Scan(
+		&credit.ID,
+		&credit.SupplierReturnID,
+		&credit.Amount,
+		&credit.Reference,
+		&credit.CreditedAt,
+		&credit.Notes,
+		&credit.CreatedBy,
+		&credit.CreatedAt,
+	)
+
+	if err != nil {
+		r.Logger.Error("failed to create supplier credit", zap.Error(err))
+		return nil, err
+	}
+
+	return &credit, nil
+}
+
+func (r *supplierReturnRepository) FindCredits(ctx context.Context, returnID int) ([]model.SupplierCredit, error) {
+	rows, err := r.DB.Query(ctx, `
+	SELECT id, supplier_return_id, amount, reference, credited_at, notes, created_by, created_at
+	FROM supplier_credits
+	WHERE supplier_return_id = $1
+	ORDER BY credited_at, id
+	`, returnID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var credits []model.SupplierCredit
+	for rows.Next() {
+		var c model.SupplierCredit
+		if err := rows.Scan(
+			&c.ID,
+			&c.SupplierReturnID,
+			&c.Amount,
+			&c.Reference,
+			&c.CreditedAt,
+			&c.Notes,
+			&c.CreatedBy,
+			&c.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		credits = append(credits, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return credits, nil
+}
+
+func (r *supplierReturnRepository) AddCredited(ctx context.Context, id int, amount model.Money) error {
+	query := `
+	UPDATE supplier_returns
+	SET credited_amount = credited_amount + $1::numeric,
+	    credit_status = CASE
+	        WHEN credited_amount + $1::numeric >= credit_amount THEN 'credited'
+	        ELSE 'partially_credited'
+	    END
+	WHERE id = $2 AND credited_amount + $1::numeric <= credit_amount
+	`
+
+	res, err := r.DB.Exec(ctx, query, amount, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("credit exceeds outstanding return credit")
+	}
+
+	return nil
+}
+
+func (r *supplierReturnRepository) CreditSummary(ctx context.Context, supplierID int) (*model.SupplierCreditSummary, error) {
+	summary := model.SupplierCreditSummary{SupplierID: supplierID}
+
+	err := r.DB.QueryRow(ctx, `
+	SELECT COUNT(*), COALESCE(SUM(credit_amount), 0), COALESCE(SUM(credited_amount), 0)
+	FROM supplier_returns
+	WHERE supplier_id = $1
+	`, supplierID).Scan(&summary.TotalReturns, &summary.CreditAmount, &summary.CreditedAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Outstanding = summary.CreditAmount - summary.CreditedAmount
+	return &summary, nil
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/model"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type TaxRateRepository interface {
+	Create(ctx context.Context, t *model.TaxRate) (*model.TaxRate, error)
+	Lists(ctx context.Context, page, limit int) ([]model.TaxRate, int, error)
+	FindByID(ctx context.Context, id int) (*model.TaxRate, error)
+	Update(ctx context.Context, t *model.TaxRate) (*model.TaxRate, error)
+	Delete(ctx context.Context, id int) error
+
+	// tarif yang berlaku per item: tarif item, kalau kosong tarif kategorinya
+	ForItems(ctx context.Context, itemIDs []int) (map[int]*model.TaxRate, error)
+}
+
+type taxRateRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewTaxRateRepository(db DBTX, log *zap.Logger) TaxRateRepository {
+	return &taxRateRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+const taxRateColumns = `id, code, name, rate, is_inclusive, is_active, created_by, created_at, updated_at`
+
+func scanTaxRate(row pgx.Row, t *model.TaxRate) error {
+	return row.Scan(
+		&t.ID,
+		&t.Code,
+		&t.Name,
+		&t.Rate,
+		&t.IsInclusive,
+		&t.IsActive,
+		&t.CreatedBy,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+}
+
+func (r *taxRateRepository) Create(ctx context.Context, t *model.TaxRate) (*model.TaxRate, error) {
+	query := `
+	INSERT INTO tax_rates (code, name, rate, is_inclusive, is_active, created_by)
+	VALUES ($1, $2, $3, $4, true, $5)
+	RETURNING ` + taxRateColumns
+
+	var rate model.TaxRate
+	err := scanTaxRate(r.DB.QueryRow(ctx, query,
+		t.Code,
+		t.Name,
+		t.Rate,
+		t.IsInclusive,
+		t.CreatedBy,
+	), &rate)
+
+	if err != nil {
+		r.Logger.Error("failed create tax rate", zap.Error(err))
+		return nil, err
+	}
+
+	r.Logger.Info("tax rate created successfully", zap.Int("id", rate.ID))
+	return &rate, nil
+}
+
+func (r *taxRateRepository) Lists(ctx context.Context, page, limit int) ([]model.TaxRate, int, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM tax_rates`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.DB.Query(ctx, `SELECT `+taxRateColumns+` FROM tax_rates ORDER BY code LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var rates []model.TaxRate
+	for rows.Next() {
+		var t model.TaxRate
+		if err := scanTaxRate(rows, &t); err != nil {
+			return nil, 0, err
+		}
+		rates = append(rates, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return rates, total, nil
+}
+
+func (r *taxRateRepository) FindByID(ctx context.Context, id int) (*model.TaxRate, error) {
+	var t model.TaxRate
+	err := scanTaxRate(r.DB.QueryRow(ctx, `SELECT `+taxRateColumns+` FROM tax_rates WHERE id = $1`, id), &t)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("tax rate not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (r *taxRateRepository) Update(ctx context.Context, t *model.TaxRate) (*model.TaxRate, error) {
+	query := `
+	UPDATE tax_rates
+	SET code = $1, name = $2, rate = $3, is_inclusive = $4, is_active = $5
+	WHERE id = $6
+	RETURNING ` + taxRateColumns
+
+	var rate model.TaxRate
+	err := scanTaxRate(r.DB.QueryRow(ctx, query,
+		t.Code,
+		t.Name,
+		t.Rate,
+		t.IsInclusive,
+		t.IsActive,
+		t.ID,
+	), &rate)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("tax rate not found")
+	}
+	if err != nil {
+		r.Logger.Error("failed update tax rate", zap.Error(err))
+		return nil, err
+	}
+
+	return &rate, nil
+}
+
+// soft delete, sale lama tetap menyimpan snapshot rate-nya sendiri
+func (r *taxRateRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.DB.Exec(ctx, `UPDATE tax_rates SET is_active = false WHERE id = $1 AND is_active = true`, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("tax rate not found or already deleted")
+	}
+
+	return nil
+}
+
+func (r *taxRateRepository) ForItems(ctx context.Context, itemIDs []int) (map[int]*model.TaxRate, error) {
+	// tarif nonaktif dianggap tidak ada, jatuh ke tarif kategori
+	query := `
+	SELECT i.id, t.id, t.code, t.name, t.rate, t.is_inclusive, t.is_active, t.created_by, t.created_at, t.updated_at
+	FROM items i
+	JOIN categories c ON c.id = i.category_id
+	LEFT JOIN tax_rates it ON it.id = i.tax_rate_id AND it.is_active = true
+	LEFT JOIN tax_rates ct ON ct.id = c.tax_rate_id AND ct.is_active = true
+	JOIN tax_rates t ON t.id = COALESCE(it.id, ct.id)
+	WHERE i.id = ANY($1)
+	`
+
+	rows, err := r.DB.Query(ctx, query, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := map[int]*model.TaxRate{}
+	for rows.Next() {
+		var itemID int
+		var t model.TaxRate
+		if err := rows.Scan(
+			&itemID,
+			&t.ID,
+			&t.Code,
+			&t.Name,
+			&t.Rate,
+			&t.IsInclusive,
+			&t.IsActive,
+			&t.CreatedBy,
+			&t.CreatedAt,
+			&t.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		rates[itemID] = &t
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
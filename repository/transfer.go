@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type TransferRepository interface {
+	Create(ctx context.Context, t *model.StockTransfer) (*model.StockTransfer, error)
+	CreateItem(ctx context.Context, it *model.StockTransferItem) error
+	Lists(ctx context.Context, filter dto.TransferFilter, page, limit int) ([]model.StockTransfer, int, error)
+	FindByID(ctx context.Context, id int) (*model.StockTransfer, error)
+	FindByIDForUpdate(ctx context.Context, id int) (*model.StockTransfer, error)
+	FindItems(ctx context.Context, transferID int) ([]model.StockTransferItem, error)
+
+	// perubahan status
+	MarkShipped(ctx context.Context, id, userID int) error
+	UpdateStatus(ctx context.Context, id int, status string) error
+	AddReceived(ctx context.Context, lineID, qty int) error
+}
+
+type transferRepository struct {
+	DB     DBTX
+	Logger *zap.Logger
+}
+
+func NewTransferRepository(db DBTX, log *zap.Logger) TransferRepository {
+	return &transferRepository{
+		DB:     db,
+		Logger: log,
+	}
+}
+
+const transferSelect = `
+	SELECT
+		t.id, t.source_rack_id, t.destination_rack_id, t.status,
+		sr.warehouse_id, dr.warehouse_id,
+		t.notes, t.created_by, t.shipped_by, t.shipped_at, t.received_at,
+		t.created_at, t.updated_at
+	FROM stock_transfers t
+	JOIN racks sr ON sr.id = t.source_rack_id
+	JOIN racks dr ON dr.id = t.destination_rack_id
+`
+
+func scanTransfer(row pgx.Row, t *model.StockTransfer) error {
+	return row.Scan(
+		&t.ID,
+		&t.SourceRackID,
+		&t.DestinationRackID,
+		&t.Status,
+		&t.SourceWarehouseID,
+		&t.DestinationWarehouseID,
+		&t.Notes,
+		&t.CreatedBy,
+		&t.ShippedBy,
+		&t.ShippedAt,
+		&t.ReceivedAt,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+}
+
+func (r *transferRepository) Create(ctx context.Context, t *model.StockTransfer) (*model.StockTransfer, error) {
+	query := `
+	INSERT INTO stock_transfers (source_rack_id, destination_rack_id, status, notes, created_by)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id;
+	`
+
+	var id int
+	err := r.DB.QueryRow(ctx, query,
+		t.SourceRackID,
+		t.DestinationRackID,
+		model.TransferDraft,
+		t.Notes,
+		t.CreatedBy,
+	).Scan(&id)
+
+	if err != nil {
+		r.Logger.Error("failed to create stock transfer", zap.Error(err))
+		return nil, err
+	}
+
+	r.Logger.Info("stock transfer created", zap.Int("id", id))
+	return r.FindByID(ctx, id)
+}
+
+func (r *transferRepository) CreateItem(ctx context.Context, it *model.StockTransferItem) error {
+	query := `
+	INSERT INTO stock_transfer_items (transfer_id, item_id, quantity)
+	VALUES ($1, $2, $3)
+	RETURNING id;
+	`
+
+	err := r.DB.QueryRow(ctx, query, it.TransferID, it.ItemID, it.Quantity).Scan(&it.ID)
+	if err != nil {
+		r.Logger.Error("failed to create stock transfer item", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *transferRepository) Lists(ctx context.Context, filter dto.TransferFilter, page, limit int) ([]model.StockTransfer, int, error) {
+	offset := (page - 1) * limit
+
+	var conds []string
+	var args []any
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conds = append(conds, fmt.Sprintf("t.status = $%d", len(args)))
+	}
+	if filter.SourceWarehouseID != 0 {
+		args = append(args, filter.SourceWarehouseID)
+		conds = append(conds, fmt.Sprintf("sr.warehouse_id = $%d", len(args)))
+	}
+	if filter.DestinationWarehouseID != 0 {
+		args = append(args, filter.DestinationWarehouseID)
+		conds = append(conds, fmt.Sprintf("dr.warehouse_id = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int
+	countQuery := `
+	SELECT COUNT(*)
+	FROM stock_transfers t
+	JOIN racks sr ON sr.id = t.source_rack_id
+	JOIN racks dr ON dr.id = t.destination_rack_id
+	` + where
+	if err := r.DB.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, limit, offset)
+	query := transferSelect + where + fmt.Sprintf(`
+	ORDER BY t.created_at DESC
+	LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args))
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var transfers []model.StockTransfer
+	for rows.Next() {
+		var t model.StockTransfer
+		if err := scanTransfer(rows, &t); err != nil {
+			return nil, 0, err
+		}
+		transfers = append(transfers, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return transfers, total, nil
+}
+
+func (r *transferRepository) FindByID(ctx context.Context, id int) (*model.StockTransfer, error) {
+	return r.findOne(ctx, transferSelect+`WHERE t.id = $1`, id)
+}
+
+// lock dokumen transfer selama perubahan status
+func (r *transferRepository) FindByIDForUpdate(ctx context.Context, id int) (*model.StockTransfer, error) {
+	return r.findOne(ctx, transferSelect+`WHERE t.id = $1 FOR UPDATE OF t`, id)
+}
+
+func (r *transferRepository) findOne(ctx context.Context, query string, id int) (*model.StockTransfer, error) {
+	var t model.StockTransfer
+	err := scanTransfer(r.DB.QueryRow(ctx, query, id), &t)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("transfer not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (r *transferRepository) FindItems(ctx context.Context, transferID int) ([]model.StockTransferItem, error) {
+	query := `
+	SELECT ti.id, ti.transfer_id, ti.item_id, i.sku, i.name, ti.quantity, ti.received_quantity
+	FROM stock_transfer_items ti
+	JOIN items i ON i.id = ti.item_id
+	WHERE ti.transfer_id = $1
+	ORDER BY ti.item_id
+	`
+
+	rows, err := r.DB.Query(ctx, query, transferID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.StockTransferItem
+	for rows.Next() {
+		var it model.StockTransferItem
+		if err := rows.Scan(
+			&it.ID,
+			&it.TransferID,
+			&it.ItemID,
+			&it.SKU,
+			&it.Name,
+			&it.Quantity,
+			&it.ReceivedQuantity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *transferRepository) MarkShipped(ctx context.Context, id, userID int) error {
+	query := `
+	UPDATE stock_transfers
+	SET status = $1,
+	    shipped_by = $2,
+	    shipped_at = NOW(),
+	    updated_at = NOW()
+	WHERE id = $3
+	`
+
+	res, err := r.DB.Exec(ctx, query, model.TransferInTransit, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("transfer not found")
+	}
+
+	return nil
+}
+
+func (r *transferRepository) UpdateStatus(ctx context.Context, id int, status string) error {
+	query := `
+	UPDATE stock_transfers
+	SET status = $1,
+	    received_at = CASE WHEN $1 IN ('received', 'closed') THEN NOW() ELSE received_at END,
+	    updated_at = NOW()
+	WHERE id = $2
+	`
+
+	res, err := r.DB.Exec(ctx, query, status, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("transfer not found")
+	}
+
+	return nil
+}
+
+func (r *transferRepository) AddReceived(ctx context.Context, lineID, qty int) error {
+	query := `
+	UPDATE stock_transfer_items
+	SET received_quantity = received_quantity + $1
+	WHERE id = $2 AND received_quantity + $1 <= quantity
+	`
+
+	res, err := r.DB.Exec(ctx, query, qty, lineID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("received quantity exceeds transferred quantity")
+	}
+
+	return nil
+}
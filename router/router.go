@@ -82,14 +82,79 @@ func NewRouter(h *handler.Container, log *zap.Logger) *chi.Mux {
 		r.Route("/items", func(r chi.Router) {
 			r.With(role.AllowAdmin()).Post("/", h.Items.Create)
 			r.With(role.AllowRead()).Get("/", h.Items.Lists)
+			// item dengan stok di bawah minimum
+			r.With(role.AllowAllRole()).Get("/low-stock", h.Items.LowStock)
 
 			r.Route("/{id}", func(r chi.Router) {
 				r.With(role.AllowRead()).Get("/", h.Items.DetailById)
 				r.With(role.AllowAllRole()).Put("/", h.Items.Update)
 				r.With(role.AllowAdmin()).Delete("/", h.Items.Delete)
+
+				// stok hanya berubah lewat adjustment
+				r.With(role.AllowAllRole()).Post("/adjustments", h.Items.Adjust)
+				r.With(role.AllowRead()).Get("/movements", h.Items.Movements)
+
+				// harga item di tiap daftar harga
+				r.With(role.AllowRead()).Get("/prices", h.PriceList.ItemPrices)
+
+				// riwayat & jadwal perubahan harga / cost
+				r.With(role.AllowRead()).Get("/price-history", h.Items.PriceHistory)
+				r.With(role.AllowAdmin()).Post("/price-changes", h.Items.SchedulePriceChange)
+				r.With(role.AllowAdmin()).Post("/price-changes/{changeId}/cancel", h.Items.CancelPriceChange)
+
+				// supplier yang bisa menyuplai item
+				r.With(role.AllowRead()).Get("/suppliers", h.Supplier.ItemSuppliers)
+			})
+		})
+
+		// transfer stok antar rak / gudang
+		r.Route("/transfers", func(r chi.Router) {
+			r.With(role.AllowRead()).Get("/", h.Transfer.Lists)
+			r.With(role.AllowAllRole()).Post("/", h.Transfer.Create)
+
+			r.Route("/{id}", func(r chi.Router) {
+				r.With(role.AllowRead()).Get("/", h.Transfer.DetailById)
+				r.With(role.AllowAllRole()).Post("/ship", h.Transfer.Ship)
+				r.With(role.AllowAllRole()).Post("/receive", h.Transfer.Receive)
+				r.With(role.AllowAllRole()).Post("/close", h.Transfer.Close)
+				r.With(role.AllowAllRole()).Post("/cancel", h.Transfer.Cancel)
+			})
+		})
+
+		// pembelian ke supplier, stok masuk lewat penerimaan barang (GRN)
+		r.Route("/purchase-orders", func(r chi.Router) {
+			r.With(role.AllowRead()).Get("/", h.Purchase.Lists)
+			r.With(role.AllowAllRole()).Post("/", h.Purchase.Create)
+			// PO draft dari saran reorder, satu PO per supplier utama
+			r.With(role.AllowAdmin()).Post("/from-reorder", h.Purchase.CreateFromReorder)
+
+			r.Route("/{id}", func(r chi.Router) {
+				r.With(role.AllowRead()).Get("/", h.Purchase.DetailById)
+				r.With(role.AllowAllRole()).Put("/", h.Purchase.Update)
+				r.With(role.AllowAdmin()).Post("/approve", h.Purchase.Approve)
+				r.With(role.AllowAllRole()).Post("/receipts", h.Purchase.Receive)
+				r.With(role.AllowAdmin()).Post("/close", h.Purchase.Close)
+				r.With(role.AllowAdmin()).Post("/cancel", h.Purchase.Cancel)
 			})
 		})
 
+		// retur barang ke supplier + kredit yang diterima
+		r.Route("/supplier-returns", func(r chi.Router) {
+			r.With(role.AllowRead()).Get("/", h.SupplierReturn.Lists)
+			r.With(role.AllowAllRole()).Post("/", h.SupplierReturn.Create)
+
+			r.Route("/{id}", func(r chi.Router) {
+				r.With(role.AllowRead()).Get("/", h.SupplierReturn.DetailById)
+				r.With(role.AllowAdmin()).Post("/credits", h.SupplierReturn.AddCredit)
+			})
+		})
+
+		// notifikasi sistem (stok di bawah minimum)
+		r.Route("/notifications", func(r chi.Router) {
+			r.With(role.AllowAllRole()).Get("/", h.Notification.Lists)
+			r.With(role.AllowAllRole()).Post("/{id}/read", h.Notification.MarkRead)
+		})
+
 		r.Route("/categories", func(r chi.Router) {
 			// read all role
 			r.With(role.AllowRead()).Get("/", h.Category.Lists)
@@ -103,12 +168,95 @@ func NewRouter(h *handler.Container, log *zap.Logger) *chi.Mux {
 			})
 		})
 
+		// master tarif pajak, dipasang per kategori / item
+		r.Route("/tax-rates", func(r chi.Router) {
+			r.With(role.AllowRead()).Get("/", h.TaxRate.Lists)
+			r.With(role.AllowAdmin()).Post("/", h.TaxRate.Create)
+
+			r.Route("/{id}", func(r chi.Router) {
+				r.With(role.AllowRead()).Get("/", h.TaxRate.DetailById)
+				r.With(role.AllowAdmin()).Put("/", h.TaxRate.Update)
+				r.With(role.AllowAdmin()).Delete("/", h.TaxRate.Delete)
+			})
+		})
+
+		// daftar harga (retail, grosir, reseller) + harga bertingkat per qty
+		r.Route("/price-lists", func(r chi.Router) {
+			r.With(role.AllowRead()).Get("/", h.PriceList.Lists)
+			r.With(role.AllowAdmin()).Post("/", h.PriceList.Create)
+
+			r.Route("/{id}", func(r chi.Router) {
+				r.With(role.AllowRead()).Get("/", h.PriceList.DetailById)
+				r.With(role.AllowAdmin()).Put("/", h.PriceList.Update)
+				r.With(role.AllowAdmin()).Delete("/", h.PriceList.Delete)
+
+				r.With(role.AllowAdmin()).Post("/items", h.PriceList.UpsertItem)
+				r.With(role.AllowAdmin()).Delete("/items/{priceItemId}", h.PriceList.DeleteItem)
+			})
+		})
+
+		// promo & aturan diskon, dievaluasi otomatis saat sale dibuat
+		r.Route("/promotions", func(r chi.Router) {
+			r.With(role.AllowRead()).Get("/", h.Promotion.Lists)
+			r.With(role.AllowAdmin()).Post("/", h.Promotion.Create)
+
+			r.Route("/{id}", func(r chi.Router) {
+				r.With(role.AllowRead()).Get("/", h.Promotion.DetailById)
+				r.With(role.AllowAdmin()).Put("/", h.Promotion.Update)
+				r.With(role.AllowAdmin()).Delete("/", h.Promotion.Delete)
+			})
+		})
+
+		r.Route("/customers", func(r chi.Router) {
+			r.With(role.AllowRead()).Get("/", h.Customer.Lists)
+			r.With(role.AllowAdmin()).Post("/", h.Customer.Create)
+
+			r.Route("/{id}", func(r chi.Router) {
+				r.With(role.AllowRead()).Get("/", h.Customer.DetailById)
+				r.With(role.AllowAdmin()).Put("/", h.Customer.Update)
+				r.With(role.AllowAdmin()).Delete("/", h.Customer.Delete)
+
+				// riwayat belanja + sisa tagihan
+				r.With(role.AllowRead()).Get("/sales", h.Customer.Sales)
+			})
+		})
+
+		// supplier + item yang disuplai (sku supplier, lead time, harga beli terakhir)
+		r.Route("/suppliers", func(r chi.Router) {
+			r.With(role.AllowRead()).Get("/", h.Supplier.Lists)
+			r.With(role.AllowAdmin()).Post("/", h.Supplier.Create)
+
+			r.Route("/{id}", func(r chi.Router) {
+				r.With(role.AllowRead()).Get("/", h.Supplier.DetailById)
+				r.With(role.AllowAdmin()).Put("/", h.Supplier.Update)
+				r.With(role.AllowAdmin()).Delete("/", h.Supplier.Delete)
+
+				r.With(role.AllowRead()).Get("/items", h.Supplier.Items)
+				r.With(role.AllowAdmin()).Post("/items", h.Supplier.UpsertItem)
+				r.With(role.AllowAdmin()).Delete("/items/{itemId}", h.Supplier.DeleteItem)
+
+				// total kredit retur yang belum diterima dari supplier
+				r.With(role.AllowRead()).Get("/credits", h.SupplierReturn.CreditSummary)
+			})
+		})
+
+		// laporan, admin & super admin
+		r.Route("/reports", func(r chi.Router) {
+			r.With(role.AllowAdmin()).Get("/inventory-valuation", h.Report.InventoryValuation)
+			r.With(role.AllowAdmin()).Get("/reorder-suggestions", h.Report.ReorderSuggestions)
+			r.With(role.AllowAdmin()).Get("/sales", h.Report.Sales)
+			r.With(role.AllowAdmin()).Get("/gross-margin", h.Report.GrossMargin)
+			r.With(role.AllowAdmin()).Get("/inventory-aging", h.Report.InventoryAging)
+			r.With(role.AllowAdmin()).Get("/dead-stock", h.Report.DeadStock)
+		})
+
 		r.Route("/sale", func(r chi.Router) {
 			r.With(role.AllowRead()).Get("/", h.Sale.Lists)
 			r.With(role.AllowAdmin()).Post("/", h.Sale.Create)
 
 			r.Route("/{id}", func(r chi.Router) {
 				r.With(role.AllowRead()).Get("/", h.Sale.FindById)
+				r.With(role.AllowRead()).Get("/items", h.Sale.Items)
 				// update data sale (customer, notes, dll)
 				r.With(role.AllowSuperAdmin()).Put("/", h.Sale.Update)
 
@@ -116,6 +264,16 @@ func NewRouter(h *handler.Container, log *zap.Logger) *chi.Mux {
 
 				// update transaction
 				r.With(role.AllowAdmin()).Patch("/payment-status", h.Sale.UpdateSalePaymentStatus)
+				r.With(role.AllowAdmin()).Post("/cancel", h.Sale.Cancel)
+
+				// retur & refund
+				r.With(role.AllowRead()).Get("/returns", h.Sale.Returns)
+				r.With(role.AllowAdmin()).Post("/returns", h.Sale.CreateReturn)
+
+				// pembayaran (bisa sebagian / split)
+				r.With(role.AllowRead()).Get("/payments", h.Sale.Payments)
+				r.With(role.AllowAdmin()).Post("/payments", h.Sale.AddPayment)
+				r.With(role.AllowAdmin()).Post("/payments/{paymentId}/void", h.Sale.VoidPayment)
 			})
 		})
 	})
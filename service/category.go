@@ -60,6 +60,7 @@ func (c *categoryService) Create(ctx context.Context, usr *model.User, req dto.C
 		Code:        req.Code,
 		Name:        req.Name,
 		Description: req.Description,
+		TaxRateID:   req.TaxRateID,
 		IsActive:    true,
 		CreatedBy:   &createdBy,
 	}
@@ -121,6 +122,7 @@ func (c *categoryService) Update(ctx context.Context, usr *model.User, id int, r
 		Code:        req.Code,
 		Name:        req.Name,
 		Description: req.Description,
+		TaxRateID:   req.TaxRateID,
 		IsActive:    *req.IsActive,
 	}
 
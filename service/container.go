@@ -3,6 +3,7 @@ package service
 import (
 	"alfdwirhmn/inventory/database"
 	"alfdwirhmn/inventory/repository"
+	"alfdwirhmn/inventory/utils"
 
 	"go.uber.org/zap"
 )
@@ -15,9 +16,20 @@ type Container struct {
 	Racks     RacksService
 	Items     ItemsService
 	Sale      SaleService
+	Transfer  TransferService
+	Purchase  PurchaseService
+	Customer  CustomerService
+	Supplier  SupplierService
+	TaxRate   TaxRateService
+	Promotion PromotionService
+	PriceList PriceListService
+	Report    ReportService
+
+	SupplierReturn SupplierReturnService
+	Notification   NotificationService
 }
 
-func NewContainer(repo *repository.Container, log *zap.Logger, tx database.TxManager) *Container {
+func NewContainer(repo *repository.Container, log *zap.Logger, tx database.TxManager, conf utils.Configuration) *Container {
 	permSvc := NewPermissionService()
 
 	return &Container{
@@ -26,13 +38,64 @@ func NewContainer(repo *repository.Container, log *zap.Logger, tx database.TxMan
 		Category:  NewCategoryService(repo.CategoryRepo, permSvc),
 		Warehouse: NewWarehouseService(repo.WarehouseRepo, permSvc),
 		Racks:     NewRacksService(repo.RacksRepo, permSvc),
-		Items:     NewItemsService(repo.ItemsRepo, permSvc),
+		Items:     NewItemsService(repo.ItemsRepo, repo.ItemLocationRepo, repo.StockMovementRepo, repo.PriceHistoryRepo, conf.CostingMethod, permSvc, tx, log),
 		Sale: NewSaleService(
 			repo.SaleRepo,
 			repo.ItemsRepo,
+			repo.SaleReturnRepo,
+			repo.SalePaymentRepo,
+			repo.WarehouseRepo,
+			conf.InvoicePattern,
+			conf.CostingMethod,
+			permSvc,
+			tx,
+			log,
+		),
+		Transfer: NewTransferService(
+			repo.TransferRepo,
+			repo.RacksRepo,
+			repo.ItemsRepo,
+			conf.CostingMethod,
+			permSvc,
+			tx,
+			log,
+		),
+		Purchase: NewPurchaseService(
+			repo.PurchaseOrderRepo,
+			repo.GoodsReceiptRepo,
+			repo.SupplierRepo,
+			repo.ItemsRepo,
+			repo.WarehouseRepo,
+			repo.RacksRepo,
+			repo.ReportRepo,
+			conf.PurchaseOrderPattern,
+			conf.GoodsReceiptPattern,
+			conf.CostingMethod,
+			permSvc,
+			tx,
+			log,
+		),
+		Customer:  NewCustomerService(repo.CustomerRepo, repo.SaleRepo, permSvc),
+		Supplier:  NewSupplierService(repo.SupplierRepo, repo.ItemsRepo, permSvc, tx, log),
+		TaxRate:   NewTaxRateService(repo.TaxRateRepo, permSvc),
+		Promotion: NewPromotionService(repo.PromotionRepo, permSvc),
+		PriceList: NewPriceListService(repo.PriceListRepo, repo.ItemsRepo, permSvc),
+		Report:    NewReportService(repo.StockMovementRepo, repo.ReportRepo, conf.CostingMethod, permSvc),
+
+		SupplierReturn: NewSupplierReturnService(
+			repo.SupplierReturnRepo,
+			repo.GoodsReceiptRepo,
+			repo.PurchaseOrderRepo,
+			repo.SupplierRepo,
+			repo.ItemsRepo,
+			repo.RacksRepo,
+			repo.WarehouseRepo,
+			conf.SupplierReturnPattern,
+			conf.CostingMethod,
 			permSvc,
 			tx,
 			log,
 		),
+		Notification: NewNotificationService(repo.NotificationRepo, permSvc, log),
 	}
 }
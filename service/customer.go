@@ -0,0 +1,142 @@
+package service
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/repository"
+	"alfdwirhmn/inventory/utils"
+	"context"
+	"errors"
+)
+
+type CustomerService interface {
+	Create(ctx context.Context, usr *model.User, req dto.CreateCustomerRequest) (*model.Customer, error)
+	FindAll(ctx context.Context, search string, page, limit int) (*[]model.Customer, *dto.Pagination, error)
+	FindByID(ctx context.Context, usr *model.User, id int) (*model.Customer, error)
+	Update(ctx context.Context, usr *model.User, id int, req dto.UpdateCustomerRequest) (*model.Customer, error)
+	Delete(ctx context.Context, usr *model.User, id int) error
+
+	// riwayat sale customer beserta ringkasan total
+	Sales(ctx context.Context, usr *model.User, id, page, limit int) (*dto.CustomerSalesResponseDTO, *dto.Pagination, error)
+}
+
+type customerService struct {
+	repo     repository.CustomerRepository
+	saleRepo repository.SaleRepository
+	permSvc  PermissionService
+}
+
+func NewCustomerService(repo repository.CustomerRepository, saleRepo repository.SaleRepository, permSvc PermissionService) CustomerService {
+	return &customerService{
+		repo:     repo,
+		saleRepo: saleRepo,
+		permSvc:  permSvc,
+	}
+}
+
+func (s *customerService) Create(ctx context.Context, usr *model.User, req dto.CreateCustomerRequest) (*model.Customer, error) {
+	if !s.permSvc.CanCreateMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot create customer")
+	}
+
+	createdBy := usr.ID
+
+	return s.repo.Create(ctx, &model.Customer{
+		Name:        req.Name,
+		Phone:       req.Phone,
+		Email:       req.Email,
+		Address:     req.Address,
+		Notes:       req.Notes,
+		IsActive:    true,
+		PriceListID: req.PriceListID,
+		CreatedBy:   &createdBy,
+	})
+}
+
+func (s *customerService) FindAll(ctx context.Context, search string, page, limit int) (*[]model.Customer, *dto.Pagination, error) {
+	customers, total, err := s.repo.Lists(ctx, search, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := dto.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: utils.TotalPage(limit, int64(total)),
+		TotalRows:  total,
+	}
+
+	return &customers, &pagination, nil
+}
+
+func (s *customerService) FindByID(ctx context.Context, usr *model.User, id int) (*model.Customer, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot access customer")
+	}
+
+	return s.repo.FindByID(ctx, id)
+}
+
+func (s *customerService) Update(ctx context.Context, usr *model.User, id int, req dto.UpdateCustomerRequest) (*model.Customer, error) {
+	if !s.permSvc.CanUpdateMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot update customer")
+	}
+
+	return s.repo.Update(ctx, &model.Customer{
+		ID:          id,
+		Name:        req.Name,
+		Phone:       req.Phone,
+		Email:       req.Email,
+		Address:     req.Address,
+		Notes:       req.Notes,
+		IsActive:    req.IsActive,
+		PriceListID: req.PriceListID,
+	})
+}
+
+func (s *customerService) Delete(ctx context.Context, usr *model.User, id int) error {
+	if !s.permSvc.CanDeleteMasterData(usr.Role) {
+		return errors.New("forbidden: cannot delete customer")
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *customerService) Sales(ctx context.Context, usr *model.User, id, page, limit int) (*dto.CustomerSalesResponseDTO, *dto.Pagination, error) {
+	if !s.permSvc.CanViewSale(usr.Role) {
+		return nil, nil, errors.New("forbidden")
+	}
+
+	customer, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary, err := s.saleRepo.SummaryByCustomer(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sales, total, err := s.saleRepo.ListsByCustomer(ctx, id, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := &dto.CustomerSalesResponseDTO{
+		Customer: *customer,
+		Summary:  *summary,
+		Sales:    make([]dto.SaleResponseDTO, 0, len(sales)),
+	}
+	for i := range sales {
+		resp.Sales = append(resp.Sales, dto.ToSaleResponseDTO(&sales[i]))
+	}
+
+	pagination := dto.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: utils.TotalPage(limit, int64(total)),
+		TotalRows:  total,
+	}
+
+	return resp, &pagination, nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"alfdwirhmn/inventory/repository"
+	"alfdwirhmn/inventory/utils"
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrWarehouseRequired dokumen tanpa gudang tidak bisa diberi nomor dari pattern yang memakai {WAREHOUSE}
+var ErrWarehouseRequired = errors.New("warehouse_id is required by the document number pattern")
+
+// numberSequence generate nomor dokumen dari pattern, sequence-nya disimpan per prefix & periode.
+// db harus transaksi yang sama dengan insert dokumennya supaya nomor tetap berurutan tanpa celah.
+type numberSequence struct {
+	pattern string
+	log     *zap.Logger
+}
+
+func newNumberSequence(pattern string, log *zap.Logger) *numberSequence {
+	return &numberSequence{
+		pattern: pattern,
+		log:     log,
+	}
+}
+
+// needsWarehouse true kalau pattern memakai kode gudang, cek sebelum transaksi dimulai
+func (n *numberSequence) needsWarehouse() bool {
+	return utils.PatternNeedsWarehouse(n.pattern)
+}
+
+func (n *numberSequence) Next(ctx context.Context, db repository.DBTX, warehouseCode string, at time.Time) (string, error) {
+	number, err := utils.NewDocumentNumber(n.pattern, warehouseCode, at)
+	if err != nil {
+		return "", err
+	}
+
+	seq, err := repository.NewNumberSequenceRepository(db, n.log).Next(ctx, number.Prefix, number.Period)
+	if err != nil {
+		return "", err
+	}
+
+	return number.Format(seq), nil
+}
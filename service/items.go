@@ -1,12 +1,16 @@
 package service
 
 import (
+	"alfdwirhmn/inventory/database"
 	"alfdwirhmn/inventory/dto"
 	"alfdwirhmn/inventory/model"
 	"alfdwirhmn/inventory/repository"
 	"alfdwirhmn/inventory/utils"
 	"context"
 	"errors"
+	"time"
+
+	"go.uber.org/zap"
 )
 
 type ItemsService interface {
@@ -15,17 +19,42 @@ type ItemsService interface {
 	Update(ctx context.Context, usr *model.User, id int, req dto.UpdateItemRequest) (*model.Item, error)
 	Delete(ctx context.Context, usr *model.User, id int) error
 	FindByID(ctx context.Context, id int, usr *model.User) (*model.Item, error)
+
+	// stok
+	Adjust(ctx context.Context, usr *model.User, id int, req dto.CreateStockAdjustmentRequest) (*model.StockMovement, error)
+	Movements(ctx context.Context, usr *model.User, id, page, limit int) ([]model.StockMovement, *dto.Pagination, error)
+	LowStock(ctx context.Context, usr *model.User, filter dto.LowStockFilter, page, limit int) ([]model.LowStockItem, *dto.Pagination, error)
+
+	// riwayat & jadwal perubahan harga / cost
+	PriceHistory(ctx context.Context, usr *model.User, id, page, limit int) ([]model.ItemPriceChange, *dto.Pagination, error)
+	SchedulePriceChange(ctx context.Context, usr *model.User, id int, req dto.CreatePriceChangeRequest) (*model.ItemPriceChange, error)
+	CancelPriceChange(ctx context.Context, usr *model.User, id, changeID int) error
+
+	// dipanggil scheduler, return jumlah perubahan yang diterapkan
+	ApplyScheduledPrices(ctx context.Context) (int, error)
 }
 
 type itemsService struct {
-	repo    repository.ItemsRepository
-	permSvc PermissionService
+	repo         repository.ItemsRepository
+	locationRepo repository.ItemLocationRepository
+	movementRepo repository.StockMovementRepository
+	historyRepo  repository.PriceHistoryRepository
+	costing      string
+	txMgr        database.TxManager
+	permSvc      PermissionService
+	log          *zap.Logger
 }
 
-func NewItemsService(repo repository.ItemsRepository, permSvc PermissionService) ItemsService {
+func NewItemsService(repo repository.ItemsRepository, locationRepo repository.ItemLocationRepository, movementRepo repository.StockMovementRepository, historyRepo repository.PriceHistoryRepository, costing string, permSvc PermissionService, tx database.TxManager, log *zap.Logger) ItemsService {
 	return &itemsService{
-		repo:    repo,
-		permSvc: permSvc,
+		repo:         repo,
+		locationRepo: locationRepo,
+		movementRepo: movementRepo,
+		historyRepo:  historyRepo,
+		costing:      costing,
+		txMgr:        tx,
+		permSvc:      permSvc,
+		log:          log,
 	}
 }
 
@@ -41,13 +70,13 @@ func (s *itemsService) Create(ctx context.Context, usr *model.User, req dto.Crea
 	items := &model.Item{
 		CategoryID:   req.CategoryID,
 		RackID:       req.RackID,
+		TaxRateID:    req.TaxRateID,
 		SKU:          req.SKU,
 		Name:         req.Name,
 		Description:  req.Description,
 		Unit:         req.Unit,
 		Price:        req.Price,
 		Cost:         req.Cost,
-		Stock:        req.Stock,
 		MinimumStock: req.MinimumStock,
 		Weight:       req.Weight,
 		Dimensions:   req.Dimensions,
@@ -55,7 +84,44 @@ func (s *itemsService) Create(ctx context.Context, usr *model.User, req dto.Crea
 		CreatedBy:    &createdBy,
 	}
 
-	return s.repo.Create(ctx, items)
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// item dibuat dengan stok 0, stok awal masuk lewat ledger
+	created, err := repository.NewItemsRepository(tx, s.log).Create(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Stock > 0 {
+		if req.RackID == nil {
+			return nil, errors.New("rack_id is required for opening stock")
+		}
+
+		notes := "opening stock"
+		mv := &model.StockMovement{
+			ItemID:       created.ID,
+			RackID:       *req.RackID,
+			MovementType: model.MovementReceipt,
+			Quantity:     req.Stock,
+			TotalCost:    created.Cost.Mul(req.Stock),
+			Notes:        &notes,
+			CreatedBy:    &createdBy,
+		}
+		if err := newStockLedger(tx, s.log, s.costing).Post(ctx, mv); err != nil {
+			return nil, err
+		}
+		created.Stock = mv.StockAfter
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return created, nil
 }
 
 func (s *itemsService) FindAll(page, limit int) (*[]model.Item, *dto.Pagination, error) {
@@ -79,7 +145,59 @@ func (s *itemsService) Update(ctx context.Context, user *model.User, id int, req
 		return nil, errors.New("forbidden: cannot update item")
 	}
 
-	return s.repo.Update(ctx, id, req)
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// lock item supaya harga lama yang dicatat di riwayat tidak basi
+	locked, err := newStockLedger(tx, s.log, s.costing).Lock(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := repository.NewItemsRepository(tx, s.log).Update(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if change := priceChange(locked[id], item); change != nil {
+		change.CreatedBy = &user.ID
+		if _, err := repository.NewPriceHistoryRepository(tx, s.log).Create(ctx, change); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// priceChange membandingkan harga & cost sebelum dan sesudah update, nil kalau tidak berubah
+func priceChange(before, after *model.Item) *model.ItemPriceChange {
+	if before.Price == after.Price && before.Cost == after.Cost {
+		return nil
+	}
+
+	now := time.Now()
+	change := &model.ItemPriceChange{
+		ItemID:      after.ID,
+		EffectiveAt: now,
+		Status:      model.PriceChangeApplied,
+		AppliedAt:   &now,
+	}
+
+	if before.Price != after.Price {
+		change.OldPrice, change.NewPrice = &before.Price, &after.Price
+	}
+	if before.Cost != after.Cost {
+		change.OldCost, change.NewCost = &before.Cost, &after.Cost
+	}
+
+	return change
 }
 
 func (s *itemsService) FindByID(ctx context.Context, id int, usr *model.User) (*model.Item, error) {
@@ -88,7 +206,18 @@ func (s *itemsService) FindByID(ctx context.Context, id int, usr *model.User) (*
 		return nil, errors.New("forbidden: cannot access items")
 	}
 
-	return s.repo.FindByID(ctx, id)
+	item, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// rincian stok per rak & gudang
+	item.Locations, err = s.locationRepo.FindByItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
 }
 
 func (s *itemsService) Delete(ctx context.Context, usr *model.User, id int) error {
@@ -98,3 +227,253 @@ func (s *itemsService) Delete(ctx context.Context, usr *model.User, id int) erro
 
 	return s.repo.Delete(ctx, id)
 }
+
+func (s *itemsService) Adjust(ctx context.Context, usr *model.User, id int, req dto.CreateStockAdjustmentRequest) (*model.StockMovement, error) {
+	if !s.permSvc.CanUpdateStock(usr.Role) {
+		return nil, errors.New("forbidden: cannot update stock")
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	item, err := repository.NewItemsRepository(tx, s.log).FindByID(ctx, id)
+	if err != nil || !item.IsActive {
+		return nil, errors.New("item not found or already deleted")
+	}
+
+	rackID := req.RackID
+	if rackID == nil {
+		rackID = item.RackID
+	}
+	if rackID == nil {
+		return nil, errors.New("rack_id is required, item has no default rack")
+	}
+
+	mv := &model.StockMovement{
+		ItemID:       item.ID,
+		RackID:       *rackID,
+		MovementType: model.MovementAdjustment,
+		Quantity:     req.Quantity,
+		Reason:       &req.Reason,
+		Notes:        req.Notes,
+		CreatedBy:    &usr.ID,
+	}
+	if err := newStockLedger(tx, s.log, s.costing).Post(ctx, mv); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return mv, nil
+}
+
+func (s *itemsService) Movements(ctx context.Context, usr *model.User, id, page, limit int) ([]model.StockMovement, *dto.Pagination, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, nil, errors.New("forbidden: cannot access stock movements")
+	}
+
+	movements, total, err := s.movementRepo.ListsByItem(ctx, id, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: utils.TotalPage(limit, int64(total)),
+		TotalRows:  total,
+	}
+
+	return movements, pagination, nil
+}
+
+func (s *itemsService) LowStock(ctx context.Context, usr *model.User, filter dto.LowStockFilter, page, limit int) ([]model.LowStockItem, *dto.Pagination, error) {
+	if !s.permSvc.CanCheckMinStock(usr.Role) {
+		return nil, nil, errors.New("forbidden: cannot check minimum stock")
+	}
+
+	items, total, err := s.repo.LowStock(ctx, filter, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: utils.TotalPage(limit, int64(total)),
+		TotalRows:  total,
+	}
+
+	return items, pagination, nil
+}
+
+func (s *itemsService) PriceHistory(ctx context.Context, usr *model.User, id, page, limit int) ([]model.ItemPriceChange, *dto.Pagination, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, nil, errors.New("forbidden: cannot access price history")
+	}
+
+	changes, total, err := s.historyRepo.ListsByItem(ctx, id, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: utils.TotalPage(limit, int64(total)),
+		TotalRows:  total,
+	}
+
+	return changes, pagination, nil
+}
+
+// effective_at kosong / sudah lewat = langsung diterapkan, selain itu dijadwalkan
+func (s *itemsService) SchedulePriceChange(ctx context.Context, usr *model.User, id int, req dto.CreatePriceChangeRequest) (*model.ItemPriceChange, error) {
+	if !s.permSvc.CanUpdateMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot update item price")
+	}
+
+	if req.Price == nil && req.Cost == nil {
+		return nil, errors.New("price or cost is required")
+	}
+
+	item, err := s.repo.FindByID(ctx, id)
+	if err != nil || !item.IsActive {
+		return nil, errors.New("item not found or already deleted")
+	}
+
+	if req.EffectiveAt != nil && req.EffectiveAt.After(time.Now()) {
+		return s.historyRepo.Create(ctx, &model.ItemPriceChange{
+			ItemID:      id,
+			NewPrice:    req.Price,
+			NewCost:     req.Cost,
+			EffectiveAt: *req.EffectiveAt,
+			Status:      model.PriceChangeScheduled,
+			Reason:      req.Reason,
+			CreatedBy:   &usr.ID,
+		})
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	locked, err := newStockLedger(tx, s.log, s.costing).Lock(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := repository.NewItemsRepository(tx, s.log).Update(ctx, id, dto.UpdateItemRequest{
+		Price: req.Price,
+		Cost:  req.Cost,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	change := priceChange(locked[id], updated)
+	if change == nil {
+		return nil, errors.New("price and cost are unchanged")
+	}
+	change.Reason = req.Reason
+	change.CreatedBy = &usr.ID
+
+	change, err = repository.NewPriceHistoryRepository(tx, s.log).Create(ctx, change)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+func (s *itemsService) CancelPriceChange(ctx context.Context, usr *model.User, id, changeID int) error {
+	if !s.permSvc.CanUpdateMasterData(usr.Role) {
+		return errors.New("forbidden: cannot update item price")
+	}
+
+	return s.historyRepo.Cancel(ctx, id, changeID)
+}
+
+func (s *itemsService) ApplyScheduledPrices(ctx context.Context) (int, error) {
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	historyRepo := repository.NewPriceHistoryRepository(tx, s.log)
+	itemRepo := repository.NewItemsRepository(tx, s.log)
+
+	due, err := historyRepo.DueScheduled(ctx, time.Now(), 100)
+	if err != nil {
+		return 0, err
+	}
+	if len(due) == 0 {
+		return 0, nil
+	}
+
+	itemIDs := make([]int, 0, len(due))
+	for _, c := range due {
+		itemIDs = append(itemIDs, c.ItemID)
+	}
+
+	items, err := newStockLedger(tx, s.log, s.costing).Lock(ctx, itemIDs...)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, c := range due {
+		item := items[c.ItemID]
+
+		// item sudah dihapus, jadwalnya dibatalkan
+		if !item.IsActive {
+			if err := historyRepo.Cancel(ctx, c.ItemID, c.ID); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		updated, err := itemRepo.Update(ctx, c.ItemID, dto.UpdateItemRequest{
+			Price: c.NewPrice,
+			Cost:  c.NewCost,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		// nilai lama hanya dicatat untuk field yang memang dijadwalkan berubah
+		var oldPrice, oldCost *model.Money
+		if c.NewPrice != nil {
+			oldPrice = &item.Price
+		}
+		if c.NewCost != nil {
+			oldCost = &item.Cost
+		}
+
+		if err := historyRepo.MarkApplied(ctx, c.ID, oldPrice, oldCost); err != nil {
+			return 0, err
+		}
+
+		// jadwal berikutnya untuk item yang sama dibandingkan dengan harga terbaru
+		items[c.ItemID] = updated
+		applied++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RunLowStockChecker membuat notifikasi untuk item yang turun di bawah minimum_stock
+// setelah sale / adjustment, dicek setiap interval sampai ctx selesai
+func RunLowStockChecker(ctx context.Context, svc NotificationService, interval time.Duration, log *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		created, err := svc.CheckLowStock(ctx)
+		if err != nil {
+			log.Error("failed check low stock items", zap.Error(err))
+		} else if created > 0 {
+			log.Info("low stock notifications created", zap.Int("count", created))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/repository"
+	"alfdwirhmn/inventory/utils"
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+type NotificationService interface {
+	FindAll(ctx context.Context, usr *model.User, filter dto.NotificationFilter, page, limit int) ([]model.Notification, *dto.Pagination, error)
+	MarkRead(ctx context.Context, usr *model.User, id int) (*model.Notification, error)
+
+	// dipanggil checker, return jumlah notifikasi low stock baru
+	CheckLowStock(ctx context.Context) (int, error)
+}
+
+type notificationService struct {
+	repo    repository.NotificationRepository
+	permSvc PermissionService
+	log     *zap.Logger
+}
+
+func NewNotificationService(repo repository.NotificationRepository, permSvc PermissionService, log *zap.Logger) NotificationService {
+	return &notificationService{
+		repo:    repo,
+		permSvc: permSvc,
+		log:     log,
+	}
+}
+
+func (s *notificationService) FindAll(ctx context.Context, usr *model.User, filter dto.NotificationFilter, page, limit int) ([]model.Notification, *dto.Pagination, error) {
+	if !s.permSvc.CanCheckMinStock(usr.Role) {
+		return nil, nil, errors.New("forbidden: cannot access notifications")
+	}
+
+	notifications, total, err := s.repo.Lists(ctx, filter, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: utils.TotalPage(limit, int64(total)),
+		TotalRows:  total,
+	}
+
+	return notifications, pagination, nil
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, usr *model.User, id int) (*model.Notification, error) {
+	if !s.permSvc.CanCheckMinStock(usr.Role) {
+		return nil, errors.New("forbidden: cannot access notifications")
+	}
+
+	return s.repo.MarkRead(ctx, id, usr.ID)
+}
+
+func (s *notificationService) CheckLowStock(ctx context.Context) (int, error) {
+	// tutup dulu yang sudah normal supaya item yang turun lagi dapat notifikasi baru
+	if _, err := s.repo.ResolveLowStock(ctx); err != nil {
+		return 0, err
+	}
+
+	created, err := s.repo.CreateLowStock(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, n := range created {
+		s.log.Warn("item below minimum stock",
+			zap.Intp("item_id", n.ItemID),
+			zap.Intp("stock", n.Stock),
+			zap.Intp("minimum_stock", n.MinimumStock),
+		)
+	}
+
+	return len(created), nil
+}
@@ -28,6 +28,10 @@ type PermissionService interface {
 	CanUpdateSale(role string) bool
 	CanDeleteSale(role string) bool
 
+	// PURCHASE
+	CanCreatePurchase(role string) bool
+	CanApprovePurchase(role string) bool
+
 	// REPORT
 	CanAccessReports(role string) bool
 }
@@ -152,6 +156,15 @@ func (s *permissionService) CanDeleteSale(role string) bool {
 	return role == "super_admin"
 }
 
+// purchase, semua role bisa buat draft PO, approve hanya admin dan super admin
+func (s *permissionService) CanCreatePurchase(role string) bool {
+	return role == "super_admin" || role == "admin" || role == "staff"
+}
+
+func (s *permissionService) CanApprovePurchase(role string) bool {
+	return role == "super_admin" || role == "admin"
+}
+
 // report, hanya admin dan super yang bisa akses report
 func (s *permissionService) CanAccessReports(role string) bool {
 	return role == "super_admin" || role == "admin"
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/repository"
+	"alfdwirhmn/inventory/utils"
+	"context"
+	"errors"
+)
+
+type PriceListService interface {
+	Create(ctx context.Context, usr *model.User, req dto.CreatePriceListRequest) (*model.PriceList, error)
+	FindAll(ctx context.Context, page, limit int) (*[]model.PriceList, *dto.Pagination, error)
+	FindByID(ctx context.Context, usr *model.User, id int) (*model.PriceList, error)
+	Update(ctx context.Context, usr *model.User, id int, req dto.UpdatePriceListRequest) (*model.PriceList, error)
+	Delete(ctx context.Context, usr *model.User, id int) error
+
+	UpsertItem(ctx context.Context, usr *model.User, id int, req dto.UpsertPriceListItemRequest) (*model.PriceListItem, error)
+	DeleteItem(ctx context.Context, usr *model.User, id, priceItemID int) error
+
+	// harga satu item di semua daftar harga
+	ItemPrices(ctx context.Context, usr *model.User, itemID int) (*dto.ItemPricesResponseDTO, error)
+}
+
+type priceListService struct {
+	repo     repository.PriceListRepository
+	itemRepo repository.ItemsRepository
+	permSvc  PermissionService
+}
+
+func NewPriceListService(repo repository.PriceListRepository, itemRepo repository.ItemsRepository, permSvc PermissionService) PriceListService {
+	return &priceListService{
+		repo:     repo,
+		itemRepo: itemRepo,
+		permSvc:  permSvc,
+	}
+}
+
+func (s *priceListService) Create(ctx context.Context, usr *model.User, req dto.CreatePriceListRequest) (*model.PriceList, error) {
+	if !s.permSvc.CanCreateMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot create price list")
+	}
+
+	createdBy := usr.ID
+
+	return s.repo.Create(ctx, &model.PriceList{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		IsActive:    true,
+		CreatedBy:   &createdBy,
+	})
+}
+
+func (s *priceListService) FindAll(ctx context.Context, page, limit int) (*[]model.PriceList, *dto.Pagination, error) {
+	lists, total, err := s.repo.Lists(ctx, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := dto.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: utils.TotalPage(limit, int64(total)),
+		TotalRows:  total,
+	}
+
+	return &lists, &pagination, nil
+}
+
+func (s *priceListService) FindByID(ctx context.Context, usr *model.User, id int) (*model.PriceList, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot access price list")
+	}
+
+	list, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	list.Items, err = s.repo.Items(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *priceListService) Update(ctx context.Context, usr *model.User, id int, req dto.UpdatePriceListRequest) (*model.PriceList, error) {
+	if !s.permSvc.CanUpdateMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot update price list")
+	}
+
+	return s.repo.Update(ctx, &model.PriceList{
+		ID:          id,
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		IsActive:    req.IsActive,
+	})
+}
+
+func (s *priceListService) Delete(ctx context.Context, usr *model.User, id int) error {
+	if !s.permSvc.CanDeleteMasterData(usr.Role) {
+		return errors.New("forbidden: cannot delete price list")
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// harga baru hanya berlaku untuk sale berikutnya, sale lama menyimpan unit price sendiri
+func (s *priceListService) UpsertItem(ctx context.Context, usr *model.User, id int, req dto.UpsertPriceListItemRequest) (*model.PriceListItem, error) {
+	if !s.permSvc.CanUpdateMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot update price list")
+	}
+
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	item, err := s.itemRepo.FindByID(ctx, req.ItemID)
+	if err != nil || !item.IsActive {
+		return nil, errors.New("item not found or inactive")
+	}
+
+	minQty := req.MinQuantity
+	if minQty == 0 {
+		minQty = 1
+	}
+
+	return s.repo.UpsertItem(ctx, &model.PriceListItem{
+		PriceListID: id,
+		ItemID:      req.ItemID,
+		MinQuantity: minQty,
+		Price:       req.Price,
+	})
+}
+
+func (s *priceListService) DeleteItem(ctx context.Context, usr *model.User, id, priceItemID int) error {
+	if !s.permSvc.CanUpdateMasterData(usr.Role) {
+		return errors.New("forbidden: cannot update price list")
+	}
+
+	return s.repo.DeleteItem(ctx, id, priceItemID)
+}
+
+func (s *priceListService) ItemPrices(ctx context.Context, usr *model.User, itemID int) (*dto.ItemPricesResponseDTO, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot access item prices")
+	}
+
+	item, err := s.itemRepo.FindByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := s.repo.ForItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ItemPricesResponseDTO{
+		ItemID:    item.ID,
+		SKU:       item.SKU,
+		Name:      item.Name,
+		BasePrice: item.Price,
+		Prices:    prices,
+	}, nil
+}
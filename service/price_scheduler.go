@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RunPriceScheduler menerapkan perubahan harga terjadwal setiap interval sampai ctx selesai.
+// aman dijalankan di beberapa instance, jadwal yang sedang diproses di-skip (SKIP LOCKED)
+func RunPriceScheduler(ctx context.Context, svc ItemsService, interval time.Duration, log *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		applied, err := svc.ApplyScheduledPrices(ctx)
+		if err != nil {
+			log.Error("failed apply scheduled price changes", zap.Error(err))
+		} else if applied > 0 {
+			log.Info("scheduled price changes applied", zap.Int("count", applied))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/repository"
+	"alfdwirhmn/inventory/utils"
+	"context"
+	"errors"
+	"time"
+)
+
+type PromotionService interface {
+	Create(ctx context.Context, usr *model.User, req dto.CreatePromotionRequest) (*model.Promotion, error)
+	FindAll(ctx context.Context, activeOnly bool, page, limit int) (*[]model.Promotion, *dto.Pagination, error)
+	FindByID(ctx context.Context, usr *model.User, id int) (*model.Promotion, error)
+	Update(ctx context.Context, usr *model.User, id int, req dto.UpdatePromotionRequest) (*model.Promotion, error)
+	Delete(ctx context.Context, usr *model.User, id int) error
+}
+
+type promotionService struct {
+	repo    repository.PromotionRepository
+	permSvc PermissionService
+}
+
+func NewPromotionService(repo repository.PromotionRepository, permSvc PermissionService) PromotionService {
+	return &promotionService{
+		repo:    repo,
+		permSvc: permSvc,
+	}
+}
+
+func (s *promotionService) Create(ctx context.Context, usr *model.User, req dto.CreatePromotionRequest) (*model.Promotion, error) {
+	if !s.permSvc.CanCreateMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot create promotion")
+	}
+
+	promo, err := buildPromotion(req)
+	if err != nil {
+		return nil, err
+	}
+
+	createdBy := usr.ID
+	promo.CreatedBy = &createdBy
+	promo.IsActive = true
+
+	return s.repo.Create(ctx, promo)
+}
+
+func (s *promotionService) FindAll(ctx context.Context, activeOnly bool, page, limit int) (*[]model.Promotion, *dto.Pagination, error) {
+	promos, total, err := s.repo.Lists(ctx, activeOnly, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := dto.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: utils.TotalPage(limit, int64(total)),
+		TotalRows:  total,
+	}
+
+	return &promos, &pagination, nil
+}
+
+func (s *promotionService) FindByID(ctx context.Context, usr *model.User, id int) (*model.Promotion, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot access promotion")
+	}
+
+	return s.repo.FindByID(ctx, id)
+}
+
+// perubahan promo hanya berlaku untuk sale berikutnya
+func (s *promotionService) Update(ctx context.Context, usr *model.User, id int, req dto.UpdatePromotionRequest) (*model.Promotion, error) {
+	if !s.permSvc.CanUpdateMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot update promotion")
+	}
+
+	current, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	promo, err := buildPromotion(req.CreatePromotionRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if promo.UsageLimit != nil && *promo.UsageLimit < current.UsageCount {
+		return nil, errors.New("usage limit cannot be lower than current usage")
+	}
+
+	promo.ID = id
+	promo.IsActive = req.IsActive
+
+	return s.repo.Update(ctx, promo)
+}
+
+func (s *promotionService) Delete(ctx context.Context, usr *model.User, id int) error {
+	if !s.permSvc.CanDeleteMasterData(usr.Role) {
+		return errors.New("forbidden: cannot delete promotion")
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// validasi aturan per tipe promo
+func buildPromotion(req dto.CreatePromotionRequest) (*model.Promotion, error) {
+	if req.ItemID != nil && req.CategoryID != nil {
+		return nil, errors.New("promotion applies to either an item or a category, not both")
+	}
+
+	promo := &model.Promotion{
+		Code:       req.Code,
+		Name:       req.Name,
+		PromoType:  req.PromoType,
+		Value:      req.Value,
+		ItemID:     req.ItemID,
+		CategoryID: req.CategoryID,
+		StartsAt:   time.Now(),
+		EndsAt:     req.EndsAt,
+		UsageLimit: req.UsageLimit,
+	}
+
+	if req.StartsAt != nil {
+		promo.StartsAt = *req.StartsAt
+	}
+	if promo.EndsAt != nil && !promo.EndsAt.After(promo.StartsAt) {
+		return nil, errors.New("ends_at must be after starts_at")
+	}
+
+	switch req.PromoType {
+	case model.PromoPercentage:
+		if req.Value <= 0 || req.Value > model.NewMoney(100) {
+			return nil, errors.New("percentage value must be between 0 and 100")
+		}
+	case model.PromoFixed:
+		if req.Value <= 0 {
+			return nil, errors.New("fixed value must be greater than 0")
+		}
+	case model.PromoBuyXGetY:
+		if req.BuyQuantity == nil || req.GetQuantity == nil {
+			return nil, errors.New("buy_quantity and get_quantity are required for buy_x_get_y")
+		}
+		promo.Value = 0
+		promo.BuyQuantity = req.BuyQuantity
+		promo.GetQuantity = req.GetQuantity
+	}
+
+	return promo, nil
+}
@@ -0,0 +1,714 @@
+package service
+
+import (
+	"alfdwirhmn/inventory/database"
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/repository"
+	"alfdwirhmn/inventory/utils"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type PurchaseService interface {
+	Create(ctx context.Context, usr *model.User, req dto.CreatePurchaseOrderRequest) (*model.PurchaseOrder, error)
+	FindAll(ctx context.Context, usr *model.User, filter dto.PurchaseOrderFilter, page, limit int) ([]model.PurchaseOrder, *dto.Pagination, error)
+	FindByID(ctx context.Context, usr *model.User, id int) (*model.PurchaseOrder, error)
+	Update(ctx context.Context, usr *model.User, id int, req dto.UpdatePurchaseOrderRequest) (*model.PurchaseOrder, error)
+	// buat PO draft dari saran reorder, satu PO per supplier utama
+	CreateFromReorder(ctx context.Context, usr *model.User, req dto.CreateReorderPurchaseRequest) (*dto.ReorderPurchaseResponseDTO, error)
+
+	// draft -> approved -> (partially_received) -> closed
+	Approve(ctx context.Context, usr *model.User, id int) (*model.PurchaseOrder, error)
+	Receive(ctx context.Context, usr *model.User, id int, req dto.ReceivePurchaseOrderRequest) (*model.GoodsReceipt, error)
+	// tutup PO yang tidak akan dikirim penuh oleh supplier
+	Close(ctx context.Context, usr *model.User, id int) (*model.PurchaseOrder, error)
+	Cancel(ctx context.Context, usr *model.User, id int) (*model.PurchaseOrder, error)
+}
+
+type purchaseService struct {
+	repo          repository.PurchaseOrderRepository
+	receiptRepo   repository.GoodsReceiptRepository
+	supplierRepo  repository.SupplierRepository
+	itemRepo      repository.ItemsRepository
+	warehouseRepo repository.WarehouseRepository
+	rackRepo      repository.RacksRepository
+	reportRepo    repository.ReportRepository
+	poNo          *numberSequence
+	grnNo         *numberSequence
+	costing       string
+	permSvc       PermissionService
+	txMgr         database.TxManager
+	log           *zap.Logger
+}
+
+func NewPurchaseService(
+	repo repository.PurchaseOrderRepository,
+	receiptRepo repository.GoodsReceiptRepository,
+	supplierRepo repository.SupplierRepository,
+	itemRepo repository.ItemsRepository,
+	warehouseRepo repository.WarehouseRepository,
+	rackRepo repository.RacksRepository,
+	reportRepo repository.ReportRepository,
+	poPattern, grnPattern string,
+	costing string,
+	permSvc PermissionService,
+	tx database.TxManager,
+	log *zap.Logger,
+) PurchaseService {
+	return &purchaseService{
+		repo:          repo,
+		receiptRepo:   receiptRepo,
+		supplierRepo:  supplierRepo,
+		itemRepo:      itemRepo,
+		warehouseRepo: warehouseRepo,
+		rackRepo:      rackRepo,
+		reportRepo:    reportRepo,
+		poNo:          newNumberSequence(poPattern, log),
+		grnNo:         newNumberSequence(grnPattern, log),
+		costing:       costing,
+		permSvc:       permSvc,
+		txMgr:         tx,
+		log:           log,
+	}
+}
+
+func (s *purchaseService) Create(ctx context.Context, usr *model.User, req dto.CreatePurchaseOrderRequest) (*model.PurchaseOrder, error) {
+	if !s.permSvc.CanCreatePurchase(usr.Role) {
+		return nil, errors.New("forbidden: cannot create purchase order")
+	}
+
+	warehouse, err := s.warehouseRepo.DetailById(req.WarehouseID)
+	if err != nil || warehouse == nil || !warehouse.IsActive {
+		return nil, errors.New("warehouse not found or inactive")
+	}
+
+	lines, total, err := s.buildLines(ctx, req.SupplierID, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	orderDate := time.Now()
+	if req.OrderDate != nil {
+		orderDate = *req.OrderDate
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	po, err := s.createDraft(ctx, tx, warehouse.Code, &model.PurchaseOrder{
+		SupplierID:   req.SupplierID,
+		WarehouseID:  req.WarehouseID,
+		OrderDate:    orderDate,
+		ExpectedDate: req.ExpectedDate,
+		TotalAmount:  total,
+		Notes:        req.Notes,
+		CreatedBy:    usr.ID,
+	}, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return po, nil
+}
+
+// createDraft beri nomor PO lalu simpan header & baris di dalam tx
+func (s *purchaseService) createDraft(ctx context.Context, tx repository.DBTX, warehouseCode string, order *model.PurchaseOrder, lines []model.PurchaseOrderItem) (*model.PurchaseOrder, error) {
+	repo := repository.NewPurchaseOrderRepository(tx, s.log)
+
+	number, err := s.poNo.Next(ctx, tx, warehouseCode, order.OrderDate)
+	if err != nil {
+		return nil, err
+	}
+	order.PONumber = number
+
+	po, err := repo.Create(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range lines {
+		lines[i].PurchaseOrderID = po.ID
+		if err := repo.CreateItem(ctx, &lines[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	po.Items, err = repo.FindItems(ctx, po.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return po, nil
+}
+
+func (s *purchaseService) CreateFromReorder(ctx context.Context, usr *model.User, req dto.CreateReorderPurchaseRequest) (*dto.ReorderPurchaseResponseDTO, error) {
+	if !s.permSvc.CanApprovePurchase(usr.Role) {
+		return nil, errors.New("forbidden: cannot create purchase order from reorder suggestions")
+	}
+
+	warehouse, err := s.warehouseRepo.DetailById(req.WarehouseID)
+	if err != nil || warehouse == nil || !warehouse.IsActive {
+		return nil, errors.New("warehouse not found or inactive")
+	}
+
+	filter := dto.ReorderFilter{
+		WarehouseID: req.WarehouseID,
+		CategoryID:  req.CategoryID,
+		SupplierID:  req.SupplierID,
+		Days:        req.Days,
+	}
+	if filter.Days <= 0 {
+		filter.Days = defaultReorderDays
+	}
+	from, to := reorderWindow(filter.Days, time.Now())
+
+	candidates, err := s.reportRepo.ReorderCandidates(ctx, filter, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// kelompokkan per supplier utama, urutan supplier ikut urutan item pertama
+	resp := &dto.ReorderPurchaseResponseDTO{
+		PurchaseOrders: []model.PurchaseOrder{},
+		Skipped:        []model.ReorderSuggestion{},
+	}
+	var supplierIDs []int
+	groups := map[int][]model.ReorderSuggestion{}
+	for _, sg := range suggestReorder(candidates, filter.Days) {
+		if len(req.ItemIDs) > 0 && !slices.Contains(req.ItemIDs, sg.ItemID) {
+			continue
+		}
+		if sg.SupplierID == nil {
+			resp.Skipped = append(resp.Skipped, sg)
+			continue
+		}
+
+		if _, ok := groups[*sg.SupplierID]; !ok {
+			supplierIDs = append(supplierIDs, *sg.SupplierID)
+		}
+		groups[*sg.SupplierID] = append(groups[*sg.SupplierID], sg)
+	}
+
+	if len(supplierIDs) == 0 {
+		if len(resp.Skipped) > 0 {
+			return nil, fmt.Errorf("%d suggested items have no preferred supplier", len(resp.Skipped))
+		}
+		return nil, errors.New("no items need to be reordered")
+	}
+
+	notes := fmt.Sprintf("reorder suggestion (%d days sales)", filter.Days)
+	if req.Notes != nil {
+		notes = *req.Notes
+	}
+	orderDate := time.Now()
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, supplierID := range supplierIDs {
+		var total model.Money
+		var leadTime int
+		lines := make([]model.PurchaseOrderItem, 0, len(groups[supplierID]))
+		for _, sg := range groups[supplierID] {
+			lines = append(lines, model.PurchaseOrderItem{
+				ItemID:   sg.ItemID,
+				Quantity: sg.SuggestedQuantity,
+				UnitCost: sg.UnitCost,
+				Subtotal: sg.EstimatedCost,
+			})
+			total += sg.EstimatedCost
+			leadTime = max(leadTime, sg.LeadTimeDays)
+		}
+
+		// perkiraan datang = lead time terlama di PO
+		var expected *time.Time
+		if leadTime > 0 {
+			at := orderDate.AddDate(0, 0, leadTime)
+			expected = &at
+		}
+
+		po, err := s.createDraft(ctx, tx, warehouse.Code, &model.PurchaseOrder{
+			SupplierID:   supplierID,
+			WarehouseID:  req.WarehouseID,
+			OrderDate:    orderDate,
+			ExpectedDate: expected,
+			TotalAmount:  total,
+			Notes:        &notes,
+			CreatedBy:    usr.ID,
+		}, lines)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.PurchaseOrders = append(resp.PurchaseOrders, *po)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// buildLines validasi supplier & item lalu hitung harga per baris.
+// harga kosong diisi harga beli terakhir dari supplier, fallback cost master item
+func (s *purchaseService) buildLines(ctx context.Context, supplierID int, items []dto.PurchaseOrderItemRequest) ([]model.PurchaseOrderItem, model.Money, error) {
+	supplier, err := s.supplierRepo.FindByID(ctx, supplierID)
+	if err != nil || !supplier.IsActive {
+		return nil, 0, errors.New("supplier not found or inactive")
+	}
+
+	links, err := s.supplierRepo.ItemsBySupplier(ctx, supplierID)
+	if err != nil {
+		return nil, 0, err
+	}
+	lastPrice := map[int]model.Money{}
+	for _, link := range links {
+		if link.LastPurchasePrice != nil {
+			lastPrice[link.ItemID] = *link.LastPurchasePrice
+		}
+	}
+
+	var total model.Money
+	seen := map[int]bool{}
+	lines := make([]model.PurchaseOrderItem, 0, len(items))
+	for _, it := range items {
+		if seen[it.ItemID] {
+			return nil, 0, fmt.Errorf("item %d listed more than once", it.ItemID)
+		}
+		seen[it.ItemID] = true
+
+		item, err := s.itemRepo.FindByID(ctx, it.ItemID)
+		if err != nil || !item.IsActive {
+			return nil, 0, fmt.Errorf("item %d not found or inactive", it.ItemID)
+		}
+
+		unitCost := item.Cost
+		if price, ok := lastPrice[it.ItemID]; ok {
+			unitCost = price
+		}
+		if it.UnitCost != nil {
+			unitCost = *it.UnitCost
+		}
+		if unitCost < 0 {
+			return nil, 0, fmt.Errorf("item %s: unit cost cannot be negative", item.SKU)
+		}
+
+		line := model.PurchaseOrderItem{
+			ItemID:   it.ItemID,
+			Quantity: it.Quantity,
+			UnitCost: unitCost,
+			Subtotal: unitCost.Mul(it.Quantity),
+		}
+		total += line.Subtotal
+
+		lines = append(lines, line)
+	}
+
+	return lines, total, nil
+}
+
+func (s *purchaseService) FindAll(ctx context.Context, usr *model.User, filter dto.PurchaseOrderFilter, page, limit int) ([]model.PurchaseOrder, *dto.Pagination, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, nil, errors.New("forbidden: cannot access purchase orders")
+	}
+
+	orders, total, err := s.repo.Lists(ctx, filter, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: utils.TotalPage(limit, int64(total)),
+		TotalRows:  total,
+	}
+
+	return orders, pagination, nil
+}
+
+func (s *purchaseService) FindByID(ctx context.Context, usr *model.User, id int) (*model.PurchaseOrder, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot access purchase orders")
+	}
+
+	po, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	po.Items, err = s.repo.FindItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	po.Receipts, err = s.receiptRepo.ListsByPurchaseOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return po, nil
+}
+
+func (s *purchaseService) Update(ctx context.Context, usr *model.User, id int, req dto.UpdatePurchaseOrderRequest) (*model.PurchaseOrder, error) {
+	if !s.permSvc.CanCreatePurchase(usr.Role) {
+		return nil, errors.New("forbidden: cannot update purchase order")
+	}
+
+	lines, total, err := s.buildLines(ctx, req.SupplierID, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := repository.NewPurchaseOrderRepository(tx, s.log)
+
+	po, err := repo.FindByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if po.Status != model.PurchaseDraft {
+		return nil, fmt.Errorf("purchase order is %s, only draft purchase order can be updated", po.Status)
+	}
+
+	po.SupplierID = req.SupplierID
+	po.ExpectedDate = req.ExpectedDate
+	po.Notes = req.Notes
+	po.TotalAmount = total
+	if err := repo.Update(ctx, po); err != nil {
+		return nil, err
+	}
+
+	// baris draft diganti semua
+	if err := repo.DeleteItems(ctx, id); err != nil {
+		return nil, err
+	}
+	for i := range lines {
+		lines[i].PurchaseOrderID = id
+		if err := repo.CreateItem(ctx, &lines[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(ctx, usr, id)
+}
+
+func (s *purchaseService) Approve(ctx context.Context, usr *model.User, id int) (*model.PurchaseOrder, error) {
+	if !s.permSvc.CanApprovePurchase(usr.Role) {
+		return nil, errors.New("forbidden: cannot approve purchase order")
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := repository.NewPurchaseOrderRepository(tx, s.log)
+
+	po, err := repo.FindByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if po.Status != model.PurchaseDraft {
+		return nil, fmt.Errorf("purchase order is %s, only draft purchase order can be approved", po.Status)
+	}
+
+	if err := repo.Approve(ctx, id, usr.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(ctx, usr, id)
+}
+
+func (s *purchaseService) Receive(ctx context.Context, usr *model.User, id int, req dto.ReceivePurchaseOrderRequest) (*model.GoodsReceipt, error) {
+	if !s.permSvc.CanUpdateStock(usr.Role) {
+		return nil, errors.New("forbidden: cannot receive purchase order")
+	}
+
+	rack, err := s.rackRepo.DetailById(req.RackID)
+	if err != nil || rack == nil || !rack.IsActive {
+		return nil, fmt.Errorf("rack %d not found or inactive", req.RackID)
+	}
+
+	receivedDate := time.Now()
+	if req.ReceivedDate != nil {
+		receivedDate = *req.ReceivedDate
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := repository.NewPurchaseOrderRepository(tx, s.log)
+	receiptRepo := repository.NewGoodsReceiptRepository(tx, s.log)
+	supplierRepo := repository.NewSupplierRepository(tx, s.log)
+	itemRepo := repository.NewItemsRepository(tx, s.log)
+	movementRepo := repository.NewStockMovementRepository(tx, s.log)
+	historyRepo := repository.NewPriceHistoryRepository(tx, s.log)
+	ledger := newStockLedger(tx, s.log, s.costing)
+
+	po, err := repo.FindByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if po.Status != model.PurchaseApproved && po.Status != model.PurchasePartiallyReceived {
+		return nil, fmt.Errorf("purchase order is %s, only approved purchase order can be received", po.Status)
+	}
+
+	if rack.WarehouseID != po.WarehouseID {
+		return nil, fmt.Errorf("rack %s is not in the purchase order warehouse", rack.Code)
+	}
+
+	lines, err := repo.FindItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	inOrder := map[int]bool{}
+	for _, line := range lines {
+		inOrder[line.ItemID] = true
+	}
+
+	// qty & harga yang diterima per item, default semua sisa dengan harga PO
+	receive := map[int]int{}
+	unitCost := map[int]model.Money{}
+	if len(req.Items) == 0 {
+		for _, line := range lines {
+			receive[line.ItemID] = line.Quantity - line.ReceivedQuantity
+		}
+	}
+	for _, it := range req.Items {
+		if !inOrder[it.ItemID] {
+			return nil, fmt.Errorf("item %d is not part of this purchase order", it.ItemID)
+		}
+		receive[it.ItemID] += it.Quantity
+		if it.UnitCost != nil {
+			if *it.UnitCost < 0 {
+				return nil, fmt.Errorf("item %d: unit cost cannot be negative", it.ItemID)
+			}
+			unitCost[it.ItemID] = *it.UnitCost
+		}
+	}
+
+	var receivedIDs []int
+	var total model.Money
+	complete := true
+	for i, line := range lines {
+		qty := receive[line.ItemID]
+
+		remaining := line.Quantity - line.ReceivedQuantity
+		if qty > remaining {
+			return nil, fmt.Errorf("item %s: only %d left to receive", line.SKU, remaining)
+		}
+		if qty < remaining {
+			complete = false
+		}
+		if qty == 0 {
+			continue
+		}
+
+		if _, ok := unitCost[line.ItemID]; !ok {
+			unitCost[line.ItemID] = line.UnitCost
+		}
+		lines[i].Subtotal = unitCost[line.ItemID].Mul(qty)
+		total += lines[i].Subtotal
+		receivedIDs = append(receivedIDs, line.ItemID)
+	}
+
+	if len(receivedIDs) == 0 {
+		return nil, errors.New("nothing to receive")
+	}
+
+	items, err := ledger.Lock(ctx, receivedIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	number, err := s.grnNo.Next(ctx, tx, po.WarehouseCode, receivedDate)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := receiptRepo.Create(ctx, &model.GoodsReceipt{
+		GRNNumber:       number,
+		PurchaseOrderID: id,
+		RackID:          req.RackID,
+		ReceivedDate:    receivedDate,
+		TotalAmount:     total,
+		Notes:           req.Notes,
+		ReceivedBy:      usr.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notes := "goods receipt " + receipt.GRNNumber
+	for _, line := range lines {
+		qty := receive[line.ItemID]
+		if qty == 0 {
+			continue
+		}
+
+		// stok masuk ke rak dengan nilai sebesar harga beli
+		mv := &model.StockMovement{
+			ItemID:       line.ItemID,
+			RackID:       req.RackID,
+			MovementType: model.MovementReceipt,
+			Quantity:     qty,
+			TotalCost:    line.Subtotal,
+			ReferenceID:  &receipt.ID,
+			Notes:        &notes,
+			CreatedBy:    &usr.ID,
+		}
+		if err := ledger.Post(ctx, mv); err != nil {
+			return nil, fmt.Errorf("item %s: %w", line.SKU, err)
+		}
+
+		if err := repo.AddReceived(ctx, line.ID, qty); err != nil {
+			return nil, err
+		}
+
+		receiptLine := model.GoodsReceiptItem{
+			GoodsReceiptID:      receipt.ID,
+			PurchaseOrderItemID: line.ID,
+			ItemID:              line.ItemID,
+			SKU:                 line.SKU,
+			Name:                line.Name,
+			Quantity:            qty,
+			UnitCost:            unitCost[line.ItemID],
+			Subtotal:            line.Subtotal,
+		}
+		if err := receiptRepo.CreateItem(ctx, &receiptLine); err != nil {
+			return nil, err
+		}
+		receipt.Items = append(receipt.Items, receiptLine)
+
+		if err := supplierRepo.UpdateLastPurchase(ctx, line.ItemID, po.SupplierID, unitCost[line.ItemID], receivedDate); err != nil {
+			return nil, err
+		}
+
+		// cost master item ikut rata-rata tertimbang persediaan setelah barang masuk
+		value, err := movementRepo.ValueOnHand(ctx, line.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		if mv.StockAfter <= 0 || value <= 0 {
+			continue
+		}
+
+		before := items[line.ItemID]
+		cost := value.MulRatio(1, int64(mv.StockAfter))
+		if cost == before.Cost || !before.IsActive {
+			continue
+		}
+
+		updated, err := itemRepo.Update(ctx, line.ItemID, dto.UpdateItemRequest{Cost: &cost})
+		if err != nil {
+			return nil, err
+		}
+
+		change := priceChange(before, updated)
+		change.Reason = &notes
+		change.CreatedBy = &usr.ID
+		if _, err := historyRepo.Create(ctx, change); err != nil {
+			return nil, err
+		}
+	}
+
+	status := model.PurchasePartiallyReceived
+	if complete {
+		status = model.PurchaseClosed
+	}
+	if err := repo.UpdateStatus(ctx, id, status); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+func (s *purchaseService) Close(ctx context.Context, usr *model.User, id int) (*model.PurchaseOrder, error) {
+	if !s.permSvc.CanApprovePurchase(usr.Role) {
+		return nil, errors.New("forbidden: cannot close purchase order")
+	}
+
+	return s.transition(ctx, usr, id, model.PurchaseClosed, model.PurchasePartiallyReceived)
+}
+
+func (s *purchaseService) Cancel(ctx context.Context, usr *model.User, id int) (*model.PurchaseOrder, error) {
+	if !s.permSvc.CanApprovePurchase(usr.Role) {
+		return nil, errors.New("forbidden: cannot cancel purchase order")
+	}
+
+	// belum ada barang yang diterima, PO sebagian diterima ditutup lewat Close
+	return s.transition(ctx, usr, id, model.PurchaseCancelled, model.PurchaseDraft, model.PurchaseApproved)
+}
+
+// transition ubah status PO kalau status sekarang termasuk from
+func (s *purchaseService) transition(ctx context.Context, usr *model.User, id int, to string, from ...string) (*model.PurchaseOrder, error) {
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := repository.NewPurchaseOrderRepository(tx, s.log)
+
+	po, err := repo.FindByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(from, po.Status) {
+		return nil, fmt.Errorf("purchase order is %s, cannot change to %s", po.Status, to)
+	}
+
+	if err := repo.UpdateStatus(ctx, id, to); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(ctx, usr, id)
+}
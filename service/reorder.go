@@ -0,0 +1,48 @@
+package service
+
+import (
+	"alfdwirhmn/inventory/model"
+	"math"
+	"time"
+)
+
+// periode default rata-rata penjualan harian untuk saran reorder
+const defaultReorderDays = 30
+
+// reorderWindow periode penjualan [from, to) yang berakhir di akhir hari ini
+func reorderWindow(days int, now time.Time) (time.Time, time.Time) {
+	if days <= 0 {
+		days = defaultReorderDays
+	}
+
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	return to.AddDate(0, 0, -days), to
+}
+
+// suggestReorder isi reorder point & saran qty, hanya item yang perlu dipesan yang dikembalikan.
+// reorder point = rata-rata pemakaian harian x lead time + safety stock (minimum_stock),
+// saran qty = reorder point - (stok + on order), minimal sebesar MOQ supplier
+func suggestReorder(candidates []model.ReorderSuggestion, days int) []model.ReorderSuggestion {
+	if days <= 0 {
+		days = defaultReorderDays
+	}
+
+	suggestions := []model.ReorderSuggestion{}
+	for _, c := range candidates {
+		usage := max(c.SoldQuantity, 0)
+		c.DailyUsage = math.Round(float64(usage)/float64(days)*100) / 100
+		c.ReorderPoint = int(math.Ceil(float64(usage)*float64(c.LeadTimeDays)/float64(days))) + c.SafetyStock
+
+		position := c.Stock + c.OnOrder
+		if position >= c.ReorderPoint {
+			continue
+		}
+
+		c.SuggestedQuantity = max(c.ReorderPoint-position, c.MinOrderQuantity)
+		c.EstimatedCost = c.UnitCost.Mul(c.SuggestedQuantity)
+
+		suggestions = append(suggestions, c)
+	}
+
+	return suggestions
+}
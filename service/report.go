@@ -0,0 +1,292 @@
+package service
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/repository"
+	"alfdwirhmn/inventory/utils"
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// ErrReportForbidden dikembalikan kalau role user tidak boleh melihat laporan
+var ErrReportForbidden = errors.New("forbidden: cannot access reports")
+
+type ReportService interface {
+	// nilai persediaan per gudang pada akhir tanggal asOf
+	InventoryValuation(ctx context.Context, usr *model.User, warehouseID *int, asOf time.Time) (*dto.InventoryValuationResponseDTO, error)
+	// saran pembelian ulang dari rata-rata penjualan harian
+	ReorderSuggestions(ctx context.Context, usr *model.User, filter dto.ReorderFilter) (*dto.ReorderReportResponseDTO, error)
+	// penjualan per periode, metode bayar, kasir, kategori & item
+	Sales(ctx context.Context, usr *model.User, filter dto.SalesReportFilter) (*dto.SalesReportResponseDTO, error)
+	// laba kotor per item, kategori atau periode
+	GrossMargin(ctx context.Context, usr *model.User, filter dto.MarginReportFilter) (*dto.MarginReportResponseDTO, error)
+	// umur stok per gudang & rak (0-30, 31-90, 91-180, >180 hari)
+	InventoryAging(ctx context.Context, usr *model.User, filter dto.InventoryAgingFilter) (*dto.InventoryAgingResponseDTO, error)
+	// stok yang tidak bergerak / terjual selama >= days hari
+	DeadStock(ctx context.Context, usr *model.User, filter dto.InventoryAgingFilter, days int) (*dto.DeadStockResponseDTO, error)
+}
+
+type reportService struct {
+	movementRepo repository.StockMovementRepository
+	reportRepo   repository.ReportRepository
+	costing      string
+	permSvc      PermissionService
+}
+
+func NewReportService(movementRepo repository.StockMovementRepository, reportRepo repository.ReportRepository, costing string, permSvc PermissionService) ReportService {
+	return &reportService{
+		movementRepo: movementRepo,
+		reportRepo:   reportRepo,
+		costing:      costing,
+		permSvc:      permSvc,
+	}
+}
+
+func (s *reportService) InventoryValuation(ctx context.Context, usr *model.User, warehouseID *int, asOf time.Time) (*dto.InventoryValuationResponseDTO, error) {
+	if !s.permSvc.CanAccessReports(usr.Role) {
+		return nil, ErrReportForbidden
+	}
+
+	// semua mutasi sampai akhir hari asOf
+	day := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, asOf.Location())
+	rows, err := s.movementRepo.Valuation(ctx, warehouseID, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.InventoryValuationResponseDTO{
+		AsOf:          day.Format(utils.DateLayout),
+		CostingMethod: s.costing,
+		Warehouses:    []dto.WarehouseValuationResponseDTO{},
+	}
+
+	// rows sudah urut per gudang
+	for _, row := range rows {
+		n := len(resp.Warehouses)
+		if n == 0 || resp.Warehouses[n-1].WarehouseID != row.WarehouseID {
+			resp.Warehouses = append(resp.Warehouses, dto.WarehouseValuationResponseDTO{
+				WarehouseID:   row.WarehouseID,
+				WarehouseCode: row.WarehouseCode,
+				WarehouseName: row.WarehouseName,
+			})
+			n++
+		}
+
+		wh := &resp.Warehouses[n-1]
+		wh.Quantity += row.Quantity
+		wh.Value += row.Value
+		wh.Items = append(wh.Items, row)
+
+		resp.TotalQuantity += row.Quantity
+		resp.TotalValue += row.Value
+	}
+
+	return resp, nil
+}
+
+func (s *reportService) ReorderSuggestions(ctx context.Context, usr *model.User, filter dto.ReorderFilter) (*dto.ReorderReportResponseDTO, error) {
+	if !s.permSvc.CanAccessReports(usr.Role) {
+		return nil, ErrReportForbidden
+	}
+
+	if filter.Days <= 0 {
+		filter.Days = defaultReorderDays
+	}
+	from, to := reorderWindow(filter.Days, time.Now())
+
+	candidates, err := s.reportRepo.ReorderCandidates(ctx, filter, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ReorderReportResponseDTO{
+		From:  from.Format(utils.DateLayout),
+		To:    to.AddDate(0, 0, -1).Format(utils.DateLayout),
+		Days:  filter.Days,
+		Items: suggestReorder(candidates, filter.Days),
+	}
+
+	resp.TotalItems = len(resp.Items)
+	for _, it := range resp.Items {
+		resp.EstimatedCost += it.EstimatedCost
+	}
+
+	return resp, nil
+}
+
+func (s *reportService) Sales(ctx context.Context, usr *model.User, filter dto.SalesReportFilter) (*dto.SalesReportResponseDTO, error) {
+	if !s.permSvc.CanAccessReports(usr.Role) {
+		return nil, ErrReportForbidden
+	}
+
+	if filter.Period == "" {
+		filter.Period = dto.SalesPeriodDay
+	}
+
+	summary, err := s.reportRepo.SalesSummary(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.SalesReportResponseDTO{
+		From:    filter.From.Format(utils.DateLayout),
+		To:      filter.To.Format(utils.DateLayout),
+		Period:  filter.Period,
+		Summary: *summary,
+	}
+
+	if resp.Periods, err = s.reportRepo.SalesByPeriod(ctx, filter); err != nil {
+		return nil, err
+	}
+	if resp.PaymentMethods, err = s.reportRepo.SalesByPaymentMethod(ctx, filter); err != nil {
+		return nil, err
+	}
+	if resp.Cashiers, err = s.reportRepo.SalesByCashier(ctx, filter); err != nil {
+		return nil, err
+	}
+	if resp.Categories, err = s.reportRepo.SalesByCategory(ctx, filter); err != nil {
+		return nil, err
+	}
+	if resp.Items, err = s.reportRepo.SalesByItem(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *reportService) GrossMargin(ctx context.Context, usr *model.User, filter dto.MarginReportFilter) (*dto.MarginReportResponseDTO, error) {
+	if !s.permSvc.CanAccessReports(usr.Role) {
+		return nil, ErrReportForbidden
+	}
+
+	if filter.GroupBy == "" {
+		filter.GroupBy = dto.MarginByItem
+	}
+	if filter.Period == "" {
+		filter.Period = dto.SalesPeriodMonth
+	}
+	if filter.Sort == "" {
+		filter.Sort = "margin"
+	}
+
+	rows, err := s.reportRepo.GrossMargin(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(rows, func(a, b model.GrossMargin) int {
+		c := compareMargin(a, b, filter.Sort)
+		if filter.Desc {
+			return -c
+		}
+		return c
+	})
+
+	resp := &dto.MarginReportResponseDTO{
+		From:    filter.From.Format(utils.DateLayout),
+		To:      filter.To.Format(utils.DateLayout),
+		GroupBy: filter.GroupBy,
+		Sort:    filter.Sort,
+		Order:   "asc",
+		Total:   model.GrossMargin{Key: "total"},
+		Rows:    rows,
+	}
+	if filter.GroupBy == dto.MarginByPeriod {
+		resp.Period = filter.Period
+	}
+	if filter.Desc {
+		resp.Order = "desc"
+	}
+
+	for _, row := range rows {
+		resp.Total.Quantity += row.Quantity
+		resp.Total.Revenue += row.Revenue
+		resp.Total.Cost += row.Cost
+	}
+	resp.Total.SetMargin()
+
+	return resp, nil
+}
+
+func compareMargin(a, b model.GrossMargin, sort string) int {
+	switch sort {
+	case "revenue":
+		return cmp.Compare(a.Revenue, b.Revenue)
+	case "cost":
+		return cmp.Compare(a.Cost, b.Cost)
+	case "margin_percent":
+		return cmp.Compare(a.MarginPercent, b.MarginPercent)
+	case "quantity":
+		return cmp.Compare(a.Quantity, b.Quantity)
+	case "key":
+		return cmp.Compare(a.Key, b.Key)
+	default:
+		return cmp.Compare(a.Margin, b.Margin)
+	}
+}
+
+func (s *reportService) InventoryAging(ctx context.Context, usr *model.User, filter dto.InventoryAgingFilter) (*dto.InventoryAgingResponseDTO, error) {
+	if !s.permSvc.CanAccessReports(usr.Role) {
+		return nil, ErrReportForbidden
+	}
+
+	rows, err := s.reportRepo.InventoryAging(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.InventoryAgingResponseDTO{
+		AsOf:  time.Now().Format(utils.DateLayout),
+		Items: rows,
+	}
+
+	for _, row := range rows {
+		resp.Total.Quantity += row.Quantity
+		resp.Total.Value += row.Value
+		resp.Total.Days0To30 += row.Days0To30
+		resp.Total.Days31To90 += row.Days31To90
+		resp.Total.Days91To180 += row.Days91To180
+		resp.Total.DaysOver180 += row.DaysOver180
+	}
+
+	return resp, nil
+}
+
+func (s *reportService) DeadStock(ctx context.Context, usr *model.User, filter dto.InventoryAgingFilter, days int) (*dto.DeadStockResponseDTO, error) {
+	if !s.permSvc.CanAccessReports(usr.Role) {
+		return nil, ErrReportForbidden
+	}
+
+	rows, err := s.reportRepo.InventoryAging(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.DeadStockResponseDTO{
+		AsOf:  time.Now().Format(utils.DateLayout),
+		Days:  days,
+		Items: []model.InventoryAging{},
+	}
+
+	for _, row := range rows {
+		if row.IdleDays < days {
+			continue
+		}
+
+		resp.Items = append(resp.Items, row)
+		resp.TotalQuantity += row.Quantity
+		resp.TotalValue += row.Value
+	}
+	resp.TotalItems = len(resp.Items)
+
+	// paling lama diam di atas, kandidat utama promo clearance
+	slices.SortStableFunc(resp.Items, func(a, b model.InventoryAging) int {
+		return cmp.Compare(b.IdleDays, a.IdleDays)
+	})
+
+	return resp, nil
+}
@@ -8,6 +8,9 @@ import (
 	"alfdwirhmn/inventory/utils"
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -16,6 +19,7 @@ type SaleService interface {
 	Create(ctx context.Context, usr *model.User, req dto.CreateSaleRequest) (*model.Sale, error)
 	FindAll(ctx context.Context, page, limit int) (*[]model.Sale, *dto.Pagination, error)
 	Detail(ctx context.Context, usr *model.User, id int) (*model.Sale, error)
+	Items(ctx context.Context, usr *model.User, id int) ([]model.SaleItem, error)
 	Update(ctx context.Context, usr *model.User, sale *model.Sale) error
 	Delete(ctx context.Context, usr *model.User, id int) error
 
@@ -26,23 +30,45 @@ type SaleService interface {
 		req dto.UpdateSalePaymentRequest,
 		user *model.User,
 	) (*model.Sale, error)
+
+	// cancel sale, stok dikembalikan
+	Cancel(ctx context.Context, usr *model.User, saleID int, req dto.CancelSaleRequest) (*model.Sale, error)
+
+	// retur & refund
+	CreateReturn(ctx context.Context, usr *model.User, saleID int, req dto.CreateSaleReturnRequest) (*model.SaleReturn, error)
+	Returns(ctx context.Context, usr *model.User, saleID int) ([]model.SaleReturn, error)
+
+	// pembayaran sebagian / split payment
+	AddPayment(ctx context.Context, usr *model.User, saleID int, req dto.CreateSalePaymentRequest) (*model.Sale, error)
+	Payments(ctx context.Context, usr *model.User, saleID int) (*model.Sale, error)
+	VoidPayment(ctx context.Context, usr *model.User, saleID, paymentID int, req dto.VoidSalePaymentRequest) (*model.Sale, error)
 }
 
 type saleService struct {
-	repo     repository.SaleRepository
-	itemRepo repository.ItemsRepository
-	txMgr    database.TxManager // transaction db
-	permSvc  PermissionService
-	log      *zap.Logger
+	repo          repository.SaleRepository
+	itemRepo      repository.ItemsRepository
+	returnRepo    repository.SaleReturnRepository
+	paymentRepo   repository.SalePaymentRepository
+	warehouseRepo repository.WarehouseRepository
+	invoiceNo     *numberSequence
+	costing       string
+	txMgr         database.TxManager // transaction db
+	permSvc       PermissionService
+	log           *zap.Logger
 }
 
-func NewSaleService(repo repository.SaleRepository, itemRepo repository.ItemsRepository, permSvc PermissionService, tx database.TxManager, log *zap.Logger) SaleService {
+func NewSaleService(repo repository.SaleRepository, itemRepo repository.ItemsRepository, returnRepo repository.SaleReturnRepository, paymentRepo repository.SalePaymentRepository, warehouseRepo repository.WarehouseRepository, invoicePattern, costing string, permSvc PermissionService, tx database.TxManager, log *zap.Logger) SaleService {
 	return &saleService{
-		repo:     repo,
-		itemRepo: itemRepo,
-		permSvc:  permSvc,
-		txMgr:    tx,
-		log:      log,
+		repo:          repo,
+		itemRepo:      itemRepo,
+		returnRepo:    returnRepo,
+		paymentRepo:   paymentRepo,
+		warehouseRepo: warehouseRepo,
+		invoiceNo:     newNumberSequence(invoicePattern, log),
+		costing:       costing,
+		permSvc:       permSvc,
+		txMgr:         tx,
+		log:           log,
 	}
 }
 
@@ -51,6 +77,11 @@ func (s *saleService) Create(ctx context.Context, usr *model.User, req dto.Creat
 		return nil, errors.New("forbidden")
 	}
 
+	// nomor invoice otomatis butuh kode gudang kalau pattern-nya memakai {WAREHOUSE}
+	if req.InvoiceNumber == "" && req.WarehouseID == nil && s.invoiceNo.needsWarehouse() {
+		return nil, ErrWarehouseRequired
+	}
+
 	// start transtaction
 	tx, err := s.txMgr.Begin(ctx)
 	if err != nil {
@@ -62,37 +93,207 @@ func (s *saleService) Create(ctx context.Context, usr *model.User, req dto.Creat
 
 	// new repo with transaction context
 	saleRepo := repository.NewSaleRepository(tx, s.log)
-	itemRepo := repository.NewItemsRepository(tx, s.log)
+	ledger := newStockLedger(tx, s.log, s.costing)
+
+	// lock semua item di awal (urut id), sale paralel untuk item yang sama antri di sini
+	itemIDs := make([]int, 0, len(req.Items))
+	for _, it := range req.Items {
+		itemIDs = append(itemIDs, it.ItemID)
+	}
+
+	items, err := ledger.Lock(ctx, itemIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	// tarif pajak per item (item override kategori)
+	rates, err := repository.NewTaxRateRepository(tx, s.log).ForItems(ctx, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	saleDate := time.Now()
+	if req.SaleDate != nil {
+		saleDate = *req.SaleDate
+	}
+
+	customerName, customerPhone, customerEmail := req.CustomerName, req.CustomerPhone, req.CustomerEmail
+	priceListID := req.PriceListID
+	if req.CustomerID != nil {
+		customer, err := repository.NewCustomerRepository(tx, s.log).FindByID(ctx, *req.CustomerID)
+		if err != nil || !customer.IsActive {
+			return nil, errors.New("customer not found or inactive")
+		}
+
+		// snapshot data customer saat transaksi
+		if customerName == "" {
+			customerName = customer.Name
+		}
+		if customerPhone == "" && customer.Phone != nil {
+			customerPhone = *customer.Phone
+		}
+		if customerEmail == "" && customer.Email != nil {
+			customerEmail = *customer.Email
+		}
+		if priceListID == nil {
+			priceListID = customer.PriceListID
+		}
+	}
 
-	var total float64
+	// daftar harga harus masih aktif. yang dipilih di request ditolak,
+	// bawaan customer yang sudah nonaktif diabaikan (pakai harga dasar)
+	priceListRepo := repository.NewPriceListRepository(tx, s.log)
+	if priceListID != nil {
+		list, err := priceListRepo.FindByID(ctx, *priceListID)
+		if err != nil || !list.IsActive {
+			if req.PriceListID != nil {
+				return nil, errors.New("price list not found or inactive")
+			}
+			priceListID = nil
+		}
+	}
 
-	// loop items
+	// qty total per item, dipakai untuk stok dan harga bertingkat
+	requested := map[int]int{}
 	for _, it := range req.Items {
-		// find from db
-		item, err := itemRepo.FindByID(ctx, it.ItemID)
+		requested[it.ItemID] += it.Quantity
+	}
+
+	// harga dari daftar harga, item yang tidak ada di daftar pakai harga dasar
+	listPrices := map[int]model.Money{}
+	if priceListID != nil {
+		listPrices, err = priceListRepo.PricesFor(ctx, *priceListID, requested)
 		if err != nil {
 			return nil, err
 		}
+	}
+
+	// promo yang berlaku saat ini, bukan sale_date dari client
+	// supaya sale yang dimundurkan tanggalnya tidak bisa memakai promo yang sudah lewat
+	promoRepo := repository.NewPromotionRepository(tx, s.log)
+	promos, err := promoRepo.ForItems(ctx, itemIDs, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var total model.Money
+	var lines []model.SaleItem
+
+	// promo yang sudah ditambah usage_count-nya di sale ini
+	counted := map[int]bool{}
+
+	// kuota promo bisa habis diambil sale lain setelah ForItems,
+	// promo itu dibuang lalu baris dihitung ulang dengan promo terbaik berikutnya
+	for {
+		total = 0
+		lines = make([]model.SaleItem, 0, len(req.Items))
+		var usedPromos []int
+
+		for _, it := range req.Items {
+			item := items[it.ItemID]
+
+			// item yang sama bisa muncul di beberapa baris, cek stok dari total qty-nya
+			if item.Stock < requested[it.ItemID] {
+				return nil, fmt.Errorf("stock not enough for item %s", item.SKU)
+			}
+
+			line := model.SaleItem{
+				ItemID:    it.ItemID,
+				Quantity:  it.Quantity,
+				UnitPrice: item.Price,
+				Discount:  it.Discount,
+			}
+
+			if price, ok := listPrices[it.ItemID]; ok {
+				line.UnitPrice = price
+				line.PriceListID = priceListID
+			}
+
+			if promo, amount := bestPromotion(promos[it.ItemID], line.UnitPrice, it.Quantity); promo != nil {
+				promoID := promo.ID
+				line.PromotionID = &promoID
+				line.PromotionDiscount = amount
+				if !slices.Contains(usedPromos, promo.ID) {
+					usedPromos = append(usedPromos, promo.ID)
+				}
+			}
+
+			// subtotal per item = harga * qty - diskon promo - diskon manual
+			line.Subtotal = line.UnitPrice.Mul(it.Quantity) - line.PromotionDiscount - it.Discount
+			if line.Subtotal < 0 {
+				return nil, fmt.Errorf("discount for item %s exceeds its subtotal", item.SKU)
+			}
+			total += line.Subtotal
+
+			lines = append(lines, line)
+		}
+
+		// satu sale dihitung satu pemakaian per promo, urut id supaya lock row promo konsisten
+		slices.Sort(usedPromos)
+		exhausted := 0
+		for _, id := range usedPromos {
+			if counted[id] {
+				continue
+			}
+
+			ok, err := promoRepo.IncrementUsage(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("promotion %d: %w", id, err)
+			}
+			if !ok {
+				exhausted = id
+				break
+			}
+			counted[id] = true
+		}
+
+		if exhausted == 0 {
+			break
+		}
 
-		if item.Stock < it.Quantity {
-			return nil, errors.New("stock not enough")
+		for itemID := range promos {
+			promos[itemID] = slices.DeleteFunc(promos[itemID], func(p model.Promotion) bool {
+				return p.ID == exhausted
+			})
 		}
+	}
 
-		// subtotal per item = harga * qty - discount
-		sub := (item.Price * float64(it.Quantity)) - it.Discount
-		total += sub
+	if req.Discount > total {
+		return nil, errors.New("discount exceeds sale total")
 	}
 
-	grandTotal := total - req.Discount + req.Tax
+	tax, addedTax := applySaleTax(lines, req.Discount, rates)
+	grandTotal := total - req.Discount + addedTax
+
+	warehouseCode := ""
+	if req.WarehouseID != nil {
+		warehouse, err := s.warehouseRepo.DetailById(*req.WarehouseID)
+		if err != nil || warehouse == nil || !warehouse.IsActive {
+			return nil, errors.New("warehouse not found or inactive")
+		}
+		warehouseCode = warehouse.Code
+	}
+
+	// nomor invoice digenerate di transaksi yang sama, kecuali dikirim manual (import)
+	invoiceNumber := req.InvoiceNumber
+	if invoiceNumber == "" {
+		invoiceNumber, err = s.invoiceNo.Next(ctx, tx, warehouseCode, saleDate)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	sale := &model.Sale{
-		InvoiceNumber: req.InvoiceNumber,
-		CustomerName:  &req.CustomerName,
-		CustomerPhone: &req.CustomerPhone,
-		CustomerEmail: &req.CustomerEmail,
+		InvoiceNumber: invoiceNumber,
+		WarehouseID:   req.WarehouseID,
+		CustomerID:    req.CustomerID,
+		SaleDate:      saleDate,
+		CustomerName:  &customerName,
+		CustomerPhone: &customerPhone,
+		CustomerEmail: &customerEmail,
 		TotalAmount:   total,
 		Discount:      req.Discount,
-		Tax:           req.Tax,
+		Tax:           tax,
 		GrandTotal:    grandTotal,
 		PaymentMethod: &req.PaymentMethod,
 		Notes:         &req.Notes,
@@ -106,25 +307,30 @@ func (s *saleService) Create(ctx context.Context, usr *model.User, req dto.Creat
 	}
 
 	// loop back, for save sale_item (detaill)
-	for _, it := range req.Items {
-		item, _ := itemRepo.FindByID(ctx, it.ItemID)
-
-		sub := (item.Price * float64(it.Quantity)) - it.Discount
-
-		err = saleRepo.CreateItem(ctx, &model.SaleItem{
-			SaleID:    sale.ID,
-			ItemID:    it.ItemID,
-			Quantity:  it.Quantity,
-			UnitPrice: item.Price,
-			Subtotal:  sub,
-			Discount:  it.Discount,
-		})
+	for i := range lines {
+		line := &lines[i]
+		line.SaleID = sale.ID
+		item := items[line.ItemID]
+
+		// kurangin stok items per rak berdasarkan qty, sekaligus catat mutasinya
+		movements, err := ledger.Consume(ctx, model.StockMovement{
+			ItemID:       line.ItemID,
+			MovementType: model.MovementSale,
+			ReferenceID:  &sale.ID,
+			CreatedBy:    &usr.ID,
+		}, line.Quantity, item.RackID, req.WarehouseID)
 		if err != nil {
 			return nil, err
 		}
 
-		// kurangin stok items berdasarkan qty
-		itemRepo.ReduceStock(ctx, it.ItemID, it.Quantity)
+		// harga pokok baris = nilai persediaan yang keluar
+		for _, mv := range movements {
+			line.Cogs -= mv.TotalCost
+		}
+
+		if err := saleRepo.CreateItem(ctx, line); err != nil {
+			return nil, err
+		}
 	}
 
 	// commit transaksi
@@ -135,6 +341,70 @@ func (s *saleService) Create(ctx context.Context, usr *model.User, req dto.Creat
 	return sale, nil
 }
 
+// bestPromotion memilih promo dengan potongan terbesar untuk satu baris,
+// kalau sama besar promo yang dibuat lebih dulu yang dipakai
+func bestPromotion(promos []model.Promotion, price model.Money, qty int) (*model.Promotion, model.Money) {
+	var best *model.Promotion
+	var bestAmount model.Money
+
+	for i := range promos {
+		amount := promos[i].Discount(price, qty)
+		if amount > bestAmount {
+			best, bestAmount = &promos[i], amount
+		}
+	}
+
+	return best, bestAmount
+}
+
+// applySaleTax menghitung pajak per baris. diskon header dibagi proporsional ke subtotal
+// baris (sisa pembulatan ke baris terakhir) supaya dasar pengenaan pajak sudah setelah diskon.
+// return total pajak dan bagian pajak exclusive yang ditambahkan ke grand total.
+func applySaleTax(lines []model.SaleItem, discount model.Money, rates map[int]*model.TaxRate) (tax, added model.Money) {
+	shares := discountShares(lines, discount)
+
+	for i := range lines {
+		line := &lines[i]
+
+		rate := rates[line.ItemID]
+		if rate == nil {
+			continue
+		}
+
+		lineTax, lineAdded := rate.Split(line.Subtotal - shares[i])
+		line.TaxRateID = &rate.ID
+		line.TaxRate = rate.Rate
+		line.TaxInclusive = rate.IsInclusive
+		line.TaxAmount = lineTax
+
+		tax += lineTax
+		added += lineAdded
+	}
+
+	return tax, added
+}
+
+// discountShares membagi diskon header ke baris proporsional subtotal, sisa pembulatan ke baris terakhir.
+// urutan baris harus sama dengan saat sale dibuat (FindItems urut id) supaya hasilnya sama persis
+func discountShares(lines []model.SaleItem, discount model.Money) []model.Money {
+	var total model.Money
+	for _, line := range lines {
+		total += line.Subtotal
+	}
+
+	shares := make([]model.Money, len(lines))
+	allocated := model.Money(0)
+	for i, line := range lines {
+		shares[i] = discount.MulRatio(int64(line.Subtotal), int64(total))
+		if i == len(lines)-1 {
+			shares[i] = discount - allocated
+		}
+		allocated += shares[i]
+	}
+
+	return shares
+}
+
 func (s *saleService) FindAll(ctx context.Context, page, limit int) (*[]model.Sale, *dto.Pagination, error) {
 	sale, total, err := s.repo.Lists(ctx, page, limit)
 	if err != nil {
@@ -155,7 +425,31 @@ func (s *saleService) Detail(ctx context.Context, usr *model.User, id int) (*mod
 	if !s.permSvc.CanViewSale(usr.Role) {
 		return nil, errors.New("forbidden")
 	}
-	return s.repo.FindDetailByID(ctx, id)
+
+	sale, err := s.repo.FindDetailByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("sale not found")
+	}
+
+	sale.Items, err = s.repo.FindItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+func (s *saleService) Items(ctx context.Context, usr *model.User, id int) ([]model.SaleItem, error) {
+	if !s.permSvc.CanViewSale(usr.Role) {
+		return nil, errors.New("forbidden")
+	}
+
+	// pastikan sale ada
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return nil, errors.New("sale not found")
+	}
+
+	return s.repo.FindItems(ctx, id)
 }
 
 func (s *saleService) Update(ctx context.Context, usr *model.User, sale *model.Sale) error {
@@ -169,6 +463,16 @@ func (s *saleService) Delete(ctx context.Context, usr *model.User, id int) error
 	if !s.permSvc.CanDeleteSale(usr.Role) {
 		return errors.New("forbidden")
 	}
+
+	// riwayat pembayaran (termasuk yang sudah di-void) tidak ikut dihapus
+	payments, err := s.paymentRepo.ListsBySale(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(payments) > 0 {
+		return errors.New("sale has payments, cannot delete")
+	}
+
 	return s.repo.Delete(ctx, id)
 }
 
@@ -178,31 +482,514 @@ func (s *saleService) UpdatePaymentStatus(ctx context.Context, saleID int, req d
 		return nil, errors.New("unauthorized")
 	}
 
-	// get data id from db
-	sale, err := s.repo.FindDetailByID(ctx, saleID)
+	tx, err := s.txMgr.Begin(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback(ctx)
+
+	saleRepo := repository.NewSaleRepository(tx, s.log)
+	paymentRepo := repository.NewSalePaymentRepository(tx, s.log)
+	returnRepo := repository.NewSaleReturnRepository(tx, s.log)
+
+	// get data id from db
+	sale, err := saleRepo.FindDetailByIDForUpdate(ctx, saleID)
+	if err != nil {
+		return nil, errors.New("sale not found")
+	}
 
 	// if paid, not update pay status (double pay)
-	if sale.PaymentStatus == "paid" {
+	if sale.PaymentStatus == model.PaymentPaid {
 		return nil, errors.New("sale already paid")
 	}
 
-	if sale.PaymentStatus == "cancelled" && req.PaymentStatus == "paid" {
-		return nil, errors.New("cancelled sale cannot be paid")
+	if sale.PaymentStatus == model.PaymentCancelled {
+		return nil, errors.New("cancelled sale cannot be updated")
+	}
+
+	due, paid, err := saleBalance(ctx, paymentRepo, returnRepo, sale)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.PaymentStatus {
+	case model.PaymentPaid:
+		// status tidak diset langsung, tapi lewat pembayaran sebesar sisa tagihan
+		if sale.PaymentMethod == nil || *sale.PaymentMethod == "" {
+			return nil, errors.New("sale has no payment method, record the payment via /payments")
+		}
+
+		// tagihan bisa sudah lunas karena refund retur
+		if due > paid {
+			err = paymentRepo.Create(ctx, &model.SalePayment{
+				SaleID:        saleID,
+				Amount:        due - paid,
+				PaymentMethod: *sale.PaymentMethod,
+				CreatedBy:     user.ID,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	case model.PaymentPending:
+		if paid > 0 {
+			return nil, errors.New("sale has payments recorded, void them instead")
+		}
 	}
 
-	// update to db
-	if err := s.repo.UpdatePaymentStatus(ctx, saleID, req.PaymentStatus); err != nil {
+	if err := s.settlePaymentStatus(ctx, saleRepo, paymentRepo, returnRepo, sale); err != nil {
 		return nil, err
 	}
 
 	// get sales data after update
-	updatedSale, err := s.repo.FindDetailByID(ctx, saleID)
+	updatedSale, err := saleRepo.FindDetailByID(ctx, saleID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
 	return updatedSale, nil
 }
+
+func (s *saleService) AddPayment(ctx context.Context, usr *model.User, saleID int, req dto.CreateSalePaymentRequest) (*model.Sale, error) {
+	if !s.permSvc.CanCreateSale(usr.Role) {
+		return nil, errors.New("forbidden")
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	saleRepo := repository.NewSaleRepository(tx, s.log)
+	paymentRepo := repository.NewSalePaymentRepository(tx, s.log)
+	returnRepo := repository.NewSaleReturnRepository(tx, s.log)
+
+	// lock sale supaya pembayaran paralel tidak melebihi grand total
+	sale, err := saleRepo.FindDetailByIDForUpdate(ctx, saleID)
+	if err != nil {
+		return nil, errors.New("sale not found")
+	}
+
+	if sale.PaymentStatus == model.PaymentCancelled {
+		return nil, errors.New("cancelled sale cannot be paid")
+	}
+
+	due, paid, err := saleBalance(ctx, paymentRepo, returnRepo, sale)
+	if err != nil {
+		return nil, err
+	}
+
+	outstanding := due - paid
+	if outstanding <= 0 {
+		return nil, errors.New("sale already paid")
+	}
+	if req.Amount > outstanding {
+		return nil, fmt.Errorf("amount exceeds outstanding balance of %s", outstanding)
+	}
+
+	err = paymentRepo.Create(ctx, &model.SalePayment{
+		SaleID:        saleID,
+		Amount:        req.Amount,
+		PaymentMethod: req.PaymentMethod,
+		Reference:     req.Reference,
+		CreatedBy:     usr.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.settlePaymentStatus(ctx, saleRepo, paymentRepo, returnRepo, sale); err != nil {
+		return nil, err
+	}
+
+	sale.Payments, err = paymentRepo.ListsBySale(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+func (s *saleService) Payments(ctx context.Context, usr *model.User, saleID int) (*model.Sale, error) {
+	if !s.permSvc.CanViewSale(usr.Role) {
+		return nil, errors.New("forbidden")
+	}
+
+	sale, err := s.repo.FindDetailByID(ctx, saleID)
+	if err != nil {
+		return nil, errors.New("sale not found")
+	}
+
+	sale.Payments, err = s.paymentRepo.ListsBySale(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+func (s *saleService) VoidPayment(ctx context.Context, usr *model.User, saleID, paymentID int, req dto.VoidSalePaymentRequest) (*model.Sale, error) {
+	if usr.Role != "super_admin" && usr.Role != "admin" {
+		return nil, errors.New("unauthorized")
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	saleRepo := repository.NewSaleRepository(tx, s.log)
+	paymentRepo := repository.NewSalePaymentRepository(tx, s.log)
+	returnRepo := repository.NewSaleReturnRepository(tx, s.log)
+
+	sale, err := saleRepo.FindDetailByIDForUpdate(ctx, saleID)
+	if err != nil {
+		return nil, errors.New("sale not found")
+	}
+
+	if _, err := paymentRepo.FindByID(ctx, saleID, paymentID); err != nil {
+		return nil, err
+	}
+
+	if err := paymentRepo.Void(ctx, paymentID, usr.ID, req.Reason); err != nil {
+		return nil, err
+	}
+
+	// sale yang sudah cancel tetap cancelled, void hanya mencatat pembayaran yang dibatalkan
+	if sale.PaymentStatus != model.PaymentCancelled {
+		if err := s.settlePaymentStatus(ctx, saleRepo, paymentRepo, returnRepo, sale); err != nil {
+			return nil, err
+		}
+	}
+
+	sale.Payments, err = paymentRepo.ListsBySale(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+// saleBalance return tagihan sale (grand total dikurangi refund retur) dan total yang sudah dibayar
+func saleBalance(ctx context.Context, paymentRepo repository.SalePaymentRepository, returnRepo repository.SaleReturnRepository, sale *model.Sale) (due, paid model.Money, err error) {
+	paid, err = paymentRepo.TotalPaid(ctx, sale.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	refunded, err := returnRepo.TotalRefunded(ctx, sale.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return max(sale.GrandTotal-refunded, 0), paid, nil
+}
+
+// hitung ulang status pembayaran dari total sale_payments yang belum di-void
+// terhadap tagihan setelah retur
+func (s *saleService) settlePaymentStatus(ctx context.Context, saleRepo repository.SaleRepository, paymentRepo repository.SalePaymentRepository, returnRepo repository.SaleReturnRepository, sale *model.Sale) error {
+	due, paid, err := saleBalance(ctx, paymentRepo, returnRepo, sale)
+	if err != nil {
+		return err
+	}
+
+	status := model.PaymentPending
+	switch {
+	case paid >= due:
+		status = model.PaymentPaid
+	case paid > 0:
+		status = model.PaymentPartiallyPaid
+	}
+
+	if status == sale.PaymentStatus {
+		return nil
+	}
+
+	if err := saleRepo.UpdatePaymentStatus(ctx, sale.ID, status); err != nil {
+		return err
+	}
+	sale.PaymentStatus = status
+
+	return nil
+}
+
+func (s *saleService) Cancel(ctx context.Context, usr *model.User, saleID int, req dto.CancelSaleRequest) (*model.Sale, error) {
+	if usr.Role != "super_admin" && usr.Role != "admin" {
+		return nil, errors.New("unauthorized")
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	saleRepo := repository.NewSaleRepository(tx, s.log)
+	returnRepo := repository.NewSaleReturnRepository(tx, s.log)
+	movementRepo := repository.NewStockMovementRepository(tx, s.log)
+	ledger := newStockLedger(tx, s.log, s.costing)
+
+	sale, err := saleRepo.FindDetailByIDForUpdate(ctx, saleID)
+	if err != nil {
+		return nil, errors.New("sale not found")
+	}
+
+	if sale.PaymentStatus == model.PaymentCancelled {
+		return nil, errors.New("sale already cancelled")
+	}
+
+	// retur yang sudah diposting harus diselesaikan lewat retur, bukan cancel
+	returned, err := returnRepo.ReturnedQuantities(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	if len(returned) > 0 {
+		return nil, errors.New("sale has returns posted and cannot be cancelled")
+	}
+
+	// uang yang sudah diterima harus di-void / dikembalikan dulu lewat pembayaran
+	paid, err := repository.NewSalePaymentRepository(tx, s.log).TotalPaid(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	if paid > 0 {
+		return nil, errors.New("sale has active payments, void them before cancelling")
+	}
+
+	// balikin stok ke rak asal sesuai mutasi saat sale dibuat
+	movements, err := movementRepo.FindByReference(ctx, model.MovementSale, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	itemIDs := make([]int, 0, len(movements))
+	for _, mv := range movements {
+		itemIDs = append(itemIDs, mv.ItemID)
+	}
+	if _, err := ledger.Lock(ctx, itemIDs...); err != nil {
+		return nil, err
+	}
+
+	notes := "sale cancelled: " + req.Reason
+	for _, mv := range movements {
+		err := ledger.Post(ctx, &model.StockMovement{
+			ItemID:       mv.ItemID,
+			RackID:       mv.RackID,
+			MovementType: model.MovementCancellation,
+			Quantity:     -mv.Quantity,
+			TotalCost:    -mv.TotalCost, // nilai kembali sama dengan saat keluar
+			ReferenceID:  &saleID,
+			Notes:        &notes,
+			CreatedBy:    &usr.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// kuota promo yang dipakai sale ini dikembalikan, satu pemakaian per promo
+	saleItems, err := saleRepo.FindItems(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	var usedPromos []int
+	for _, si := range saleItems {
+		if si.PromotionID != nil && !slices.Contains(usedPromos, *si.PromotionID) {
+			usedPromos = append(usedPromos, *si.PromotionID)
+		}
+	}
+
+	slices.Sort(usedPromos)
+	promoRepo := repository.NewPromotionRepository(tx, s.log)
+	for _, id := range usedPromos {
+		if err := promoRepo.DecrementUsage(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := saleRepo.Cancel(ctx, saleID, usr.ID, req.Reason); err != nil {
+		return nil, err
+	}
+
+	sale, err = saleRepo.FindDetailByID(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+func (s *saleService) CreateReturn(ctx context.Context, usr *model.User, saleID int, req dto.CreateSaleReturnRequest) (*model.SaleReturn, error) {
+	if !s.permSvc.CanCreateSale(usr.Role) {
+		return nil, errors.New("forbidden")
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	saleRepo := repository.NewSaleRepository(tx, s.log)
+	itemRepo := repository.NewItemsRepository(tx, s.log)
+	returnRepo := repository.NewSaleReturnRepository(tx, s.log)
+	ledger := newStockLedger(tx, s.log, s.costing)
+
+	// lock sale supaya retur paralel tidak melebihi qty terjual
+	sale, err := saleRepo.FindDetailByIDForUpdate(ctx, saleID)
+	if err != nil {
+		return nil, errors.New("sale not found")
+	}
+
+	if sale.PaymentStatus == model.PaymentCancelled {
+		return nil, errors.New("cancelled sale cannot be returned")
+	}
+
+	saleItems, err := saleRepo.FindItems(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// nilai baris yang dibayar customer: total baris dikurangi bagian diskon header
+	lines := map[int]model.SaleItem{}
+	paidTotals := map[int]model.Money{}
+	shares := discountShares(saleItems, sale.Discount)
+	for i, si := range saleItems {
+		lines[si.ID] = si
+		paidTotals[si.ID] = si.Total() - shares[i]
+	}
+
+	returned, err := returnRepo.ReturnedQuantities(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &model.SaleReturn{
+		SaleID:       saleID,
+		RefundMethod: req.RefundMethod,
+		Reason:       req.Reason,
+		CreatedBy:    usr.ID,
+	}
+
+	// nilai persediaan yang kembali per baris retur
+	var costs []model.Money
+
+	for _, it := range req.Items {
+		line, ok := lines[it.SaleItemID]
+		if !ok {
+			return nil, fmt.Errorf("sale item %d is not part of this sale", it.SaleItemID)
+		}
+
+		// qty retur tidak boleh melebihi qty terjual dikurangi retur sebelumnya
+		left := line.Quantity - returned[line.ID]
+		if it.Quantity > left {
+			return nil, fmt.Errorf("sale item %d: only %d left to return", line.ID, left)
+		}
+
+		// bagian cogs & refund baris sesuai qty retur, dihitung kumulatif
+		// supaya retur semua qty = cogs baris & nilai yang dibayar tanpa sisa pembulatan
+		prev := returned[line.ID]
+		returned[line.ID] += it.Quantity
+		costs = append(costs,
+			line.Cogs.MulRatio(int64(returned[line.ID]), int64(line.Quantity))-line.Cogs.MulRatio(int64(prev), int64(line.Quantity)))
+
+		paid := paidTotals[line.ID]
+		refund := paid.MulRatio(int64(returned[line.ID]), int64(line.Quantity)) - paid.MulRatio(int64(prev), int64(line.Quantity))
+
+		rackID := it.RackID
+		if rackID == nil {
+			item, err := itemRepo.FindByID(ctx, line.ItemID)
+			if err != nil {
+				return nil, err
+			}
+			rackID = item.RackID
+		}
+		if rackID == nil {
+			return nil, fmt.Errorf("sale item %d: rack_id is required, item has no default rack", line.ID)
+		}
+
+		ret.Items = append(ret.Items, model.SaleReturnItem{
+			SaleItemID:   line.ID,
+			ItemID:       line.ItemID,
+			RackID:       *rackID,
+			Quantity:     it.Quantity,
+			RefundAmount: refund,
+		})
+		ret.RefundAmount += refund
+	}
+
+	itemIDs := make([]int, 0, len(ret.Items))
+	for _, it := range ret.Items {
+		itemIDs = append(itemIDs, it.ItemID)
+	}
+	if _, err := ledger.Lock(ctx, itemIDs...); err != nil {
+		return nil, err
+	}
+
+	if err := returnRepo.Create(ctx, ret); err != nil {
+		return nil, err
+	}
+
+	for i := range ret.Items {
+		it := &ret.Items[i]
+		it.ReturnID = ret.ID
+
+		if err := returnRepo.CreateItem(ctx, it); err != nil {
+			return nil, err
+		}
+
+		// barang masuk lagi ke rak
+		err = ledger.Post(ctx, &model.StockMovement{
+			ItemID:       it.ItemID,
+			RackID:       it.RackID,
+			MovementType: model.MovementReturn,
+			Quantity:     it.Quantity,
+			TotalCost:    costs[i],
+			ReferenceID:  &ret.ID,
+			CreatedBy:    &usr.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// refund mengurangi tagihan, status pembayaran ikut dihitung ulang
+	paymentRepo := repository.NewSalePaymentRepository(tx, s.log)
+	if err := s.settlePaymentStatus(ctx, saleRepo, paymentRepo, returnRepo, sale); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (s *saleService) Returns(ctx context.Context, usr *model.User, saleID int) ([]model.SaleReturn, error) {
+	if !s.permSvc.CanViewSale(usr.Role) {
+		return nil, errors.New("forbidden")
+	}
+
+	return s.returnRepo.ListsBySale(ctx, saleID)
+}
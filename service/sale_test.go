@@ -0,0 +1,374 @@
+package service
+
+import (
+	"alfdwirhmn/inventory/database"
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/repository"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// testDB membuka koneksi ke database test (schema dari sql/db.sql).
+// test di-skip kalau TEST_DATABASE_URL tidak diset, jangan arahkan ke database production.
+func testDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := pgxpool.New(context.Background(), url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(db.Close)
+
+	if err := db.Ping(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+// stockFixture membuat user, gudang dengan dua rak, gudang lain dengan satu rak, dan satu item
+// yang stok awalnya langsung ditaruh di item_locations (tanpa mutasi).
+type stockFixture struct {
+	userID      int
+	warehouseID int
+	itemID      int
+	stock       int // stok awal item di semua gudang
+	inWarehouse int // stok awal item di warehouseID
+}
+
+func newStockFixture(t *testing.T, db *pgxpool.Pool) stockFixture {
+	t.Helper()
+
+	ctx := context.Background()
+	code := fmt.Sprintf("T%d", time.Now().UnixNano()%1_000_000_000)
+
+	insert := func(query string, args ...any) int {
+		t.Helper()
+
+		var id int
+		if err := db.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	var f stockFixture
+	f.userID = insert(`INSERT INTO users (username, email, password_hash, full_name, role) VALUES ($1, $1 || '@test.local', '-', 'stock test', 'super_admin') RETURNING id`, code)
+	categoryID := insert(`INSERT INTO categories (code, name) VALUES ($1, $1) RETURNING id`, code)
+	f.warehouseID = insert(`INSERT INTO warehouses (code, name) VALUES ($1, $1) RETURNING id`, code)
+	otherWarehouseID := insert(`INSERT INTO warehouses (code, name) VALUES ($1 || 'X', $1) RETURNING id`, code)
+
+	racks := []int{
+		insert(`INSERT INTO racks (warehouse_id, code, name) VALUES ($1, $2 || 'A', 'A') RETURNING id`, f.warehouseID, code),
+		insert(`INSERT INTO racks (warehouse_id, code, name) VALUES ($1, $2 || 'B', 'B') RETURNING id`, f.warehouseID, code),
+		insert(`INSERT INTO racks (warehouse_id, code, name) VALUES ($1, $2 || 'C', 'C') RETURNING id`, otherWarehouseID, code),
+	}
+
+	f.itemID = insert(`INSERT INTO items (category_id, rack_id, sku, name, price, cost, stock) VALUES ($1, $2, $3, $3, 10000, 6000, 0) RETURNING id`, categoryID, racks[0], code)
+
+	quantities := []int{7, 5, 10}
+	for i, rackID := range racks {
+		_, err := db.Exec(ctx, `INSERT INTO item_locations (item_id, rack_id, quantity) VALUES ($1, $2, $3)`, f.itemID, rackID, quantities[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.stock += quantities[i]
+	}
+	f.inWarehouse = quantities[0] + quantities[1]
+
+	if _, err := db.Exec(ctx, `UPDATE items SET stock = $2 WHERE id = $1`, f.itemID, f.stock); err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+// sale paralel ke satu item di satu gudang tidak boleh membuat stok minus
+// atau selisih antara items.stock, item_locations dan stock_movements
+func TestSaleCreateConcurrentStock(t *testing.T) {
+	db := testDB(t)
+	f := newStockFixture(t, db)
+
+	log := zap.NewNop()
+	svc := NewSaleService(
+		repository.NewSaleRepository(db, log),
+		repository.NewItemsRepository(db, log),
+		repository.NewSaleReturnRepository(db, log),
+		repository.NewSalePaymentRepository(db, log),
+		repository.NewWarehouseRepository(db, log),
+		"INV/{WAREHOUSE}/{YYYYMM}/{SEQ:5}",
+		model.CostingFIFO,
+		NewPermissionService(),
+		database.NewTxManager(db),
+		log,
+	)
+
+	ctx := context.Background()
+	usr := &model.User{ID: f.userID, Role: "super_admin"}
+	workers := f.inWarehouse + 10
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		sold   int
+		failed []error
+	)
+
+	start := make(chan struct{})
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			_, err := svc.Create(ctx, usr, dto.CreateSaleRequest{
+				WarehouseID:  &f.warehouseID,
+				CustomerName: "stock test",
+				Items:        []dto.CreateSaleItemRequest{{ItemID: f.itemID, Quantity: 1}},
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed = append(failed, err)
+				return
+			}
+			sold++
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if sold != f.inWarehouse {
+		t.Errorf("sold %d, want %d (stock in warehouse), errors: %v", sold, f.inWarehouse, failed)
+	}
+
+	var stock, inRacks, moved int
+	err := db.QueryRow(ctx, `
+		SELECT
+			i.stock,
+			(SELECT COALESCE(SUM(quantity), 0) FROM item_locations WHERE item_id = i.id),
+			(SELECT COALESCE(SUM(quantity), 0) FROM stock_movements WHERE item_id = i.id)
+		FROM items i WHERE i.id = $1`, f.itemID).Scan(&stock, &inRacks, &moved)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stock < 0 {
+		t.Errorf("items.stock is negative: %d", stock)
+	}
+	if stock != inRacks {
+		t.Errorf("items.stock = %d, item_locations total = %d", stock, inRacks)
+	}
+	if stock != f.stock+moved {
+		t.Errorf("items.stock = %d, initial %d + movements %d = %d", stock, f.stock, moved, f.stock+moved)
+	}
+}
+
+func TestBestPromotion(t *testing.T) {
+	intp := func(v int) *int { return &v }
+
+	percent10 := model.Promotion{ID: 1, PromoType: model.PromoPercentage, Value: 1000}
+	fixed100 := model.Promotion{ID: 2, PromoType: model.PromoFixed, Value: 10000}
+	fixed100Later := model.Promotion{ID: 3, PromoType: model.PromoFixed, Value: 10000}
+	buy2get1 := model.Promotion{ID: 4, PromoType: model.PromoBuyXGetY, BuyQuantity: intp(2), GetQuantity: intp(1)}
+
+	tests := []struct {
+		name       string
+		promos     []model.Promotion
+		price      model.Money
+		qty        int
+		wantID     int // 0 = tidak ada promo
+		wantAmount model.Money
+	}{
+		{
+			name:  "no promotions",
+			price: 100000, qty: 1,
+		},
+		{
+			name:   "largest discount wins",
+			promos: []model.Promotion{percent10, fixed100, buy2get1},
+			price:  100000, qty: 3,
+			wantID: 4, wantAmount: 100000,
+		},
+		{
+			name:   "largest discount wins regardless of order",
+			promos: []model.Promotion{buy2get1, fixed100, percent10},
+			price:  200000, qty: 1,
+			wantID: 1, wantAmount: 20000,
+		},
+		{
+			name:   "tie keeps the earlier promotion",
+			promos: []model.Promotion{percent10, fixed100}, // 10% dari 1000.00 = 100.00
+			price:  100000, qty: 1,
+			wantID: 1, wantAmount: 10000,
+		},
+		{
+			name:   "tie between same promotions keeps first",
+			promos: []model.Promotion{fixed100, fixed100Later},
+			price:  100000, qty: 2,
+			wantID: 2, wantAmount: 20000,
+		},
+		{
+			name:   "zero discount is not applied",
+			promos: []model.Promotion{buy2get1},
+			price:  100000, qty: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promo, amount := bestPromotion(tt.promos, tt.price, tt.qty)
+
+			gotID := 0
+			if promo != nil {
+				gotID = promo.ID
+			}
+			if gotID != tt.wantID || amount != tt.wantAmount {
+				t.Errorf("bestPromotion = (%d, %v), want (%d, %v)", gotID, amount, tt.wantID, tt.wantAmount)
+			}
+		})
+	}
+}
+
+func TestApplySaleTax(t *testing.T) {
+	exclusive11 := &model.TaxRate{ID: 1, Rate: 1100}
+	inclusive11 := &model.TaxRate{ID: 2, Rate: 1100, IsInclusive: true}
+	exclusive10 := &model.TaxRate{ID: 3, Rate: 1000}
+	exclusive50 := &model.TaxRate{ID: 4, Rate: 5000}
+
+	tests := []struct {
+		name      string
+		subtotals []model.Money // subtotal per baris, item id = index + 1
+		rates     map[int]*model.TaxRate
+		discount  model.Money
+		wantTaxes []model.Money // pajak per baris
+		wantAdded model.Money
+	}{
+		{
+			name:      "exclusive",
+			subtotals: []model.Money{100000},
+			rates:     map[int]*model.TaxRate{1: exclusive11},
+			wantTaxes: []model.Money{11000},
+			wantAdded: 11000,
+		},
+		{
+			name:      "inclusive does not add to total",
+			subtotals: []model.Money{111000},
+			rates:     map[int]*model.TaxRate{1: inclusive11},
+			wantTaxes: []model.Money{11000},
+		},
+		{
+			name:      "exempt item without rate",
+			subtotals: []model.Money{100000, 50000},
+			rates:     map[int]*model.TaxRate{1: exclusive11},
+			wantTaxes: []model.Money{11000, 0},
+			wantAdded: 11000,
+		},
+		{
+			name:      "no rates at all",
+			subtotals: []model.Money{100000, 50000},
+			discount:  10000,
+			wantTaxes: []model.Money{0, 0},
+		},
+		{
+			name:      "header discount split proportionally before tax",
+			subtotals: []model.Money{10000, 30000}, // diskon 250 & 750
+			rates:     map[int]*model.TaxRate{1: exclusive10, 2: exclusive10},
+			discount:  1000,
+			wantTaxes: []model.Money{975, 2925},
+			wantAdded: 3900,
+		},
+		{
+			name:      "mixed inclusive, exclusive and exempt with discount",
+			subtotals: []model.Money{10000, 20000, 30000}, // diskon 1000, 2000, 3000
+			rates:     map[int]*model.TaxRate{1: exclusive11, 3: inclusive11},
+			discount:  6000,
+			wantTaxes: []model.Money{990, 0, 2676}, // 27000 * 11 / 111 = 2675.67
+			wantAdded: 990,
+		},
+		{
+			name:      "rounding remainder of discount goes to last line",
+			subtotals: []model.Money{100, 100, 100}, // diskon 33, 33, 34
+			rates:     map[int]*model.TaxRate{1: exclusive50, 2: exclusive50, 3: exclusive50},
+			discount:  100,
+			wantTaxes: []model.Money{34, 34, 33}, // 33.5, 33.5, 33
+			wantAdded: 101,
+		},
+		{
+			name:      "discount equal to total leaves no tax",
+			subtotals: []model.Money{5000, 5000},
+			rates:     map[int]*model.TaxRate{1: exclusive11, 2: inclusive11},
+			discount:  10000,
+			wantTaxes: []model.Money{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := make([]model.SaleItem, len(tt.subtotals))
+			var total model.Money
+			for i, sub := range tt.subtotals {
+				lines[i] = model.SaleItem{ItemID: i + 1, Quantity: 1, UnitPrice: sub, Subtotal: sub}
+				total += sub
+			}
+
+			tax, added := applySaleTax(lines, tt.discount, tt.rates)
+
+			// nilai yang dibayar per baris, dasar refund retur
+			shares := discountShares(lines, tt.discount)
+
+			var lineTax, lineTotal, linePaid, shared model.Money
+			for i, line := range lines {
+				if line.TaxAmount != tt.wantTaxes[i] {
+					t.Errorf("line %d tax = %v, want %v", i, line.TaxAmount, tt.wantTaxes[i])
+				}
+
+				rate := tt.rates[line.ItemID]
+				if rate == nil && line.TaxRateID != nil {
+					t.Errorf("line %d is exempt but has tax rate %d", i, *line.TaxRateID)
+				}
+				if rate != nil && (line.TaxRateID == nil || *line.TaxRateID != rate.ID || line.TaxInclusive != rate.IsInclusive) {
+					t.Errorf("line %d tax rate not recorded", i)
+				}
+
+				lineTax += line.TaxAmount
+				lineTotal += line.Total()
+				linePaid += line.Total() - shares[i]
+				shared += shares[i]
+			}
+
+			if added != tt.wantAdded {
+				t.Errorf("added = %v, want %v", added, tt.wantAdded)
+			}
+
+			// total harus cocok sampai sen: pajak header = jumlah pajak baris,
+			// grand total = jumlah total baris dikurangi diskon header
+			if tax != lineTax {
+				t.Errorf("tax = %v, sum of line tax = %v", tax, lineTax)
+			}
+			if grand := total - tt.discount + added; grand != lineTotal-tt.discount {
+				t.Errorf("grand total = %v, sum of line totals - discount = %v", grand, lineTotal-tt.discount)
+			}
+			if shared != tt.discount {
+				t.Errorf("discount shares = %v, want %v", shared, tt.discount)
+			}
+			if grand := total - tt.discount + added; linePaid != grand {
+				t.Errorf("sum of paid line totals = %v, grand total = %v", linePaid, grand)
+			}
+		})
+	}
+}
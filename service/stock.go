@@ -0,0 +1,277 @@
+package service
+
+import (
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/repository"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// stockLedger jadi satu-satunya jalur untuk mengubah stok item,
+// setiap perubahan stok per rak langsung dicatat ke stock_movements
+// dan items.stock dihitung ulang dari total semua rak.
+// db harus berupa transaksi yang sama dengan perubahan dokumennya.
+//
+// urutan lock selalu items (urut id) dulu baru item_locations lalu cost_layers,
+// dokumen dengan banyak item wajib memanggil Lock di awal transaksi.
+//
+// setiap mutasi juga membawa nilai persediaan (total_cost). barang masuk membuat
+// cost layer, barang keluar menghabiskan layer urut FIFO. costing menentukan harga pokok
+// barang keluar: fifo = nilai layer yang terpakai, average = rata-rata nilai persediaan.
+type stockLedger struct {
+	itemRepo     repository.ItemsRepository
+	locationRepo repository.ItemLocationRepository
+	movementRepo repository.StockMovementRepository
+	layerRepo    repository.CostLayerRepository
+	costing      string
+}
+
+func newStockLedger(db repository.DBTX, log *zap.Logger, costing string) *stockLedger {
+	return &stockLedger{
+		itemRepo:     repository.NewItemsRepository(db, log),
+		locationRepo: repository.NewItemLocationRepository(db, log),
+		movementRepo: repository.NewStockMovementRepository(db, log),
+		layerRepo:    repository.NewCostLayerRepository(db, log),
+		costing:      costing,
+	}
+}
+
+// Lock mengunci baris items yang akan diubah stoknya, urut id.
+// item yang sudah dikunci di transaksi yang sama tidak menunggu lagi.
+func (l *stockLedger) Lock(ctx context.Context, itemIDs ...int) (map[int]*model.Item, error) {
+	items, err := l.itemRepo.LockByIDs(ctx, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := make(map[int]*model.Item, len(items))
+	for i := range items {
+		locked[items[i].ID] = &items[i]
+	}
+
+	for _, id := range itemIDs {
+		if _, ok := locked[id]; !ok {
+			return nil, fmt.Errorf("item %d not found", id)
+		}
+	}
+
+	return locked, nil
+}
+
+func (l *stockLedger) Post(ctx context.Context, mv *model.StockMovement) error {
+	if mv.RackID == 0 {
+		return errors.New("rack is required for stock movement")
+	}
+
+	locked, err := l.Lock(ctx, mv.ItemID)
+	if err != nil {
+		return err
+	}
+
+	// nilai dihitung dari stok sebelum mutasi
+	if err := l.cost(ctx, locked[mv.ItemID], mv); err != nil {
+		return err
+	}
+
+	if _, err := l.locationRepo.AdjustQuantity(ctx, mv.ItemID, mv.RackID, mv.Quantity); err != nil {
+		return err
+	}
+
+	stock, err := l.itemRepo.SyncStock(ctx, mv.ItemID)
+	if err != nil {
+		return err
+	}
+
+	mv.StockAfter = stock
+	if err := l.movementRepo.Create(ctx, mv); err != nil {
+		return err
+	}
+
+	// transfer hanya pindah lokasi, tidak menambah lapisan biaya
+	if mv.Quantity > 0 && mv.MovementType != model.MovementTransfer {
+		return l.layerRepo.Create(ctx, &model.CostLayer{
+			ItemID:         mv.ItemID,
+			MovementID:     mv.ID,
+			Quantity:       mv.Quantity,
+			UnitCost:       mv.UnitCost,
+			RemainingValue: mv.TotalCost,
+		})
+	}
+
+	return nil
+}
+
+// cost mengisi UnitCost & TotalCost mutasi. barang masuk memakai TotalCost dari caller
+// kalau diisi (receipt, retur, transfer masuk), selain itu rata-rata persediaan saat ini.
+// barang keluar dengan TotalCost dari caller (negatif) tetap menghabiskan layer senilai itu.
+func (l *stockLedger) cost(ctx context.Context, item *model.Item, mv *model.StockMovement) error {
+	qty := mv.Quantity
+	if qty < 0 {
+		qty = -qty
+	}
+
+	var cost model.Money
+	var err error
+
+	switch {
+	case mv.Quantity > 0 && mv.TotalCost > 0:
+		cost = mv.TotalCost
+	case mv.Quantity > 0 || mv.MovementType == model.MovementTransfer:
+		cost, err = l.averageCost(ctx, item, qty)
+	case mv.TotalCost < 0:
+		value := -mv.TotalCost
+		cost, err = l.consumeLayers(ctx, item, qty, &value)
+	default:
+		// layer tetap dihabiskan di mode average supaya sisa qty per layer tetap benar
+		cost, err = l.consumeLayers(ctx, item, qty, nil)
+		if err == nil && l.costing == model.CostingAverage {
+			cost, err = l.averageCost(ctx, item, qty)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	mv.UnitCost = cost.MulRatio(1, int64(qty))
+	mv.TotalCost = cost
+	if mv.Quantity < 0 {
+		mv.TotalCost = -cost
+	}
+
+	return nil
+}
+
+// nilai qty unit dengan harga rata-rata persediaan, fallback ke cost master item
+// kalau belum ada nilai (stok lama sebelum costing / stok kosong)
+func (l *stockLedger) averageCost(ctx context.Context, item *model.Item, qty int) (model.Money, error) {
+	value, err := l.movementRepo.ValueOnHand(ctx, item.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	if item.Stock <= 0 || value <= 0 {
+		return item.Cost.Mul(qty), nil
+	}
+
+	return value.MulRatio(int64(qty), int64(item.Stock)), nil
+}
+
+// consumeLayers menghabiskan layer urut FIFO dan mengembalikan nilainya.
+// qty yang tidak tertutup layer dinilai dengan cost master item.
+// value diisi kalau nilai keluar sudah ditentukan caller, nilai yang diambil dari layer
+// diskalakan ke value supaya total layer tetap sama dengan total nilai mutasi
+func (l *stockLedger) consumeLayers(ctx context.Context, item *model.Item, qty int, value *model.Money) (model.Money, error) {
+	layers, err := l.layerRepo.LockOpen(ctx, item.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	type take struct {
+		layer model.CostLayer
+		qty   int
+		value model.Money
+	}
+
+	var takes []take
+	var layered model.Money
+	remaining := qty
+	for _, layer := range layers {
+		if remaining == 0 {
+			break
+		}
+
+		n := min(layer.RemainingQuantity, remaining)
+
+		v := layer.RemainingValue
+		if n < layer.RemainingQuantity {
+			v = layer.RemainingValue.MulRatio(int64(n), int64(layer.RemainingQuantity))
+		}
+
+		takes = append(takes, take{layer: layer, qty: n, value: v})
+		layered += v
+		remaining -= n
+	}
+
+	cost := layered + item.Cost.Mul(remaining)
+
+	if value != nil {
+		// bagian value untuk layer dibagi proporsional nilai FIFO, sisa pembulatan ke layer terakhir
+		target := value.MulRatio(int64(layered), int64(cost))
+		allocated := model.Money(0)
+		for i := range takes {
+			t := &takes[i]
+			t.value = value.MulRatio(int64(t.value), int64(cost))
+			if i == len(takes)-1 {
+				t.value = target - allocated
+			}
+			t.value = max(min(t.value, t.layer.RemainingValue), 0)
+			allocated += t.value
+		}
+		cost = *value
+	}
+
+	for _, t := range takes {
+		if err := l.layerRepo.Consume(ctx, t.layer.ID, t.qty, t.value); err != nil {
+			return 0, err
+		}
+	}
+
+	return cost, nil
+}
+
+// Consume mengeluarkan qty dari rak-rak yang tersedia (opsional hanya di satu gudang),
+// rak utama item didahulukan, sisanya diambil urut rack_id. satu mutasi dicatat per rak yang terpakai.
+func (l *stockLedger) Consume(ctx context.Context, base model.StockMovement, qty int, preferredRack, warehouseID *int) ([]model.StockMovement, error) {
+	if _, err := l.Lock(ctx, base.ItemID); err != nil {
+		return nil, err
+	}
+
+	locations, err := l.locationRepo.LockAvailable(ctx, base.ItemID, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if preferredRack != nil {
+		for i, loc := range locations {
+			if loc.RackID == *preferredRack {
+				// geser ke depan tanpa mengubah urutan rak lainnya
+				copy(locations[1:i+1], locations[:i])
+				locations[0] = loc
+				break
+			}
+		}
+	}
+
+	available := 0
+	for _, loc := range locations {
+		available += loc.Quantity
+	}
+	if available < qty {
+		return nil, errors.New("stock not enough")
+	}
+
+	var movements []model.StockMovement
+	remaining := qty
+	for _, loc := range locations {
+		if remaining == 0 {
+			break
+		}
+
+		take := min(loc.Quantity, remaining)
+
+		mv := base
+		mv.RackID = loc.RackID
+		mv.Quantity = -take
+		if err := l.Post(ctx, &mv); err != nil {
+			return nil, err
+		}
+
+		movements = append(movements, mv)
+		remaining -= take
+	}
+
+	return movements, nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"alfdwirhmn/inventory/database"
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/repository"
+	"alfdwirhmn/inventory/utils"
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+type SupplierService interface {
+	Create(ctx context.Context, usr *model.User, req dto.CreateSupplierRequest) (*model.Supplier, error)
+	FindAll(ctx context.Context, search string, page, limit int) (*[]model.Supplier, *dto.Pagination, error)
+	FindByID(ctx context.Context, usr *model.User, id int) (*model.Supplier, error)
+	Update(ctx context.Context, usr *model.User, id int, req dto.UpdateSupplierRequest) (*model.Supplier, error)
+	Delete(ctx context.Context, usr *model.User, id int) error
+
+	// item yang disuplai supplier (sku supplier, lead time, harga beli terakhir)
+	Items(ctx context.Context, usr *model.User, id int) ([]model.ItemSupplier, error)
+	UpsertItem(ctx context.Context, usr *model.User, id int, req dto.UpsertItemSupplierRequest) (*model.ItemSupplier, error)
+	DeleteItem(ctx context.Context, usr *model.User, id, itemID int) error
+
+	// semua supplier untuk satu item, supplier utama dulu
+	ItemSuppliers(ctx context.Context, usr *model.User, itemID int) ([]model.ItemSupplier, error)
+}
+
+type supplierService struct {
+	repo     repository.SupplierRepository
+	itemRepo repository.ItemsRepository
+	permSvc  PermissionService
+	txMgr    database.TxManager
+	log      *zap.Logger
+}
+
+func NewSupplierService(repo repository.SupplierRepository, itemRepo repository.ItemsRepository, permSvc PermissionService, tx database.TxManager, log *zap.Logger) SupplierService {
+	return &supplierService{
+		repo:     repo,
+		itemRepo: itemRepo,
+		permSvc:  permSvc,
+		txMgr:    tx,
+		log:      log,
+	}
+}
+
+func (s *supplierService) Create(ctx context.Context, usr *model.User, req dto.CreateSupplierRequest) (*model.Supplier, error) {
+	if !s.permSvc.CanCreateMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot create supplier")
+	}
+
+	supplier := buildSupplier(req)
+	createdBy := usr.ID
+	supplier.CreatedBy = &createdBy
+
+	return s.repo.Create(ctx, supplier)
+}
+
+func (s *supplierService) FindAll(ctx context.Context, search string, page, limit int) (*[]model.Supplier, *dto.Pagination, error) {
+	suppliers, total, err := s.repo.Lists(ctx, search, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := dto.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: utils.TotalPage(limit, int64(total)),
+		TotalRows:  total,
+	}
+
+	return &suppliers, &pagination, nil
+}
+
+func (s *supplierService) FindByID(ctx context.Context, usr *model.User, id int) (*model.Supplier, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot access supplier")
+	}
+
+	return s.repo.FindByID(ctx, id)
+}
+
+func (s *supplierService) Update(ctx context.Context, usr *model.User, id int, req dto.UpdateSupplierRequest) (*model.Supplier, error) {
+	if !s.permSvc.CanUpdateMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot update supplier")
+	}
+
+	supplier := buildSupplier(req.CreateSupplierRequest)
+	supplier.ID = id
+	supplier.IsActive = req.IsActive
+
+	return s.repo.Update(ctx, supplier)
+}
+
+func (s *supplierService) Delete(ctx context.Context, usr *model.User, id int) error {
+	if !s.permSvc.CanDeleteMasterData(usr.Role) {
+		return errors.New("forbidden: cannot delete supplier")
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *supplierService) Items(ctx context.Context, usr *model.User, id int) ([]model.ItemSupplier, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot access supplier")
+	}
+
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ItemsBySupplier(ctx, id)
+}
+
+func (s *supplierService) UpsertItem(ctx context.Context, usr *model.User, id int, req dto.UpsertItemSupplierRequest) (*model.ItemSupplier, error) {
+	if !s.permSvc.CanUpdateMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot update supplier")
+	}
+
+	supplier, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !supplier.IsActive {
+		return nil, errors.New("supplier is inactive")
+	}
+
+	item, err := s.itemRepo.FindByID(ctx, req.ItemID)
+	if err != nil || !item.IsActive {
+		return nil, errors.New("item not found or inactive")
+	}
+
+	if req.LastPurchasePrice != nil && *req.LastPurchasePrice < 0 {
+		return nil, errors.New("last purchase price cannot be negative")
+	}
+
+	minQty := req.MinOrderQuantity
+	if minQty == 0 {
+		minQty = 1
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := repository.NewSupplierRepository(tx, s.log)
+
+	// satu item hanya punya satu supplier utama
+	if req.IsPreferred {
+		if err := repo.ClearPreferred(ctx, req.ItemID); err != nil {
+			return nil, err
+		}
+	}
+
+	link, err := repo.UpsertItem(ctx, &model.ItemSupplier{
+		ItemID:            req.ItemID,
+		SupplierID:        id,
+		SupplierSKU:       req.SupplierSKU,
+		LeadTimeDays:      req.LeadTimeDays,
+		MinOrderQuantity:  minQty,
+		LastPurchasePrice: req.LastPurchasePrice,
+		IsPreferred:       req.IsPreferred,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (s *supplierService) DeleteItem(ctx context.Context, usr *model.User, id, itemID int) error {
+	if !s.permSvc.CanUpdateMasterData(usr.Role) {
+		return errors.New("forbidden: cannot update supplier")
+	}
+
+	return s.repo.DeleteItem(ctx, id, itemID)
+}
+
+func (s *supplierService) ItemSuppliers(ctx context.Context, usr *model.User, itemID int) ([]model.ItemSupplier, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot access supplier")
+	}
+
+	if _, err := s.itemRepo.FindByID(ctx, itemID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.SuppliersByItem(ctx, itemID)
+}
+
+func buildSupplier(req dto.CreateSupplierRequest) *model.Supplier {
+	return &model.Supplier{
+		Code:             req.Code,
+		Name:             req.Name,
+		ContactPerson:    req.ContactPerson,
+		Phone:            req.Phone,
+		Email:            req.Email,
+		Address:          req.Address,
+		City:             req.City,
+		PaymentTermsDays: req.PaymentTermsDays,
+		Notes:            req.Notes,
+		IsActive:         true,
+	}
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"alfdwirhmn/inventory/database"
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/repository"
+	"alfdwirhmn/inventory/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type SupplierReturnService interface {
+	Create(ctx context.Context, usr *model.User, req dto.CreateSupplierReturnRequest) (*model.SupplierReturn, error)
+	FindAll(ctx context.Context, usr *model.User, filter dto.SupplierReturnFilter, page, limit int) ([]model.SupplierReturn, *dto.Pagination, error)
+	FindByID(ctx context.Context, usr *model.User, id int) (*model.SupplierReturn, error)
+
+	// catat kredit yang diterima dari supplier atas retur
+	AddCredit(ctx context.Context, usr *model.User, id int, req dto.AddSupplierCreditRequest) (*model.SupplierCredit, error)
+	// total kredit retur yang diharapkan, diterima & sisa per supplier
+	CreditSummary(ctx context.Context, usr *model.User, supplierID int) (*model.SupplierCreditSummary, error)
+}
+
+type supplierReturnService struct {
+	repo          repository.SupplierReturnRepository
+	receiptRepo   repository.GoodsReceiptRepository
+	poRepo        repository.PurchaseOrderRepository
+	supplierRepo  repository.SupplierRepository
+	itemRepo      repository.ItemsRepository
+	rackRepo      repository.RacksRepository
+	warehouseRepo repository.WarehouseRepository
+	returnNo      *numberSequence
+	costing       string
+	permSvc       PermissionService
+	txMgr         database.TxManager
+	log           *zap.Logger
+}
+
+func NewSupplierReturnService(
+	repo repository.SupplierReturnRepository,
+	receiptRepo repository.GoodsReceiptRepository,
+	poRepo repository.PurchaseOrderRepository,
+	supplierRepo repository.SupplierRepository,
+	itemRepo repository.ItemsRepository,
+	rackRepo repository.RacksRepository,
+	warehouseRepo repository.WarehouseRepository,
+	returnPattern string,
+	costing string,
+	permSvc PermissionService,
+	tx database.TxManager,
+	log *zap.Logger,
+) SupplierReturnService {
+	return &supplierReturnService{
+		repo:          repo,
+		receiptRepo:   receiptRepo,
+		poRepo:        poRepo,
+		supplierRepo:  supplierRepo,
+		itemRepo:      itemRepo,
+		rackRepo:      rackRepo,
+		warehouseRepo: warehouseRepo,
+		returnNo:      newNumberSequence(returnPattern, log),
+		costing:       costing,
+		permSvc:       permSvc,
+		txMgr:         tx,
+		log:           log,
+	}
+}
+
+func (s *supplierReturnService) Create(ctx context.Context, usr *model.User, req dto.CreateSupplierReturnRequest) (*model.SupplierReturn, error) {
+	if !s.permSvc.CanUpdateStock(usr.Role) {
+		return nil, errors.New("forbidden: cannot create supplier return")
+	}
+
+	rack, err := s.rackRepo.DetailById(req.RackID)
+	if err != nil || rack == nil || !rack.IsActive {
+		return nil, fmt.Errorf("rack %d not found or inactive", req.RackID)
+	}
+
+	warehouse, err := s.warehouseRepo.DetailById(rack.WarehouseID)
+	if err != nil || warehouse == nil {
+		return nil, errors.New("warehouse not found")
+	}
+
+	// retur dari GRN: supplier ikut PO, harga default ikut harga terima
+	supplierID := req.SupplierID
+	receiptLines := map[int]model.GoodsReceiptItem{}
+	if req.GoodsReceiptID != nil {
+		receipt, err := s.receiptRepo.FindByID(ctx, *req.GoodsReceiptID)
+		if err != nil {
+			return nil, err
+		}
+
+		po, err := s.poRepo.FindByID(ctx, receipt.PurchaseOrderID)
+		if err != nil {
+			return nil, err
+		}
+
+		if supplierID != 0 && supplierID != po.SupplierID {
+			return nil, errors.New("supplier does not match goods receipt supplier")
+		}
+		supplierID = po.SupplierID
+
+		for _, line := range receipt.Items {
+			receiptLines[line.ItemID] = line
+		}
+	}
+
+	supplier, err := s.supplierRepo.FindByID(ctx, supplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := s.supplierRepo.ItemsBySupplier(ctx, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	lastPrice := map[int]model.Money{}
+	for _, link := range links {
+		if link.LastPurchasePrice != nil {
+			lastPrice[link.ItemID] = *link.LastPurchasePrice
+		}
+	}
+
+	var credit model.Money
+	var itemIDs []int
+	seen := map[int]bool{}
+	lines := make([]model.SupplierReturnItem, 0, len(req.Items))
+	for _, it := range req.Items {
+		if seen[it.ItemID] {
+			return nil, fmt.Errorf("item %d listed more than once", it.ItemID)
+		}
+		seen[it.ItemID] = true
+
+		item, err := s.itemRepo.FindByID(ctx, it.ItemID)
+		if err != nil {
+			return nil, fmt.Errorf("item %d not found", it.ItemID)
+		}
+
+		line := model.SupplierReturnItem{
+			ItemID:   it.ItemID,
+			SKU:      item.SKU,
+			Name:     item.Name,
+			Quantity: it.Quantity,
+			UnitCost: item.Cost,
+		}
+
+		if req.GoodsReceiptID != nil {
+			received, ok := receiptLines[it.ItemID]
+			if !ok {
+				return nil, fmt.Errorf("item %s is not part of this goods receipt", item.SKU)
+			}
+			if it.Quantity > received.Quantity-received.ReturnedQuantity {
+				return nil, fmt.Errorf("item %s: only %d left to return", item.SKU, received.Quantity-received.ReturnedQuantity)
+			}
+			line.GoodsReceiptItemID = &received.ID
+			line.UnitCost = received.UnitCost
+		} else if price, ok := lastPrice[it.ItemID]; ok {
+			line.UnitCost = price
+		}
+
+		if it.UnitCost != nil {
+			if *it.UnitCost < 0 {
+				return nil, fmt.Errorf("item %s: unit cost cannot be negative", item.SKU)
+			}
+			line.UnitCost = *it.UnitCost
+		}
+
+		line.CreditAmount = line.UnitCost.Mul(it.Quantity)
+		credit += line.CreditAmount
+
+		lines = append(lines, line)
+		itemIDs = append(itemIDs, it.ItemID)
+	}
+
+	returnDate := time.Now()
+	if req.ReturnDate != nil {
+		returnDate = *req.ReturnDate
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := repository.NewSupplierReturnRepository(tx, s.log)
+	receiptRepo := repository.NewGoodsReceiptRepository(tx, s.log)
+	ledger := newStockLedger(tx, s.log, s.costing)
+
+	if _, err := ledger.Lock(ctx, itemIDs...); err != nil {
+		return nil, err
+	}
+
+	number, err := s.returnNo.Next(ctx, tx, warehouse.Code, returnDate)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, err := repo.Create(ctx, &model.SupplierReturn{
+		ReturnNumber:   number,
+		SupplierID:     supplier.ID,
+		GoodsReceiptID: req.GoodsReceiptID,
+		RackID:         req.RackID,
+		ReturnDate:     returnDate,
+		Reason:         req.Reason,
+		Notes:          req.Notes,
+		CreditAmount:   credit,
+		CreatedBy:      usr.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notes := "supplier return " + sr.ReturnNumber
+	for i := range lines {
+		line := &lines[i]
+
+		// stok keluar dari rak yang dipilih, nilai persediaan mengikuti metode costing
+		err := ledger.Post(ctx, &model.StockMovement{
+			ItemID:       line.ItemID,
+			RackID:       req.RackID,
+			MovementType: model.MovementSupplierReturn,
+			Quantity:     -line.Quantity,
+			ReferenceID:  &sr.ID,
+			Notes:        &notes,
+			CreatedBy:    &usr.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("item %s: %w", line.SKU, err)
+		}
+
+		// dikunci ulang di transaksi supaya dua retur bersamaan tidak melebihi qty terima
+		if line.GoodsReceiptItemID != nil {
+			if err := receiptRepo.AddReturned(ctx, *line.GoodsReceiptItemID, line.Quantity); err != nil {
+				return nil, fmt.Errorf("item %s: %w", line.SKU, err)
+			}
+		}
+
+		line.SupplierReturnID = sr.ID
+		if err := repo.CreateItem(ctx, line); err != nil {
+			return nil, err
+		}
+	}
+	sr.Items = lines
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return sr, nil
+}
+
+func (s *supplierReturnService) FindAll(ctx context.Context, usr *model.User, filter dto.SupplierReturnFilter, page, limit int) ([]model.SupplierReturn, *dto.Pagination, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, nil, errors.New("forbidden: cannot access supplier returns")
+	}
+
+	returns, total, err := s.repo.Lists(ctx, filter, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: utils.TotalPage(limit, int64(total)),
+		TotalRows:  total,
+	}
+
+	return returns, pagination, nil
+}
+
+func (s *supplierReturnService) FindByID(ctx context.Context, usr *model.User, id int) (*model.SupplierReturn, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot access supplier returns")
+	}
+
+	sr, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sr.Items, err = s.repo.FindItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sr.Credits, err = s.repo.FindCredits(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return sr, nil
+}
+
+func (s *supplierReturnService) AddCredit(ctx context.Context, usr *model.User, id int, req dto.AddSupplierCreditRequest) (*model.SupplierCredit, error) {
+	if !s.permSvc.CanApprovePurchase(usr.Role) {
+		return nil, errors.New("forbidden: cannot record supplier credit")
+	}
+
+	creditedAt := time.Now()
+	if req.CreditedAt != nil {
+		creditedAt = *req.CreditedAt
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := repository.NewSupplierReturnRepository(tx, s.log)
+
+	sr, err := repo.FindByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if outstanding := sr.CreditAmount - sr.CreditedAmount; req.Amount > outstanding {
+		return nil, fmt.Errorf("credit exceeds outstanding return credit (%s)", outstanding)
+	}
+
+	credit, err := repo.CreateCredit(ctx, &model.SupplierCredit{
+		SupplierReturnID: id,
+		Amount:           req.Amount,
+		Reference:        req.Reference,
+		CreditedAt:       creditedAt,
+		Notes:            req.Notes,
+		CreatedBy:        usr.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repo.AddCredited(ctx, id, req.Amount); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return credit, nil
+}
+
+func (s *supplierReturnService) CreditSummary(ctx context.Context, usr *model.User, supplierID int) (*model.SupplierCreditSummary, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot access supplier returns")
+	}
+
+	if _, err := s.supplierRepo.FindByID(ctx, supplierID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreditSummary(ctx, supplierID)
+}
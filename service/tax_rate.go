@@ -0,0 +1,95 @@
+package service
+
+import (
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/repository"
+	"alfdwirhmn/inventory/utils"
+	"context"
+	"errors"
+)
+
+type TaxRateService interface {
+	Create(ctx context.Context, usr *model.User, req dto.CreateTaxRateRequest) (*model.TaxRate, error)
+	FindAll(ctx context.Context, page, limit int) (*[]model.TaxRate, *dto.Pagination, error)
+	FindByID(ctx context.Context, usr *model.User, id int) (*model.TaxRate, error)
+	Update(ctx context.Context, usr *model.User, id int, req dto.UpdateTaxRateRequest) (*model.TaxRate, error)
+	Delete(ctx context.Context, usr *model.User, id int) error
+}
+
+type taxRateService struct {
+	repo    repository.TaxRateRepository
+	permSvc PermissionService
+}
+
+func NewTaxRateService(repo repository.TaxRateRepository, permSvc PermissionService) TaxRateService {
+	return &taxRateService{
+		repo:    repo,
+		permSvc: permSvc,
+	}
+}
+
+func (s *taxRateService) Create(ctx context.Context, usr *model.User, req dto.CreateTaxRateRequest) (*model.TaxRate, error) {
+	if !s.permSvc.CanCreateMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot create tax rate")
+	}
+
+	createdBy := usr.ID
+
+	return s.repo.Create(ctx, &model.TaxRate{
+		Code:        req.Code,
+		Name:        req.Name,
+		Rate:        req.Rate,
+		IsInclusive: req.IsInclusive,
+		IsActive:    true,
+		CreatedBy:   &createdBy,
+	})
+}
+
+func (s *taxRateService) FindAll(ctx context.Context, page, limit int) (*[]model.TaxRate, *dto.Pagination, error) {
+	rates, total, err := s.repo.Lists(ctx, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := dto.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: utils.TotalPage(limit, int64(total)),
+		TotalRows:  total,
+	}
+
+	return &rates, &pagination, nil
+}
+
+func (s *taxRateService) FindByID(ctx context.Context, usr *model.User, id int) (*model.TaxRate, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot access tax rate")
+	}
+
+	return s.repo.FindByID(ctx, id)
+}
+
+// perubahan rate hanya berlaku untuk sale berikutnya, sale lama menyimpan snapshot rate
+func (s *taxRateService) Update(ctx context.Context, usr *model.User, id int, req dto.UpdateTaxRateRequest) (*model.TaxRate, error) {
+	if !s.permSvc.CanUpdateMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot update tax rate")
+	}
+
+	return s.repo.Update(ctx, &model.TaxRate{
+		ID:          id,
+		Code:        req.Code,
+		Name:        req.Name,
+		Rate:        req.Rate,
+		IsInclusive: req.IsInclusive,
+		IsActive:    req.IsActive,
+	})
+}
+
+func (s *taxRateService) Delete(ctx context.Context, usr *model.User, id int) error {
+	if !s.permSvc.CanDeleteMasterData(usr.Role) {
+		return errors.New("forbidden: cannot delete tax rate")
+	}
+
+	return s.repo.Delete(ctx, id)
+}
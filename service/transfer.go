@@ -0,0 +1,461 @@
+package service
+
+import (
+	"alfdwirhmn/inventory/database"
+	"alfdwirhmn/inventory/dto"
+	"alfdwirhmn/inventory/model"
+	"alfdwirhmn/inventory/repository"
+	"alfdwirhmn/inventory/utils"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+type TransferService interface {
+	Create(ctx context.Context, usr *model.User, req dto.CreateTransferRequest) (*model.StockTransfer, error)
+	FindAll(ctx context.Context, usr *model.User, filter dto.TransferFilter, page, limit int) ([]model.StockTransfer, *dto.Pagination, error)
+	FindByID(ctx context.Context, usr *model.User, id int) (*model.StockTransfer, error)
+
+	// draft -> in_transit -> (partially_received) -> received / closed
+	Ship(ctx context.Context, usr *model.User, id int) (*model.StockTransfer, error)
+	Receive(ctx context.Context, usr *model.User, id int, req dto.ReceiveTransferRequest) (*model.StockTransfer, error)
+	Close(ctx context.Context, usr *model.User, id int, req dto.CloseTransferRequest) (*model.StockTransfer, error)
+	Cancel(ctx context.Context, usr *model.User, id int) (*model.StockTransfer, error)
+}
+
+type transferService struct {
+	repo     repository.TransferRepository
+	rackRepo repository.RacksRepository
+	itemRepo repository.ItemsRepository
+	costing  string
+	txMgr    database.TxManager
+	permSvc  PermissionService
+	log      *zap.Logger
+}
+
+func NewTransferService(repo repository.TransferRepository, rackRepo repository.RacksRepository, itemRepo repository.ItemsRepository, costing string, permSvc PermissionService, tx database.TxManager, log *zap.Logger) TransferService {
+	return &transferService{
+		repo:     repo,
+		rackRepo: rackRepo,
+		itemRepo: itemRepo,
+		costing:  costing,
+		txMgr:    tx,
+		permSvc:  permSvc,
+		log:      log,
+	}
+}
+
+func (s *transferService) Create(ctx context.Context, usr *model.User, req dto.CreateTransferRequest) (*model.StockTransfer, error) {
+	if !s.permSvc.CanUpdateStock(usr.Role) {
+		return nil, errors.New("forbidden: cannot create transfer")
+	}
+
+	// rak asal & tujuan harus aktif
+	for _, rackID := range []int{req.SourceRackID, req.DestinationRackID} {
+		rack, err := s.rackRepo.DetailById(rackID)
+		if err != nil || rack == nil || !rack.IsActive {
+			return nil, fmt.Errorf("rack %d not found or inactive", rackID)
+		}
+	}
+
+	seen := map[int]bool{}
+	for _, it := range req.Items {
+		if seen[it.ItemID] {
+			return nil, fmt.Errorf("item %d listed more than once", it.ItemID)
+		}
+		seen[it.ItemID] = true
+
+		item, err := s.itemRepo.FindByID(ctx, it.ItemID)
+		if err != nil || !item.IsActive {
+			return nil, fmt.Errorf("item %d not found or inactive", it.ItemID)
+		}
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := repository.NewTransferRepository(tx, s.log)
+
+	transfer, err := repo.Create(ctx, &model.StockTransfer{
+		SourceRackID:      req.SourceRackID,
+		DestinationRackID: req.DestinationRackID,
+		Notes:             req.Notes,
+		CreatedBy:         usr.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, it := range req.Items {
+		err := repo.CreateItem(ctx, &model.StockTransferItem{
+			TransferID: transfer.ID,
+			ItemID:     it.ItemID,
+			Quantity:   it.Quantity,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	transfer.Items, err = repo.FindItems(ctx, transfer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (s *transferService) FindAll(ctx context.Context, usr *model.User, filter dto.TransferFilter, page, limit int) ([]model.StockTransfer, *dto.Pagination, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, nil, errors.New("forbidden: cannot access transfers")
+	}
+
+	transfers, total, err := s.repo.Lists(ctx, filter, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: utils.TotalPage(limit, int64(total)),
+		TotalRows:  total,
+	}
+
+	return transfers, pagination, nil
+}
+
+func (s *transferService) FindByID(ctx context.Context, usr *model.User, id int) (*model.StockTransfer, error) {
+	if !s.permSvc.CanReadMasterData(usr.Role) {
+		return nil, errors.New("forbidden: cannot access transfers")
+	}
+
+	transfer, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer.Items, err = s.repo.FindItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (s *transferService) Ship(ctx context.Context, usr *model.User, id int) (*model.StockTransfer, error) {
+	if !s.permSvc.CanUpdateStock(usr.Role) {
+		return nil, errors.New("forbidden: cannot ship transfer")
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := repository.NewTransferRepository(tx, s.log)
+	ledger := newStockLedger(tx, s.log, s.costing)
+
+	transfer, err := repo.FindByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.Status != model.TransferDraft {
+		return nil, fmt.Errorf("transfer is %s, only draft transfer can be shipped", transfer.Status)
+	}
+
+	lines, err := repo.FindItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := ledger.Lock(ctx, transferItemIDs(lines)...); err != nil {
+		return nil, err
+	}
+
+	// stok keluar dari rak asal
+	notes := "transfer out"
+	for _, line := range lines {
+		err := ledger.Post(ctx, &model.StockMovement{
+			ItemID:       line.ItemID,
+			RackID:       transfer.SourceRackID,
+			MovementType: model.MovementTransfer,
+			Quantity:     -line.Quantity,
+			ReferenceID:  &transfer.ID,
+			Notes:        &notes,
+			CreatedBy:    &usr.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("item %s: %w", line.SKU, err)
+		}
+	}
+
+	if err := repo.MarkShipped(ctx, id, usr.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(ctx, usr, id)
+}
+
+func (s *transferService) Receive(ctx context.Context, usr *model.User, id int, req dto.ReceiveTransferRequest) (*model.StockTransfer, error) {
+	if !s.permSvc.CanUpdateStock(usr.Role) {
+		return nil, errors.New("forbidden: cannot receive transfer")
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := repository.NewTransferRepository(tx, s.log)
+	movementRepo := repository.NewStockMovementRepository(tx, s.log)
+	ledger := newStockLedger(tx, s.log, s.costing)
+
+	transfer, err := repo.FindByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.Status != model.TransferInTransit && transfer.Status != model.TransferPartiallyReceived {
+		return nil, fmt.Errorf("transfer is %s, only in transit transfer can be received", transfer.Status)
+	}
+
+	lines, err := repo.FindItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	inTransfer := map[int]bool{}
+	for _, line := range lines {
+		inTransfer[line.ItemID] = true
+	}
+
+	// qty yang diterima per item, default semua sisa
+	receive := map[int]int{}
+	if len(req.Items) == 0 {
+		for _, line := range lines {
+			receive[line.ItemID] = line.Quantity - line.ReceivedQuantity
+		}
+	}
+	for _, it := range req.Items {
+		if !inTransfer[it.ItemID] {
+			return nil, fmt.Errorf("item %d is not part of this transfer", it.ItemID)
+		}
+		receive[it.ItemID] += it.Quantity
+	}
+
+	if _, err := ledger.Lock(ctx, transferItemIDs(lines)...); err != nil {
+		return nil, err
+	}
+
+	notes := "transfer in"
+	complete := true
+	for _, line := range lines {
+		qty := receive[line.ItemID]
+
+		remaining := line.Quantity - line.ReceivedQuantity
+		if qty > remaining {
+			return nil, fmt.Errorf("item %s: only %d left to receive", line.SKU, remaining)
+		}
+		if qty < remaining {
+			complete = false
+		}
+		if qty == 0 {
+			continue
+		}
+
+		// nilai masuk mengikuti nilai saat dikirim, kumulatif supaya total diterima = total dikirim
+		shipped, err := movementRepo.OutgoingCost(ctx, model.MovementTransfer, transfer.ID, line.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		cost := shipped.MulRatio(int64(line.ReceivedQuantity+qty), int64(line.Quantity)) -
+			shipped.MulRatio(int64(line.ReceivedQuantity), int64(line.Quantity))
+
+		// stok masuk ke rak tujuan
+		err = ledger.Post(ctx, &model.StockMovement{
+			ItemID:       line.ItemID,
+			RackID:       transfer.DestinationRackID,
+			MovementType: model.MovementTransfer,
+			Quantity:     qty,
+			TotalCost:    cost,
+			ReferenceID:  &transfer.ID,
+			Notes:        &notes,
+			CreatedBy:    &usr.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := repo.AddReceived(ctx, line.ID, qty); err != nil {
+			return nil, err
+		}
+	}
+
+	status := model.TransferPartiallyReceived
+	if complete {
+		status = model.TransferReceived
+	}
+	if err := repo.UpdateStatus(ctx, id, status); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(ctx, usr, id)
+}
+
+// Close menutup transfer yang sebagian sudah diterima. qty yang tidak sampai dikembalikan
+// dulu ke rak asal dengan nilai saat dikirim, lalu dikeluarkan lagi sebagai adjustment
+// dengan nilai yang sama supaya kehilangannya tercatat di rak asal dan stok tidak berubah.
+func (s *transferService) Close(ctx context.Context, usr *model.User, id int, req dto.CloseTransferRequest) (*model.StockTransfer, error) {
+	if !s.permSvc.CanUpdateStock(usr.Role) {
+		return nil, errors.New("forbidden: cannot close transfer")
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := repository.NewTransferRepository(tx, s.log)
+	movementRepo := repository.NewStockMovementRepository(tx, s.log)
+	ledger := newStockLedger(tx, s.log, s.costing)
+
+	transfer, err := repo.FindByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// transfer yang belum diterima sama sekali juga sudah pernah keluar stok, tetap boleh ditutup
+	if transfer.Status != model.TransferInTransit && transfer.Status != model.TransferPartiallyReceived {
+		return nil, fmt.Errorf("transfer is %s, only in transit transfer can be closed", transfer.Status)
+	}
+
+	lines, err := repo.FindItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := ledger.Lock(ctx, transferItemIDs(lines)...); err != nil {
+		return nil, err
+	}
+
+	notes := fmt.Sprintf("transfer %d closed short", transfer.ID)
+	if req.Notes != nil && *req.Notes != "" {
+		notes += ": " + *req.Notes
+	}
+
+	for _, line := range lines {
+		missing := line.Quantity - line.ReceivedQuantity
+		if missing == 0 {
+			continue
+		}
+
+		// nilai sisa yang masih di perjalanan, sama dengan cara Receive menilai qty masuk
+		shipped, err := movementRepo.OutgoingCost(ctx, model.MovementTransfer, transfer.ID, line.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		cost := shipped - shipped.MulRatio(int64(line.ReceivedQuantity), int64(line.Quantity))
+
+		err = ledger.Post(ctx, &model.StockMovement{
+			ItemID:       line.ItemID,
+			RackID:       transfer.SourceRackID,
+			MovementType: model.MovementTransfer,
+			Quantity:     missing,
+			TotalCost:    cost,
+			ReferenceID:  &transfer.ID,
+			Notes:        &notes,
+			CreatedBy:    &usr.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("item %s: %w", line.SKU, err)
+		}
+
+		err = ledger.Post(ctx, &model.StockMovement{
+			ItemID:       line.ItemID,
+			RackID:       transfer.SourceRackID,
+			MovementType: model.MovementAdjustment,
+			Quantity:     -missing,
+			TotalCost:    -cost, // nilai sama dengan yang dikembalikan, layer ikut berkurang senilai ini
+			Reason:       &req.Reason,
+			Notes:        &notes,
+			CreatedBy:    &usr.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("item %s: %w", line.SKU, err)
+		}
+	}
+
+	if err := repo.UpdateStatus(ctx, id, model.TransferClosed); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(ctx, usr, id)
+}
+
+func (s *transferService) Cancel(ctx context.Context, usr *model.User, id int) (*model.StockTransfer, error) {
+	if !s.permSvc.CanUpdateStock(usr.Role) {
+		return nil, errors.New("forbidden: cannot cancel transfer")
+	}
+
+	tx, err := s.txMgr.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	repo := repository.NewTransferRepository(tx, s.log)
+
+	transfer, err := repo.FindByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// stok belum bergerak selama masih draft
+	if transfer.Status != model.TransferDraft {
+		return nil, fmt.Errorf("transfer is %s, only draft transfer can be cancelled", transfer.Status)
+	}
+
+	if err := repo.UpdateStatus(ctx, id, model.TransferCancelled); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(ctx, usr, id)
+}
+
+func transferItemIDs(lines []model.StockTransferItem) []int {
+	ids := make([]int, 0, len(lines))
+	for _, line := range lines {
+		ids = append(ids, line.ItemID)
+	}
+	return ids
+}
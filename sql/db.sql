@@ -1,10 +1,35 @@
 -- Drop tables if exists (untuk development)
+DROP TABLE IF EXISTS notifications CASCADE;
+DROP TABLE IF EXISTS stock_transfer_items CASCADE;
+DROP TABLE IF EXISTS supplier_credits CASCADE;
+DROP TABLE IF EXISTS supplier_return_items CASCADE;
+DROP TABLE IF EXISTS supplier_returns CASCADE;
+DROP TABLE IF EXISTS goods_receipt_items CASCADE;
+DROP TABLE IF EXISTS goods_receipts CASCADE;
+DROP TABLE IF EXISTS purchase_order_items CASCADE;
+DROP TABLE IF EXISTS purchase_orders CASCADE;
+DROP TABLE IF EXISTS stock_transfers CASCADE;
+DROP TABLE IF EXISTS cost_layers CASCADE;
+DROP TABLE IF EXISTS stock_movements CASCADE;
+DROP TABLE IF EXISTS item_locations CASCADE;
+DROP TABLE IF EXISTS number_sequences CASCADE;
+DROP TABLE IF EXISTS sale_payments CASCADE;
+DROP TABLE IF EXISTS sale_return_items CASCADE;
+DROP TABLE IF EXISTS sale_returns CASCADE;
 DROP TABLE IF EXISTS sale_items CASCADE;
+DROP TABLE IF EXISTS promotions CASCADE;
+DROP TABLE IF EXISTS item_price_history CASCADE;
+DROP TABLE IF EXISTS item_suppliers CASCADE;
+DROP TABLE IF EXISTS suppliers CASCADE;
 DROP TABLE IF EXISTS sales CASCADE;
+DROP TABLE IF EXISTS customers CASCADE;
+DROP TABLE IF EXISTS price_list_items CASCADE;
+DROP TABLE IF EXISTS price_lists CASCADE;
 DROP TABLE IF EXISTS items CASCADE;
 DROP TABLE IF EXISTS racks CASCADE;
 DROP TABLE IF EXISTS warehouses CASCADE;
 DROP TABLE IF EXISTS categories CASCADE;
+DROP TABLE IF EXISTS tax_rates CASCADE;
 DROP TABLE IF EXISTS sessions CASCADE;
 DROP TABLE IF EXISTS users CASCADE;
 
@@ -48,6 +73,22 @@ CREATE INDEX idx_sessions_token ON sessions(token);
 CREATE INDEX idx_sessions_user_id ON sessions(user_id);
 CREATE INDEX idx_sessions_expired_at ON sessions(expired_at);
 
+-- =====================================================
+-- TABLE: tax_rates
+-- =====================================================
+-- rate dalam persen (11.00 = PPN 11%), rate 0 untuk barang bebas pajak
+CREATE TABLE tax_rates (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    rate DECIMAL(5,2) NOT NULL CHECK (rate >= 0 AND rate <= 100),
+    is_inclusive BOOLEAN NOT NULL DEFAULT false, -- true = harga jual sudah termasuk pajak
+    is_active BOOLEAN DEFAULT true,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 -- =====================================================
 -- TABLE: categories
 -- =====================================================
@@ -56,6 +97,7 @@ CREATE TABLE categories (
     code VARCHAR(20) UNIQUE NOT NULL,
     name VARCHAR(100) UNIQUE NOT NULL,
     description TEXT,
+    tax_rate_id INTEGER REFERENCES tax_rates(id) ON DELETE SET NULL,
     is_active BOOLEAN DEFAULT true,
     created_by INTEGER REFERENCES users(id),
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -114,6 +156,7 @@ CREATE TABLE items (
     id SERIAL PRIMARY KEY,
     category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE RESTRICT,
     rack_id INTEGER REFERENCES racks(id) ON DELETE SET NULL,
+    tax_rate_id INTEGER REFERENCES tax_rates(id) ON DELETE SET NULL, -- kosong = ikut kategori
     sku VARCHAR(50) UNIQUE NOT NULL,
     name VARCHAR(200) NOT NULL,
     description TEXT,
@@ -137,12 +180,186 @@ CREATE INDEX idx_items_is_active ON items(is_active);
 CREATE INDEX idx_items_stock ON items(stock);
 CREATE INDEX idx_items_minimum_stock ON items(stock, minimum_stock);
 
+-- =====================================================
+-- TABLE: suppliers
+-- =====================================================
+CREATE TABLE suppliers (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    contact_person VARCHAR(100),
+    phone VARCHAR(20),
+    email VARCHAR(100),
+    address TEXT,
+    city VARCHAR(50),
+    payment_terms_days INTEGER NOT NULL DEFAULT 0 CHECK (payment_terms_days >= 0), -- tempo pembayaran
+    notes TEXT,
+    is_active BOOLEAN DEFAULT true,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_suppliers_name ON suppliers(name);
+CREATE INDEX idx_suppliers_is_active ON suppliers(is_active);
+
+-- =====================================================
+-- TABLE: item_suppliers
+-- =====================================================
+-- supplier per item, maksimal satu supplier utama (is_preferred) per item
+CREATE TABLE item_suppliers (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(id) ON DELETE CASCADE,
+    supplier_sku VARCHAR(50),
+    lead_time_days INTEGER NOT NULL DEFAULT 0 CHECK (lead_time_days >= 0),
+    min_order_quantity INTEGER NOT NULL DEFAULT 1 CHECK (min_order_quantity > 0),
+    last_purchase_price DECIMAL(15,2) CHECK (last_purchase_price >= 0),
+    last_purchased_at TIMESTAMP,
+    is_preferred BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(item_id, supplier_id)
+);
+
+CREATE INDEX idx_item_suppliers_supplier_id ON item_suppliers(supplier_id);
+CREATE UNIQUE INDEX idx_item_suppliers_preferred ON item_suppliers(item_id) WHERE is_preferred;
+
+-- =====================================================
+-- TABLE: item_price_history
+-- =====================================================
+-- setiap perubahan harga / cost item. new_* kosong = tidak berubah,
+-- old_* diisi saat perubahan diterapkan. scheduled diterapkan otomatis saat effective_at lewat
+CREATE TABLE item_price_history (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    old_price DECIMAL(15,2),
+    new_price DECIMAL(15,2) CHECK (new_price >= 0),
+    old_cost DECIMAL(15,2),
+    new_cost DECIMAL(15,2) CHECK (new_cost >= 0),
+    effective_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    status VARCHAR(20) NOT NULL DEFAULT 'applied' CHECK (status IN ('scheduled', 'applied', 'cancelled')),
+    reason TEXT,
+    applied_at TIMESTAMP,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (new_price IS NOT NULL OR new_cost IS NOT NULL)
+);
+
+CREATE INDEX idx_item_price_history_item_id ON item_price_history(item_id, effective_at DESC);
+CREATE INDEX idx_item_price_history_scheduled ON item_price_history(effective_at) WHERE status = 'scheduled';
+
+-- =====================================================
+-- TABLE: promotions
+-- =====================================================
+-- percentage: value = persen, fixed: value = potongan per unit,
+-- buy_x_get_y: tiap buy_quantity + get_quantity unit, get_quantity unit gratis.
+-- item_id / category_id kosong = berlaku untuk semua item
+CREATE TABLE promotions (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(30) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    promo_type VARCHAR(20) NOT NULL CHECK (promo_type IN ('percentage', 'fixed', 'buy_x_get_y')),
+    value DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (value >= 0),
+    buy_quantity INTEGER CHECK (buy_quantity > 0),
+    get_quantity INTEGER CHECK (get_quantity > 0),
+    item_id INTEGER REFERENCES items(id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES categories(id) ON DELETE CASCADE,
+    starts_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ends_at TIMESTAMP,
+    usage_limit INTEGER CHECK (usage_limit > 0),
+    usage_count INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN DEFAULT true,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (item_id IS NULL OR category_id IS NULL),
+    CHECK (ends_at IS NULL OR ends_at > starts_at),
+    CHECK (usage_limit IS NULL OR usage_count <= usage_limit)
+);
+
+CREATE INDEX idx_promotions_item_id ON promotions(item_id);
+CREATE INDEX idx_promotions_category_id ON promotions(category_id);
+CREATE INDEX idx_promotions_active_window ON promotions(is_active, starts_at, ends_at);
+
+-- =====================================================
+-- TABLE: item_locations
+-- =====================================================
+-- stok per rak, items.stock = SUM(quantity) per item
+CREATE TABLE item_locations (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    rack_id INTEGER NOT NULL REFERENCES racks(id) ON DELETE RESTRICT,
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (item_id, rack_id)
+);
+
+CREATE INDEX idx_item_locations_rack_id ON item_locations(rack_id);
+
+-- database lama yang sudah berisi stok: jalankan migrations/20261018_item_locations.sql
+
+-- =====================================================
+-- TABLE: price_lists
+-- =====================================================
+-- daftar harga bernama (retail, grosir, reseller), dipasang ke customer atau dipilih per sale
+CREATE TABLE price_lists (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    is_active BOOLEAN DEFAULT true,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- =====================================================
+-- TABLE: price_list_items
+-- =====================================================
+-- harga per item, min_quantity untuk harga bertingkat (qty >= min_quantity)
+CREATE TABLE price_list_items (
+    id SERIAL PRIMARY KEY,
+    price_list_id INTEGER NOT NULL REFERENCES price_lists(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    min_quantity INTEGER NOT NULL DEFAULT 1 CHECK (min_quantity > 0),
+    price DECIMAL(15,2) NOT NULL CHECK (price >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(price_list_id, item_id, min_quantity)
+);
+
+CREATE INDEX idx_price_list_items_item_id ON price_list_items(item_id);
+
+-- =====================================================
+-- TABLE: customers
+-- =====================================================
+CREATE TABLE customers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    phone VARCHAR(20) UNIQUE,
+    email VARCHAR(100) UNIQUE,
+    address TEXT,
+    notes TEXT,
+    price_list_id INTEGER REFERENCES price_lists(id) ON DELETE SET NULL,
+    is_active BOOLEAN DEFAULT true,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_customers_name ON customers(name);
+CREATE INDEX idx_customers_is_active ON customers(is_active);
+
 -- =====================================================
 -- TABLE: sales
 -- =====================================================
 CREATE TABLE sales (
     id SERIAL PRIMARY KEY,
     invoice_number VARCHAR(50) UNIQUE NOT NULL,
+    warehouse_id INTEGER REFERENCES warehouses(id) ON DELETE RESTRICT,
+    customer_id INTEGER REFERENCES customers(id) ON DELETE RESTRICT,
     customer_name VARCHAR(100),
     customer_phone VARCHAR(20),
     customer_email VARCHAR(100),
@@ -152,8 +369,11 @@ CREATE TABLE sales (
     tax DECIMAL(15,2) DEFAULT 0 CHECK (tax >= 0),
     grand_total DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (grand_total >= 0),
     payment_method VARCHAR(50),
-    payment_status VARCHAR(20) DEFAULT 'pending' CHECK (payment_status IN ('pending', 'paid', 'cancelled')),
+    payment_status VARCHAR(20) DEFAULT 'pending' CHECK (payment_status IN ('pending', 'partially_paid', 'paid', 'cancelled')),
     notes TEXT,
+    cancelled_by INTEGER REFERENCES users(id),
+    cancelled_at TIMESTAMP,
+    cancel_reason TEXT,
     created_by INTEGER NOT NULL REFERENCES users(id),
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
@@ -163,6 +383,20 @@ CREATE INDEX idx_sales_invoice_number ON sales(invoice_number);
 CREATE INDEX idx_sales_sale_date ON sales(sale_date);
 CREATE INDEX idx_sales_created_by ON sales(created_by);
 CREATE INDEX idx_sales_payment_status ON sales(payment_status);
+CREATE INDEX idx_sales_warehouse_id ON sales(warehouse_id);
+CREATE INDEX idx_sales_customer_id ON sales(customer_id);
+
+-- =====================================================
+-- TABLE: number_sequences
+-- =====================================================
+-- nomor urut dokumen (invoice, dll) per prefix & periode
+CREATE TABLE number_sequences (
+    prefix VARCHAR(100) NOT NULL,
+    period VARCHAR(8) NOT NULL DEFAULT '',
+    last_value INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (prefix, period)
+);
 
 -- =====================================================
 -- TABLE: sale_items
@@ -175,12 +409,312 @@ CREATE TABLE sale_items (
     unit_price DECIMAL(15,2) NOT NULL CHECK (unit_price >= 0),
     subtotal DECIMAL(15,2) NOT NULL CHECK (subtotal >= 0),
     discount DECIMAL(15,2) DEFAULT 0 CHECK (discount >= 0),
+    tax_rate_id INTEGER REFERENCES tax_rates(id) ON DELETE SET NULL,
+    tax_rate DECIMAL(5,2) NOT NULL DEFAULT 0,
+    tax_inclusive BOOLEAN NOT NULL DEFAULT false,
+    tax_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (tax_amount >= 0),
+    price_list_id INTEGER REFERENCES price_lists(id) ON DELETE SET NULL,
+    promotion_id INTEGER REFERENCES promotions(id) ON DELETE SET NULL,
+    promotion_discount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (promotion_discount >= 0),
+    cogs DECIMAL(15,2) NOT NULL DEFAULT 0, -- harga pokok baris (FIFO / average)
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
 CREATE INDEX idx_sale_items_sale_id ON sale_items(sale_id);
 CREATE INDEX idx_sale_items_item_id ON sale_items(item_id);
 
+-- =====================================================
+-- TABLE: sale_payments
+-- =====================================================
+-- satu sale bisa dibayar beberapa kali / beberapa metode
+CREATE TABLE sale_payments (
+    id SERIAL PRIMARY KEY,
+    sale_id INTEGER NOT NULL REFERENCES sales(id) ON DELETE RESTRICT,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    payment_method VARCHAR(50) NOT NULL,
+    reference VARCHAR(100),
+    paid_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    voided_at TIMESTAMP,
+    voided_by INTEGER REFERENCES users(id),
+    void_reason TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sale_payments_sale_id ON sale_payments(sale_id);
+
+-- =====================================================
+-- TABLE: sale_returns
+-- =====================================================
+-- retur penjualan sekaligus catatan refund ke customer
+CREATE TABLE sale_returns (
+    id SERIAL PRIMARY KEY,
+    sale_id INTEGER NOT NULL REFERENCES sales(id) ON DELETE RESTRICT,
+    refund_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (refund_amount >= 0),
+    refund_method VARCHAR(50),
+    reason TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sale_returns_sale_id ON sale_returns(sale_id);
+
+-- =====================================================
+-- TABLE: sale_return_items
+-- =====================================================
+CREATE TABLE sale_return_items (
+    id SERIAL PRIMARY KEY,
+    return_id INTEGER NOT NULL REFERENCES sale_returns(id) ON DELETE CASCADE,
+    sale_item_id INTEGER NOT NULL REFERENCES sale_items(id) ON DELETE RESTRICT,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    rack_id INTEGER NOT NULL REFERENCES racks(id) ON DELETE RESTRICT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    refund_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (refund_amount >= 0)
+);
+
+CREATE INDEX idx_sale_return_items_return_id ON sale_return_items(return_id);
+CREATE INDEX idx_sale_return_items_sale_item_id ON sale_return_items(sale_item_id);
+
+-- =====================================================
+-- TABLE: stock_movements
+-- =====================================================
+CREATE TABLE stock_movements (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    rack_id INTEGER NOT NULL REFERENCES racks(id) ON DELETE RESTRICT,
+    movement_type VARCHAR(20) NOT NULL CHECK (movement_type IN ('sale', 'adjustment', 'receipt', 'transfer', 'return', 'cancellation', 'supplier_return')),
+    quantity INTEGER NOT NULL CHECK (quantity <> 0),
+    stock_after INTEGER NOT NULL CHECK (stock_after >= 0),
+    reason VARCHAR(30) CHECK (reason IN ('damage', 'count_correction', 'found', 'expired', 'lost', 'theft', 'other')),
+    reference_id INTEGER,
+    notes TEXT,
+    unit_cost DECIMAL(15,2) NOT NULL DEFAULT 0,
+    total_cost DECIMAL(15,2) NOT NULL DEFAULT 0, -- nilai persediaan bertanda, sama dengan tanda quantity
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_movements_item_id ON stock_movements(item_id);
+CREATE INDEX idx_stock_movements_rack_id ON stock_movements(rack_id);
+CREATE INDEX idx_stock_movements_reference ON stock_movements(movement_type, reference_id);
+CREATE INDEX idx_stock_movements_created_at ON stock_movements(created_at);
+
+-- =====================================================
+-- TABLE: cost_layers
+-- =====================================================
+-- lapisan biaya per barang masuk, dihabiskan urut received_at (FIFO).
+-- remaining_value disimpan supaya pemakaian sebagian tidak kena selisih pembulatan
+CREATE TABLE cost_layers (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    movement_id INTEGER NOT NULL REFERENCES stock_movements(id) ON DELETE RESTRICT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    remaining_quantity INTEGER NOT NULL CHECK (remaining_quantity >= 0),
+    unit_cost DECIMAL(15,2) NOT NULL CHECK (unit_cost >= 0),
+    remaining_value DECIMAL(15,2) NOT NULL CHECK (remaining_value >= 0),
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (remaining_quantity <= quantity)
+);
+
+CREATE INDEX idx_cost_layers_open ON cost_layers(item_id, received_at, id) WHERE remaining_quantity > 0;
+
+-- =====================================================
+-- TABLE: stock_transfers
+-- =====================================================
+CREATE TABLE stock_transfers (
+    id SERIAL PRIMARY KEY,
+    source_rack_id INTEGER NOT NULL REFERENCES racks(id) ON DELETE RESTRICT,
+    destination_rack_id INTEGER NOT NULL REFERENCES racks(id) ON DELETE RESTRICT,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'in_transit', 'partially_received', 'received', 'closed', 'cancelled')),
+    notes TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    shipped_by INTEGER REFERENCES users(id),
+    shipped_at TIMESTAMP,
+    received_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (source_rack_id <> destination_rack_id)
+);
+
+CREATE INDEX idx_stock_transfers_status ON stock_transfers(status);
+CREATE INDEX idx_stock_transfers_source_rack_id ON stock_transfers(source_rack_id);
+CREATE INDEX idx_stock_transfers_destination_rack_id ON stock_transfers(destination_rack_id);
+
+-- =====================================================
+-- TABLE: stock_transfer_items
+-- =====================================================
+CREATE TABLE stock_transfer_items (
+    id SERIAL PRIMARY KEY,
+    transfer_id INTEGER NOT NULL REFERENCES stock_transfers(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    received_quantity INTEGER NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    UNIQUE (transfer_id, item_id),
+    CHECK (received_quantity <= quantity)
+);
+
+CREATE INDEX idx_stock_transfer_items_transfer_id ON stock_transfer_items(transfer_id);
+
+-- =====================================================
+-- TABLE: purchase_orders
+-- =====================================================
+CREATE TABLE purchase_orders (
+    id SERIAL PRIMARY KEY,
+    po_number VARCHAR(50) UNIQUE NOT NULL,
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(id) ON DELETE RESTRICT,
+    warehouse_id INTEGER NOT NULL REFERENCES warehouses(id) ON DELETE RESTRICT, -- gudang tujuan
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'approved', 'partially_received', 'closed', 'cancelled')),
+    order_date TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expected_date TIMESTAMP,
+    total_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (total_amount >= 0),
+    notes TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    approved_by INTEGER REFERENCES users(id),
+    approved_at TIMESTAMP,
+    closed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_purchase_orders_status ON purchase_orders(status);
+CREATE INDEX idx_purchase_orders_supplier_id ON purchase_orders(supplier_id);
+CREATE INDEX idx_purchase_orders_warehouse_id ON purchase_orders(warehouse_id);
+
+-- =====================================================
+-- TABLE: purchase_order_items
+-- =====================================================
+CREATE TABLE purchase_order_items (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INTEGER NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    received_quantity INTEGER NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    unit_cost DECIMAL(15,2) NOT NULL CHECK (unit_cost >= 0),
+    subtotal DECIMAL(15,2) NOT NULL CHECK (subtotal >= 0),
+    UNIQUE (purchase_order_id, item_id),
+    CHECK (received_quantity <= quantity)
+);
+
+CREATE INDEX idx_purchase_order_items_purchase_order_id ON purchase_order_items(purchase_order_id);
+
+-- =====================================================
+-- TABLE: goods_receipts
+-- =====================================================
+-- satu PO bisa diterima beberapa kali (parsial)
+CREATE TABLE goods_receipts (
+    id SERIAL PRIMARY KEY,
+    grn_number VARCHAR(50) UNIQUE NOT NULL,
+    purchase_order_id INTEGER NOT NULL REFERENCES purchase_orders(id) ON DELETE RESTRICT,
+    rack_id INTEGER NOT NULL REFERENCES racks(id) ON DELETE RESTRICT,
+    received_date TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    total_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (total_amount >= 0),
+    notes TEXT,
+    received_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_goods_receipts_purchase_order_id ON goods_receipts(purchase_order_id);
+
+-- =====================================================
+-- TABLE: goods_receipt_items
+-- =====================================================
+CREATE TABLE goods_receipt_items (
+    id SERIAL PRIMARY KEY,
+    goods_receipt_id INTEGER NOT NULL REFERENCES goods_receipts(id) ON DELETE CASCADE,
+    purchase_order_item_id INTEGER NOT NULL REFERENCES purchase_order_items(id) ON DELETE RESTRICT,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    returned_quantity INTEGER NOT NULL DEFAULT 0 CHECK (returned_quantity >= 0), -- diretur ke supplier
+    unit_cost DECIMAL(15,2) NOT NULL CHECK (unit_cost >= 0),
+    subtotal DECIMAL(15,2) NOT NULL CHECK (subtotal >= 0),
+    CHECK (returned_quantity <= quantity)
+);
+
+CREATE INDEX idx_goods_receipt_items_goods_receipt_id ON goods_receipt_items(goods_receipt_id);
+
+-- =====================================================
+-- TABLE: supplier_returns
+-- =====================================================
+-- retur barang ke supplier, credit_amount = nilai kredit yang diharapkan dari supplier
+CREATE TABLE supplier_returns (
+    id SERIAL PRIMARY KEY,
+    return_number VARCHAR(50) UNIQUE NOT NULL,
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(id) ON DELETE RESTRICT,
+    goods_receipt_id INTEGER REFERENCES goods_receipts(id) ON DELETE RESTRICT,
+    rack_id INTEGER NOT NULL REFERENCES racks(id) ON DELETE RESTRICT, -- rak asal barang
+    return_date TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reason TEXT,
+    credit_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (credit_amount >= 0),
+    credited_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (credited_amount >= 0),
+    credit_status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (credit_status IN ('pending', 'partially_credited', 'credited')),
+    notes TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (credited_amount <= credit_amount)
+);
+
+CREATE INDEX idx_supplier_returns_supplier_id ON supplier_returns(supplier_id);
+CREATE INDEX idx_supplier_returns_goods_receipt_id ON supplier_returns(goods_receipt_id);
+CREATE INDEX idx_supplier_returns_credit_status ON supplier_returns(credit_status);
+
+-- =====================================================
+-- TABLE: supplier_return_items
+-- =====================================================
+CREATE TABLE supplier_return_items (
+    id SERIAL PRIMARY KEY,
+    supplier_return_id INTEGER NOT NULL REFERENCES supplier_returns(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE RESTRICT,
+    goods_receipt_item_id INTEGER REFERENCES goods_receipt_items(id) ON DELETE RESTRICT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_cost DECIMAL(15,2) NOT NULL CHECK (unit_cost >= 0),
+    credit_amount DECIMAL(15,2) NOT NULL CHECK (credit_amount >= 0),
+    UNIQUE (supplier_return_id, item_id)
+);
+
+CREATE INDEX idx_supplier_return_items_supplier_return_id ON supplier_return_items(supplier_return_id);
+
+-- =====================================================
+-- TABLE: supplier_credits
+-- =====================================================
+-- kredit / nota kredit yang sudah diterima dari supplier atas retur
+CREATE TABLE supplier_credits (
+    id SERIAL PRIMARY KEY,
+    supplier_return_id INTEGER NOT NULL REFERENCES supplier_returns(id) ON DELETE RESTRICT,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    reference VARCHAR(100), -- nomor nota kredit supplier
+    credited_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    notes TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_supplier_credits_supplier_return_id ON supplier_credits(supplier_return_id);
+
+-- =====================================================
+-- TABLE: notifications
+-- =====================================================
+-- notifikasi sistem, low_stock dibuat oleh checker di background
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    type VARCHAR(30) NOT NULL CHECK (type IN ('low_stock')),
+    item_id INTEGER REFERENCES items(id) ON DELETE CASCADE,
+    movement_id INTEGER REFERENCES stock_movements(id) ON DELETE SET NULL, -- mutasi yang membuat stok turun
+    message TEXT NOT NULL,
+    stock INTEGER,
+    minimum_stock INTEGER,
+    is_read BOOLEAN NOT NULL DEFAULT false,
+    read_by INTEGER REFERENCES users(id),
+    read_at TIMESTAMP,
+    resolved_at TIMESTAMP, -- stok sudah kembali di atas minimum
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_created_at ON notifications(created_at);
+CREATE INDEX idx_notifications_is_read ON notifications(is_read);
+-- satu notifikasi low_stock terbuka per item
+CREATE UNIQUE INDEX idx_notifications_open_low_stock ON notifications(item_id) WHERE type = 'low_stock' AND resolved_at IS NULL;
+
 -- =====================================================
 -- TRIGGERS
 -- =====================================================
@@ -197,6 +731,9 @@ $$ language 'plpgsql';
 CREATE TRIGGER update_users_updated_at BEFORE UPDATE ON users
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
+CREATE TRIGGER update_tax_rates_updated_at BEFORE UPDATE ON tax_rates
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
 CREATE TRIGGER update_categories_updated_at BEFORE UPDATE ON categories
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
@@ -209,6 +746,36 @@ CREATE TRIGGER update_racks_updated_at BEFORE UPDATE ON racks
 CREATE TRIGGER update_items_updated_at BEFORE UPDATE ON items
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
+CREATE TRIGGER update_item_locations_updated_at BEFORE UPDATE ON item_locations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_stock_transfers_updated_at BEFORE UPDATE ON stock_transfers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_customers_updated_at BEFORE UPDATE ON customers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_suppliers_updated_at BEFORE UPDATE ON suppliers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_item_suppliers_updated_at BEFORE UPDATE ON item_suppliers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_purchase_orders_updated_at BEFORE UPDATE ON purchase_orders
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_supplier_returns_updated_at BEFORE UPDATE ON supplier_returns
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_price_lists_updated_at BEFORE UPDATE ON price_lists
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_price_list_items_updated_at BEFORE UPDATE ON price_list_items
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_promotions_updated_at BEFORE UPDATE ON promotions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
 CREATE TRIGGER update_sales_updated_at BEFORE UPDATE ON sales
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
@@ -258,9 +825,34 @@ GROUP BY s.id, s.invoice_number, s.sale_date, s.customer_name,
 -- =====================================================
 COMMENT ON TABLE users IS 'Tabel untuk menyimpan data pengguna sistem';
 COMMENT ON TABLE sessions IS 'Tabel untuk menyimpan session token pengguna';
+COMMENT ON TABLE tax_rates IS 'Tabel untuk master tarif pajak';
 COMMENT ON TABLE categories IS 'Tabel untuk kategori barang';
 COMMENT ON TABLE warehouses IS 'Tabel untuk gudang penyimpanan';
 COMMENT ON TABLE racks IS 'Tabel untuk rak penyimpanan di gudang';
 COMMENT ON TABLE items IS 'Tabel untuk barang/produk';
+COMMENT ON TABLE customers IS 'Tabel untuk data pelanggan';
+COMMENT ON TABLE price_lists IS 'Tabel untuk daftar harga (retail, grosir, reseller)';
+COMMENT ON TABLE price_list_items IS 'Tabel untuk harga item per daftar harga dan qty minimum';
+COMMENT ON TABLE suppliers IS 'Tabel untuk data supplier';
+COMMENT ON TABLE item_suppliers IS 'Tabel untuk relasi item dan supplier';
+COMMENT ON TABLE item_price_history IS 'Tabel untuk riwayat dan jadwal perubahan harga / cost item';
+COMMENT ON TABLE promotions IS 'Tabel untuk promo dan aturan diskon';
 COMMENT ON TABLE sales IS 'Tabel untuk transaksi penjualan';
-COMMENT ON TABLE sale_items IS 'Tabel untuk detail item penjualan';
\ No newline at end of file
+COMMENT ON TABLE sale_items IS 'Tabel untuk detail item penjualan';
+COMMENT ON TABLE item_locations IS 'Tabel untuk stok barang per rak';
+COMMENT ON TABLE number_sequences IS 'Tabel untuk nomor urut dokumen per prefix dan periode';
+COMMENT ON TABLE sale_payments IS 'Tabel untuk pembayaran penjualan (bisa sebagian/berkali-kali)';
+COMMENT ON TABLE sale_returns IS 'Tabel untuk retur penjualan dan refund';
+COMMENT ON TABLE sale_return_items IS 'Tabel untuk detail item retur penjualan';
+COMMENT ON TABLE stock_movements IS 'Tabel untuk riwayat mutasi stok barang';
+COMMENT ON TABLE cost_layers IS 'Tabel untuk lapisan biaya persediaan (FIFO)';
+COMMENT ON TABLE stock_transfers IS 'Tabel untuk dokumen transfer stok antar rak/gudang';
+COMMENT ON TABLE stock_transfer_items IS 'Tabel untuk detail item transfer stok';
+COMMENT ON TABLE purchase_orders IS 'Tabel untuk purchase order ke supplier';
+COMMENT ON TABLE purchase_order_items IS 'Tabel untuk detail item purchase order';
+COMMENT ON TABLE goods_receipts IS 'Tabel untuk penerimaan barang (GRN) dari purchase order';
+COMMENT ON TABLE goods_receipt_items IS 'Tabel untuk detail item penerimaan barang';
+COMMENT ON TABLE supplier_returns IS 'Tabel untuk retur barang ke supplier';
+COMMENT ON TABLE supplier_return_items IS 'Tabel untuk detail item retur ke supplier';
+COMMENT ON TABLE supplier_credits IS 'Tabel untuk kredit yang diterima dari supplier atas retur';
+COMMENT ON TABLE notifications IS 'Tabel untuk notifikasi sistem (stok di bawah minimum)';
\ No newline at end of file
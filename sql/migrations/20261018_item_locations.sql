@@ -0,0 +1,72 @@
+-- =====================================================
+-- MIGRATION: stok per rak (item_locations)
+-- =====================================================
+-- untuk database yang dibuat sebelum item_locations ada. stok lama di items.stock
+-- dipindah ke rak utama item (items.rack_id). setelah ini items.stock dihitung ulang
+-- dari SUM(item_locations.quantity), jadi stok yang tidak punya rak akan hilang.
+--
+-- migrasi dibatalkan kalau masih ada item dengan stok tapi tanpa rak.
+-- isi rack_id item-item tersebut dulu, lalu jalankan ulang file ini.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS item_locations (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    rack_id INTEGER NOT NULL REFERENCES racks(id) ON DELETE RESTRICT,
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (item_id, rack_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_locations_rack_id ON item_locations(rack_id);
+
+DROP TRIGGER IF EXISTS update_item_locations_updated_at ON item_locations;
+CREATE TRIGGER update_item_locations_updated_at BEFORE UPDATE ON item_locations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE item_locations IS 'Tabel untuk stok barang per rak';
+
+-- item dengan stok tapi tanpa rak tidak bisa dipindah, laporkan semua lalu batalkan
+DO $$
+DECLARE
+    missing TEXT;
+BEGIN
+    SELECT string_agg(format('%s (id %s, stock %s)', sku, id, stock), ', ' ORDER BY id)
+    INTO missing
+    FROM items
+    WHERE stock > 0 AND rack_id IS NULL;
+
+    IF missing IS NOT NULL THEN
+        RAISE EXCEPTION 'items with stock but no rack_id, set their rack first: %', missing;
+    END IF;
+END $$;
+
+-- item yang sudah punya lokasi tidak disentuh, migrasi aman dijalankan ulang
+INSERT INTO item_locations (item_id, rack_id, quantity)
+SELECT i.id, i.rack_id, i.stock
+FROM items i
+WHERE i.stock > 0
+  AND NOT EXISTS (SELECT 1 FROM item_locations l WHERE l.item_id = i.id);
+
+-- pastikan items.stock sama dengan total per rak sebelum commit
+DO $$
+DECLARE
+    mismatch TEXT;
+BEGIN
+    SELECT string_agg(format('%s (stock %s, racks %s)', i.sku, i.stock, COALESCE(l.total, 0)), ', ' ORDER BY i.id)
+    INTO mismatch
+    FROM items i
+    LEFT JOIN (
+        SELECT item_id, SUM(quantity) AS total
+        FROM item_locations
+        GROUP BY item_id
+    ) l ON l.item_id = i.id
+    WHERE i.stock <> COALESCE(l.total, 0);
+
+    IF mismatch IS NOT NULL THEN
+        RAISE EXCEPTION 'items.stock does not match item_locations: %', mismatch;
+    END IF;
+END $$;
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- =====================================================
+-- MIGRATION: status closed untuk stock_transfers
+-- =====================================================
+-- transfer yang tidak diterima penuh bisa ditutup (closed),
+-- untuk database yang dibuat dari db.sql sebelum status ini ada
+BEGIN;
+
+ALTER TABLE stock_transfers DROP CONSTRAINT IF EXISTS stock_transfers_status_check;
+ALTER TABLE stock_transfers ADD CONSTRAINT stock_transfers_status_check
+    CHECK (status IN ('draft', 'in_transit', 'partially_received', 'received', 'closed', 'cancelled'));
+
+COMMIT;
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"errors"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -13,6 +14,22 @@ type Configuration struct {
 	Limit    string
 	Debug    bool
 	DB       DatabaseCofig
+
+	// pattern nomor invoice, lihat utils.NewDocumentNumber
+	InvoicePattern string
+	// pattern nomor purchase order & penerimaan barang
+	PurchaseOrderPattern string
+	GoodsReceiptPattern  string
+	// pattern nomor retur ke supplier
+	SupplierReturnPattern string
+
+	// interval pengecekan perubahan harga terjadwal
+	PriceSchedulerInterval time.Duration
+	// interval pengecekan stok di bawah minimum
+	LowStockCheckInterval time.Duration
+
+	// harga pokok persediaan: fifo (default) atau average
+	CostingMethod string
 }
 
 type DatabaseCofig struct {
@@ -35,6 +52,42 @@ func ReadConfigurationEnv() (Configuration, error) {
 
 	viper.AutomaticEnv()
 
+	invoicePattern := viper.GetString("INVOICE_PATTERN")
+	if invoicePattern == "" {
+		// tanpa {WAREHOUSE} karena warehouse_id sale opsional
+		invoicePattern = "INV/{YYYYMM}/{SEQ:5}"
+	}
+
+	purchaseOrderPattern := viper.GetString("PURCHASE_ORDER_PATTERN")
+	if purchaseOrderPattern == "" {
+		purchaseOrderPattern = "PO/{WAREHOUSE}/{YYYYMM}/{SEQ:5}"
+	}
+
+	goodsReceiptPattern := viper.GetString("GOODS_RECEIPT_PATTERN")
+	if goodsReceiptPattern == "" {
+		goodsReceiptPattern = "GRN/{WAREHOUSE}/{YYYYMM}/{SEQ:5}"
+	}
+
+	supplierReturnPattern := viper.GetString("SUPPLIER_RETURN_PATTERN")
+	if supplierReturnPattern == "" {
+		supplierReturnPattern = "RTS/{WAREHOUSE}/{YYYYMM}/{SEQ:5}"
+	}
+
+	priceSchedulerInterval := viper.GetDuration("PRICE_SCHEDULER_INTERVAL")
+	if priceSchedulerInterval <= 0 {
+		priceSchedulerInterval = time.Minute
+	}
+
+	lowStockCheckInterval := viper.GetDuration("LOW_STOCK_CHECK_INTERVAL")
+	if lowStockCheckInterval <= 0 {
+		lowStockCheckInterval = time.Minute
+	}
+
+	costingMethod := viper.GetString("COSTING_METHOD")
+	if costingMethod != "average" {
+		costingMethod = "fifo"
+	}
+
 	return Configuration{
 		AppName:  viper.GetString("APP_NAME"),
 		Port:     viper.GetString("PORT"),
@@ -49,5 +102,13 @@ func ReadConfigurationEnv() (Configuration, error) {
 			Port:     viper.GetString("DATABASE_PORT"),
 			MaxConn:  viper.GetInt32("DATABASE_MAX_CONN"),
 		},
+		InvoicePattern:        invoicePattern,
+		PurchaseOrderPattern:  purchaseOrderPattern,
+		GoodsReceiptPattern:   goodsReceiptPattern,
+		SupplierReturnPattern: supplierReturnPattern,
+
+		PriceSchedulerInterval: priceSchedulerInterval,
+		LowStockCheckInterval:  lowStockCheckInterval,
+		CostingMethod:          costingMethod,
 	}, nil
 }
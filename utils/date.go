@@ -0,0 +1,14 @@
+package utils
+
+import "time"
+
+const DateLayout = "2006-01-02"
+
+// ParseDate membaca tanggal YYYY-MM-DD (zona waktu lokal), string kosong = def
+func ParseDate(s string, def time.Time) (time.Time, error) {
+	if s == "" {
+		return def, nil
+	}
+
+	return time.ParseInLocation(DateLayout, s, time.Local)
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// token yang didukung pattern nomor dokumen, contoh: INV/{WAREHOUSE}/{YYYYMM}/{SEQ:5}
+//
+//	{WAREHOUSE}  kode gudang
+//	{YYYY} {YY} {MM} {DD} {YYYYMM} {YYYYMMDD}  tanggal dokumen
+//	{SEQ:n}  nomor urut, di-pad nol sepanjang n digit
+var seqToken = regexp.MustCompile(`\{SEQ(?::(\d+))?\}`)
+
+type DocumentNumber struct {
+	Prefix string // pattern yang sudah dirender tanpa token SEQ
+	Period string // periode reset sequence, kosong berarti tidak pernah reset
+
+	rendered string
+	width    int
+}
+
+func NewDocumentNumber(pattern, warehouseCode string, at time.Time) (*DocumentNumber, error) {
+	matches := seqToken.FindAllStringSubmatch(pattern, -1)
+	if len(matches) != 1 {
+		return nil, errors.New("document number pattern must contain exactly one {SEQ} token")
+	}
+
+	if PatternNeedsWarehouse(pattern) && warehouseCode == "" {
+		return nil, errors.New("warehouse is required to generate document number")
+	}
+
+	width := 0
+	if matches[0][1] != "" {
+		width, _ = strconv.Atoi(matches[0][1])
+	}
+
+	rendered := strings.NewReplacer(
+		"{WAREHOUSE}", warehouseCode,
+		"{YYYYMMDD}", at.Format("20060102"),
+		"{YYYYMM}", at.Format("200601"),
+		"{YYYY}", at.Format("2006"),
+		"{YY}", at.Format("06"),
+		"{MM}", at.Format("01"),
+		"{DD}", at.Format("02"),
+	).Replace(pattern)
+
+	// periode ikut token tanggal paling detail di pattern
+	period := ""
+	switch {
+	case strings.Contains(pattern, "{DD}") || strings.Contains(pattern, "{YYYYMMDD}"):
+		period = at.Format("20060102")
+	case strings.Contains(pattern, "{MM}") || strings.Contains(pattern, "{YYYYMM}"):
+		period = at.Format("200601")
+	case strings.Contains(pattern, "{YYYY}") || strings.Contains(pattern, "{YY}"):
+		period = at.Format("2006")
+	}
+
+	return &DocumentNumber{
+		Prefix:   seqToken.ReplaceAllString(rendered, ""),
+		Period:   period,
+		rendered: rendered,
+		width:    width,
+	}, nil
+}
+
+// PatternNeedsWarehouse true kalau pattern memakai kode gudang,
+// dokumen tanpa gudang tidak bisa diberi nomor dari pattern ini
+func PatternNeedsWarehouse(pattern string) bool {
+	return strings.Contains(pattern, "{WAREHOUSE}")
+}
+
+func (d *DocumentNumber) Format(seq int) string {
+	return seqToken.ReplaceAllString(d.rendered, fmt.Sprintf("%0*d", d.width, seq))
+}